@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"enterprise-project1-mediahub/mediahub/services"
+	"enterprise-project1-mediahub/mediahub/services/shorturl"
+	"enterprise-project1-mediahub/mediahub/services/shorturl/proto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AdminStats 查看全局统计
+func (c *Controller) AdminStats(ctx *gin.Context) {
+	stats, err := c.mediaData.Stats()
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"media": stats})
+}
+
+// AdminPurgeShortUrl 下架任意短链，路径参数 type 为 p（公共短链）或 u（用户短链）
+// 短链服务会再次校验操作用户的权限
+func (c *Controller) AdminPurgeShortUrl(ctx *gin.Context) {
+	typ := ctx.Param("type")
+	if typ != "p" && typ != "u" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "短链类型无效"})
+		return
+	}
+
+	shortPool := shorturl.NewShortUrlClientPool()
+	clientConn, err := shortPool.Get()
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "下架失败"})
+		return
+	}
+	defer shortPool.Put(clientConn)
+
+	client := proto.NewShortUrlClient(clientConn)
+	outGoingCtx := services.AppendBearerTokenToContext(context.Background(), c.config.DependOn.ShortUrl.AccessToken)
+	outGoingCtx = services.AppendOperatorToContext(outGoingCtx, auth.UserID(ctx))
+	outGoingCtx = services.AppendClientToContext(outGoingCtx, ctx.ClientIP(), ctx.Request.UserAgent())
+	// 审计记录的对象类型与短链服务一致，使用短链所在的表名
+	targetType := "url_map_user"
+	if typ == "p" {
+		targetType = "url_map"
+	}
+	rs, err := client.PurgeShortUrl(outGoingCtx, &proto.PurgeRequest{Key: ctx.Param("key"), IsPublic: typ == "p"})
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		c.recorder.Record(audit.FromGin(ctx, audit.ActionLinkPurge, audit.OutcomeFailure).Target(targetType, ctx.Param("key")).WithDetail(err.Error()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "下架失败"})
+		return
+	}
+	c.record(ctx, audit.ActionLinkPurge, targetType, ctx.Param("key"), rs.Url)
+	ctx.JSON(http.StatusOK, gin.H{"short_url": rs.Url, "msg": "已下架"})
+}
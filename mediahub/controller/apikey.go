@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/utils"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// apiKeyScopes 创建 API Key 时可选的权限预设，full 不限制权限范围
+var apiKeyScopes = map[string][]string{
+	"full":   nil,
+	"upload": {auth.ScopeMediaUpload},
+	"read":   {auth.ScopeMediaRead},
+}
+
+var (
+	errApiKeyScopeInvalid  = errors.New("权限范围无效")
+	errApiKeyScopeExceeded = errors.New("权限范围超出当前凭证的权限")
+)
+
+// apiKeyScopesFor 返回权限预设对应的权限范围
+// 当前凭证的权限受限时（如带 scope 的 JWT），只能创建权限范围不超过自身的 API Key，不能创建不受限的 full 密钥
+// 参数:
+//
+//	p: 当前用户
+//	preset: 权限预设
+//
+// 返回值:
+//
+//	[]string: 权限范围，为nil表示不受限
+//	error: 预设不存在返回 errApiKeyScopeInvalid，超出当前凭证的权限返回 errApiKeyScopeExceeded
+func apiKeyScopesFor(p *auth.Principal, preset string) ([]string, error) {
+	scopes, ok := apiKeyScopes[preset]
+	if !ok {
+		return nil, errApiKeyScopeInvalid
+	}
+	if p.Scopes == nil {
+		return scopes, nil
+	}
+	if scopes == nil || !p.HasScope(scopes...) {
+		return nil, errApiKeyScopeExceeded
+	}
+	return scopes, nil
+}
+
+// apiKeyPrincipal 返回可以管理 API Key 的当前用户，失败时已写回响应
+// API Key 本身不能用来创建或撤销 API Key，防止泄露的密钥自我续命
+func apiKeyPrincipal(ctx *gin.Context) *auth.Principal {
+	p := auth.GetPrincipal(ctx)
+	if p == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+		return nil
+	}
+	if p.Method == auth.MethodApiKey {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "不能使用 API Key 管理 API Key"})
+		return nil
+	}
+	return p
+}
+
+// ApiKeyCreate 创建 API Key，完整密钥只在本次响应中返回
+// 表单字段：name 名称，scope 权限预设（full、upload、read，默认 full），expire_days 有效天数（0表示永不过期）
+// 权限受限的凭证只能创建权限范围不超过自身的 API Key
+func (c *Controller) ApiKeyCreate(ctx *gin.Context) {
+	p := apiKeyPrincipal(ctx)
+	if p == nil {
+		return
+	}
+	scope := ctx.DefaultPostForm("scope", "full")
+	scopes, err := apiKeyScopesFor(p, scope)
+	if errors.Is(err, errApiKeyScopeExceeded) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	expireDays, err := strconv.Atoi(ctx.DefaultPostForm("expire_days", "0"))
+	if err != nil || expireDays < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "有效天数无效"})
+		return
+	}
+
+	key, display, err := utils.GenerateApiKey()
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	now := time.Now().Unix()
+	e := &data.ApiKeyEntity{
+		UserID:   p.UserID,
+		Name:     ctx.PostForm("name"),
+		Display:  display,
+		KeyHash:  utils.HashApiKey(key),
+		Scopes:   scopes,
+		CreateAt: now,
+	}
+	if expireDays > 0 {
+		e.ExpireAt = now + int64(expireDays)*86400
+	}
+	if err = c.apiKeyData.Create(e); err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	c.record(ctx, audit.ActionApiKeyCreate, "api_key", strconv.FormatInt(e.ID, 10), fmt.Sprintf("name=%s scope=%s expire_at=%d", e.Name, scope, e.ExpireAt))
+	ctx.JSON(http.StatusOK, gin.H{
+		"key":     key,
+		"api_key": e,
+		"msg":     "创建成功，请妥善保存密钥，之后无法再次查看",
+	})
+}
+
+// ApiKeyList 查询当前用户的 API Key
+func (c *Controller) ApiKeyList(ctx *gin.Context) {
+	p := apiKeyPrincipal(ctx)
+	if p == nil {
+		return
+	}
+	list, err := c.apiKeyData.ListByUser(p.UserID)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"list": list})
+}
+
+// ApiKeyRevoke 撤销当前用户的 API Key
+func (c *Controller) ApiKeyRevoke(ctx *gin.Context) {
+	p := apiKeyPrincipal(ctx)
+	if p == nil {
+		return
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数检查失败"})
+		return
+	}
+	ok, err := c.apiKeyData.Revoke(id, p.UserID, time.Now().Unix())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "API Key 不存在或已撤销"})
+		return
+	}
+	c.record(ctx, audit.ActionApiKeyRevoke, "api_key", strconv.FormatInt(id, 10), "")
+	ctx.JSON(http.StatusOK, gin.H{"msg": "已撤销"})
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"testing"
+)
+
+func TestApiKeyScopesFor(t *testing.T) {
+	// 交互式登录不受限，可以创建任意预设的 API Key
+	p := &auth.Principal{Method: auth.MethodSession}
+	if scopes, err := apiKeyScopesFor(p, "full"); err != nil || scopes != nil {
+		t.Errorf("unrestricted full = %v, %v", scopes, err)
+	}
+	if _, err := apiKeyScopesFor(p, "admin"); err != errApiKeyScopeInvalid {
+		t.Errorf("unknown preset err = %v", err)
+	}
+
+	// 只有上传权限的 JWT 不能创建 full 或 read 密钥，也不能得到不受限的权限范围
+	p = &auth.Principal{Method: auth.MethodJwt, Scopes: []string{auth.ScopeMediaUpload}}
+	for _, preset := range []string{"full", "read"} {
+		if scopes, err := apiKeyScopesFor(p, preset); err != errApiKeyScopeExceeded || scopes != nil {
+			t.Errorf("restricted %s = %v, %v", preset, scopes, err)
+		}
+	}
+	if scopes, err := apiKeyScopesFor(p, "upload"); err != nil || len(scopes) != 1 || scopes[0] != auth.ScopeMediaUpload {
+		t.Errorf("restricted upload = %v, %v", scopes, err)
+	}
+
+	// 通配权限覆盖预设中的全部权限
+	p.Scopes = []string{"media:*"}
+	if scopes, err := apiKeyScopesFor(p, "read"); err != nil || scopes == nil {
+		t.Errorf("wildcard read = %v, %v", scopes, err)
+	}
+	if _, err := apiKeyScopesFor(p, "full"); err != errApiKeyScopeExceeded {
+		t.Errorf("wildcard full err = %v", err)
+	}
+}
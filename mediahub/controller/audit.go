@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"encoding/json"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// record 写入成功操作的审计记录，操作者与客户端信息取自请求
+func (c *Controller) record(ctx *gin.Context, action, targetType, targetID, detail string) {
+	c.recorder.Record(audit.FromGin(ctx, action, audit.OutcomeSuccess).Target(targetType, targetID).WithDetail(detail))
+}
+
+// auditQuery 读取查询参数中的审计查询条件，参数无效时已写回响应
+// 查询参数：actor_type、actor_id、target_type、target_id、action、from、to（时间戳）、before_id、limit
+func auditQuery(ctx *gin.Context) (*audit.Query, bool) {
+	q := &audit.Query{
+		ActorType:  ctx.Query("actor_type"),
+		TargetType: ctx.Query("target_type"),
+		TargetID:   ctx.Query("target_id"),
+		Action:     ctx.Query("action"),
+	}
+	ints := []struct {
+		name string
+		dest *int64
+	}{
+		{"actor_id", &q.ActorID},
+		{"from", &q.From},
+		{"to", &q.To},
+		{"before_id", &q.BeforeID},
+	}
+	for _, f := range ints {
+		v := ctx.Query(f.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数无效：" + f.name})
+			return nil, false
+		}
+		*f.dest = n
+	}
+	if v := ctx.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数无效：limit"})
+			return nil, false
+		}
+		q.Limit = n
+	}
+	return q, true
+}
+
+// AuditList 分页查询审计记录，按时间倒序
+// 下一页传入本页最后一条记录的ID作为 before_id
+func (c *Controller) AuditList(ctx *gin.Context) {
+	q, ok := auditQuery(ctx)
+	if !ok {
+		return
+	}
+	list, err := c.auditData.Query(q)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	var next int64
+	if len(list) > 0 {
+		next = list[len(list)-1].ID
+	}
+	ctx.JSON(http.StatusOK, gin.H{"list": list, "next_before_id": next})
+}
+
+// AuditExport 按条件导出审计记录，每行一条 JSON（JSONL）
+// 导出本身也会写入审计记录
+func (c *Controller) AuditExport(ctx *gin.Context) {
+	q, ok := auditQuery(ctx)
+	if !ok {
+		return
+	}
+	c.record(ctx, audit.ActionAuditExport, "audit_log", "", ctx.Request.URL.RawQuery)
+
+	filename := fmt.Sprintf("audit-%s.jsonl", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Status(http.StatusOK)
+	enc := json.NewEncoder(ctx.Writer)
+	err := c.auditData.Export(q, func(r *audit.Record) error {
+		return enc.Encode(r)
+	})
+	if err != nil {
+		// 响应头已经发出，只能中断输出，客户端会收到不完整的文件
+		c.log.Error(zerror.NewByErr(err))
+	}
+}
@@ -4,8 +4,14 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
 	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"enterprise-project1-mediahub/mediahub/pkg/constants"
 	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/rbac"
+	"enterprise-project1-mediahub/mediahub/pkg/session"
 	"enterprise-project1-mediahub/mediahub/pkg/storage"
 	"enterprise-project1-mediahub/mediahub/pkg/zerror"
 	"enterprise-project1-mediahub/mediahub/services"
@@ -21,6 +27,9 @@ import (
 	"io"
 	"net/http"
 	"path"
+	"strconv"
+	"sync"
+	"time"
 )
 
 /*
@@ -33,22 +42,157 @@ Go 的 image 标准库默认只支持解码，但不包含具体格式的实现
 */
 
 type Controller struct {
-	sf     storage.StorageFactory
-	log    log.ILogger
-	config *config.Config
+	sf            storage.StorageFactory
+	log           log.ILogger
+	config        *config.Config
+	mediaData     data.IMediaData
+	versionData   data.IMediaVersionData
+	watermarkData data.IWatermarkData
+	apiKeyData    data.IApiKeyData
+	rbacData      data.IRbacData
+	enforcer      *rbac.Enforcer
+	sessions      session.Store
+	cookies       *session.Cookies
+	auditData     data.IAuditData
+	recorder      *audit.Recorder
+
+	defaultMarkOnce sync.Once   // 默认图片水印只加载一次
+	defaultMark     image.Image // 管理员配置的默认图片水印
 }
 
-func NewController(sf storage.StorageFactory, logger log.ILogger, cnf *config.Config) *Controller {
+func NewController(sf storage.StorageFactory, logger log.ILogger, cnf *config.Config, mediaData data.IMediaData, versionData data.IMediaVersionData, watermarkData data.IWatermarkData, apiKeyData data.IApiKeyData, rbacData data.IRbacData, enforcer *rbac.Enforcer, sessions session.Store, cookies *session.Cookies, auditData data.IAuditData, recorder *audit.Recorder) *Controller {
 	return &Controller{
-		sf:     sf,
-		log:    logger,
-		config: cnf,
+		sf:            sf,
+		log:           logger,
+		config:        cnf,
+		mediaData:     mediaData,
+		versionData:   versionData,
+		watermarkData: watermarkData,
+		apiKeyData:    apiKeyData,
+		rbacData:      rbacData,
+		enforcer:      enforcer,
+		sessions:      sessions,
+		cookies:       cookies,
+		auditData:     auditData,
+		recorder:      recorder,
 	}
 }
 
 func (c *Controller) Upload(ctx *gin.Context) {
-	userId := ctx.GetInt64("user_id")
-	userName := ctx.PostForm("user_name") // 自动从form表单获取数据
+	userId := auth.UserID(ctx)
+	userName := auth.UserName(ctx)
+	if userName == "" {
+		userName = ctx.PostForm("user_name") // 自动从form表单获取数据
+	}
+	// 私有媒体只对登录用户开放，公共上传没有属主，无法撤销和续签
+	private := ctx.PostForm("visibility") == "private"
+	if private && userId == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"error": "私有上传需要登录",
+		})
+		return
+	}
+	content, ext, ok := c.readImage(ctx)
+	if !ok {
+		return
+	}
+
+	s := c.sf.CreateStorage()
+	content, originalPath, optimized, err := c.prepareContent(s, content, ext, userId, private)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	md5Digest := calMD5Digest(content)
+	filePath, url, err := c.putObject(s, content, md5Digest, ext, userId, private)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+
+	now := time.Now().Unix()
+	media := &data.MediaEntity{
+		UserID:       userId,
+		Md5:          fmt.Sprintf("%x", md5Digest),
+		ObjectPath:   filePath,
+		OriginalPath: originalPath,
+		Url:          url,
+		Size:         int64(optimized.Size),
+		SavedBytes:   int64(optimized.SavedBytes()),
+		Visibility:   constants.VISIBILITY_PUBLIC,
+		Version:      1,
+		CreateAt:     now,
+		UpdateAt:     now,
+	}
+	if private {
+		media.Visibility = constants.VISIBILITY_PRIVATE
+	}
+	_, err = c.mediaData.Create(media)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	// 首次上传即为第1个版本
+	err = c.versionData.Create(&data.MediaVersionEntity{
+		MediaID:      media.ID,
+		Md5:          media.Md5,
+		ObjectPath:   media.ObjectPath,
+		OriginalPath: media.OriginalPath,
+		Url:          media.Url,
+		Size:         media.Size,
+		SavedBytes:   media.SavedBytes,
+		CreateAt:     now,
+	})
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+
+	// 私有媒体的短链指向 mediahub 的访问路由，每次访问时重新签发限时地址
+	target := url
+	if private {
+		target = c.accessUrl(media)
+	}
+	shortUrl, err := c.getShortUrl(ctx, target, userId)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	err = c.mediaData.UpdateShortUrl(media.ID, shortUrl, now)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+	}
+	c.record(ctx, audit.ActionMediaUpload, "media", strconv.FormatInt(media.ID, 10),
+		fmt.Sprintf("visibility=%d short_url=%s", media.Visibility, shortUrl))
+
+	rs := gin.H{
+		"url":       shortUrl,
+		"media_id":  media.ID,
+		"user_name": userName,
+		"optimize":  optimizeSummary(optimized),
+		"msg":       "上传成功",
+	}
+	if private {
+		signedUrl, expireAt, err := c.signedUrl(s, media)
+		if err != nil {
+			c.log.Error(zerror.NewByErr(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		rs["signed_url"] = signedUrl
+		rs["expire_at"] = expireAt
+	}
+	ctx.JSON(http.StatusOK, rs)
+}
+
+// readImage 读取表单中的 file 字段并校验是否为图片
+// 返回文件内容和扩展名，校验失败时已写回响应，ok 为 false
+func (c *Controller) readImage(ctx *gin.Context) (content []byte, ext string, ok bool) {
 	//ctx.Request.FormValue()	FormValue 会先从查询参数（URL 中的 ?key=value）中查找键，如果找不到再从表单数据中查找。
 	// 它可以处理 GET 和 POST 请求中的表单数据和查询参数。
 	// 如果没有找到对应的键，返回空字符串 ""。
@@ -58,14 +202,14 @@ func (c *Controller) Upload(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": "formFile",
 		})
-		return
+		return nil, "", false
 	}
 	file, err := fileHeader.Open()
 	defer file.Close()
 	if err != nil {
 		c.log.Error(zerror.NewByErr(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{})
-		return
+		return nil, "", false
 	}
 
 	/*
@@ -83,11 +227,11 @@ func (c *Controller) Upload(ctx *gin.Context) {
 
 	*/
 
-	content, err := io.ReadAll(file)
+	content, err = io.ReadAll(file)
 	if err != nil {
 		c.log.Error(zerror.NewByErr(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{})
-		return
+		return nil, "", false
 	}
 
 	// io.NopCloser 是 Go 标准库 io 包中的一个 适配器（adapter），它会 包装一个 io.Reader，
@@ -101,28 +245,38 @@ func (c *Controller) Upload(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": "仅支持jpg、png、gif格式",
 		})
+		return nil, "", false
 	}
 	// bytes.NewReader(content) 生成的是 io.Reader，它没有 Close() 方法。
 	// io.NopCloser(...) 将 io.Reader 包装成 io.ReadCloser，这样 isImage 如果接收 io.ReadCloser，也能正常使用。
 
-	md5Digest := calMD5Digest(content)
-	fmt.Printf("%x\n", md5Digest)
-	filename := fmt.Sprintf("%x%s", md5Digest, path.Ext(fileHeader.Filename))
-	filePath := "/public/" + filename
+	return content, path.Ext(fileHeader.Filename), true
+}
+
+// putObject 按内容md5计算对象路径并上传
+// 对象路径由内容决定，同一路径的内容永远不变，替换媒体时写入新路径即可让 CDN 缓存自然失效
+func (c *Controller) putObject(s storage.Storage, content []byte, md5Digest []byte, ext string, userId int64, private bool) (filePath, url string, err error) {
+	filename := fmt.Sprintf("%x%s", md5Digest, ext)
+	filePath = "/public/" + filename
 	if userId != 0 {
 		filePath = fmt.Sprintf("/%d/%s", userId, filename)
 	}
-
-	s := c.sf.CreateStorage()
-	url, err := s.Upload(io.NopCloser(bytes.NewReader(content)), md5Digest, filePath)
-	if err != nil {
-		c.log.Error(zerror.NewByErr(err))
-		ctx.JSON(http.StatusInternalServerError, gin.H{})
-		return
+	if private {
+		filePath = fmt.Sprintf("/private/%d/%s", userId, filename)
+		err = s.UploadPrivate(io.NopCloser(bytes.NewReader(content)), md5Digest, filePath)
+		return filePath, "", err
 	}
+	url, err = s.Upload(io.NopCloser(bytes.NewReader(content)), md5Digest, filePath)
+	return filePath, url, err
+}
 
+// getShortUrl 调用短链服务为 url 生成短链接
+func (c *Controller) getShortUrl(ctx *gin.Context, url string, userId int64) (string, error) {
 	shortPool := shorturl.NewShortUrlClientPool()
-	clientConn := shortPool.Get()
+	clientConn, err := shortPool.Get()
+	if err != nil {
+		return "", err
+	}
 	defer shortPool.Put(clientConn)
 
 	// 生成短链接
@@ -146,19 +300,34 @@ func (c *Controller) Upload(ctx *gin.Context) {
 	// 加一个拦截器认证参数
 	outGoingCtx := context.Background()
 	outGoingCtx = services.AppendBearerTokenToContext(outGoingCtx, c.config.DependOn.ShortUrl.AccessToken)
+	outGoingCtx = services.AppendClientToContext(outGoingCtx, ctx.ClientIP(), ctx.Request.UserAgent())
 
 	outUrl, err := client.GetShortUrl(outGoingCtx, in)
 	if err != nil {
-		c.log.Error(zerror.NewByErr(err))
-		ctx.JSON(http.StatusInternalServerError, gin.H{})
-		return
+		return "", err
+	}
+	return outUrl.Url, nil
+}
+
+// updateShortUrlTarget 调用短链服务修改短链指向的地址，短链键保持不变
+func (c *Controller) updateShortUrlTarget(ctx *gin.Context, shortUrl, url string, userId int64) error {
+	shortPool := shorturl.NewShortUrlClientPool()
+	clientConn, err := shortPool.Get()
+	if err != nil {
+		return err
 	}
+	defer shortPool.Put(clientConn)
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"url":       outUrl.Url,
-		"user_name": userName,
-		"msg":       "上传成功",
-	})
+	client := proto.NewShortUrlClient(clientConn)
+	in := &proto.UpdateTargetRequest{
+		Key:    path.Base(shortUrl),
+		UserID: userId,
+		Url:    url,
+	}
+	outGoingCtx := services.AppendBearerTokenToContext(context.Background(), c.config.DependOn.ShortUrl.AccessToken)
+	outGoingCtx = services.AppendClientToContext(outGoingCtx, ctx.ClientIP(), ctx.Request.UserAgent())
+	_, err = client.UpdateTarget(outGoingCtx, in)
+	return err
 }
 
 //func isImage(r io.Reader) bool {
@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/storage"
+	"enterprise-project1-mediahub/mediahub/pkg/utils"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// 私有媒体签名相关默认值
+const (
+	signModeCos       = "cos" // 使用 COS 预签名地址
+	defaultSignExpire = 600   // 签名地址默认有效期（秒）
+)
+
+// accessUrl 返回私有媒体的稳定访问地址，短链指向该地址
+// 地址携带只与媒体ID绑定的签名，防止遍历ID获取他人的私有媒体
+func (c *Controller) accessUrl(e *data.MediaEntity) string {
+	sig := utils.HmacSign(c.config.Media.SignSecret, "access", strconv.FormatInt(e.ID, 10))
+	return fmt.Sprintf("%s/api/v1/media/%d/access/%s", c.config.Media.BaseUrl, e.ID, sig)
+}
+
+// signedUrl 为私有媒体签发一个限时访问地址
+// 返回：
+//   - 签名地址
+//   - 过期时间戳
+//   - 错误信息
+func (c *Controller) signedUrl(s storage.Storage, e *data.MediaEntity) (string, int64, error) {
+	expire := c.config.Media.SignExpire
+	if expire <= 0 {
+		expire = defaultSignExpire
+	}
+	expireAt := time.Now().Unix() + int64(expire)
+
+	if c.config.Media.SignMode == signModeCos {
+		url, err := s.SignURL(e.ObjectPath, time.Duration(expire)*time.Second)
+		return url, expireAt, err
+	}
+
+	// hmac 签名包含签名版本，撤销访问时递增版本即可让已发放的地址失效
+	id := strconv.FormatInt(e.ID, 10)
+	exp := strconv.FormatInt(expireAt, 10)
+	sig := utils.HmacSign(c.config.Media.SignSecret, id, strconv.Itoa(e.SignVersion), exp)
+	url := fmt.Sprintf("%s/api/v1/media/%s/download?expires=%s&sig=%s", c.config.Media.BaseUrl, id, exp, sig)
+	return url, expireAt, nil
+}
+
+// getMedia 解析路径参数中的媒体ID并查询媒体记录，失败时直接写回响应
+func (c *Controller) getMedia(ctx *gin.Context) *data.MediaEntity {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数检查失败"})
+		return nil
+	}
+	media, err := c.mediaData.GetByID(id)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return nil
+	}
+	if media == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "媒体不存在"})
+		return nil
+	}
+	return media
+}
+
+// getOwnMedia 在 getMedia 的基础上校验当前登录用户是否为媒体属主
+func (c *Controller) getOwnMedia(ctx *gin.Context) *data.MediaEntity {
+	userId := auth.UserID(ctx)
+	if userId == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+		return nil
+	}
+	media := c.getMedia(ctx)
+	if media == nil {
+		return nil
+	}
+	if media.UserID != userId {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "无权操作该媒体"})
+		return nil
+	}
+	return media
+}
+
+// MediaAccess 私有媒体短链的落地路由
+// 校验访问签名与撤销状态后，每次都重新签发限时地址并重定向
+func (c *Controller) MediaAccess(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if !utils.HmacVerify(c.config.Media.SignSecret, ctx.Param("sig"), "access", id) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "签名无效"})
+		return
+	}
+	media := c.getMedia(ctx)
+	if media == nil {
+		return
+	}
+	if media.IsDeleted() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "媒体不存在"})
+		return
+	}
+	if !media.IsPrivate() {
+		ctx.Redirect(http.StatusFound, media.Url)
+		return
+	}
+	if media.Revoked {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "访问已被撤销"})
+		return
+	}
+
+	url, _, err := c.signedUrl(c.sf.CreateStorage(), media)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	// 签名地址有时效，禁止浏览器和中间代理缓存这次重定向
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Redirect(http.StatusFound, url)
+}
+
+// MediaDownload hmac 模式下的私有媒体下载路由
+// 校验过期时间与签名（含签名版本）后从存储读取对象并输出
+func (c *Controller) MediaDownload(ctx *gin.Context) {
+	expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err != nil || expires < time.Now().Unix() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "链接已过期"})
+		return
+	}
+	media := c.getMedia(ctx)
+	if media == nil {
+		return
+	}
+	if media.IsDeleted() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "媒体不存在"})
+		return
+	}
+	if media.Revoked {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "访问已被撤销"})
+		return
+	}
+	if !utils.HmacVerify(c.config.Media.SignSecret, ctx.Query("sig"), ctx.Param("id"), strconv.Itoa(media.SignVersion), ctx.Query("expires")) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "签名无效"})
+		return
+	}
+
+	r, err := c.sf.CreateStorage().Download(media.ObjectPath)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	defer r.Close()
+
+	ctx.Header("Cache-Control", "private, max-age="+strconv.FormatInt(expires-time.Now().Unix(), 10))
+	ctx.Status(http.StatusOK)
+	ctx.Header("Content-Type", mime.TypeByExtension(path.Ext(media.ObjectPath)))
+	if _, err = io.Copy(ctx.Writer, r); err != nil {
+		c.log.Error(zerror.NewByErr(err))
+	}
+}
+
+// MediaSignedUrl 属主获取私有媒体的新签名地址
+func (c *Controller) MediaSignedUrl(ctx *gin.Context) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	if media.IsDeleted() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "媒体不存在"})
+		return
+	}
+	if !media.IsPrivate() {
+		ctx.JSON(http.StatusOK, gin.H{"url": media.Url})
+		return
+	}
+	url, expireAt, err := c.signedUrl(c.sf.CreateStorage(), media)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"url":       url,
+		"expire_at": expireAt,
+	})
+}
+
+// MediaRevoke 属主撤销私有媒体的访问
+// 短链访问将被拒绝，hmac 模式下已签发的地址立即失效；cos 模式下已签发的预签名地址只能等待过期
+func (c *Controller) MediaRevoke(ctx *gin.Context) {
+	c.setRevoked(ctx, true)
+}
+
+// MediaGrant 属主恢复私有媒体的访问，之前撤销的签名地址不会恢复
+func (c *Controller) MediaGrant(ctx *gin.Context) {
+	c.setRevoked(ctx, false)
+}
+
+func (c *Controller) setRevoked(ctx *gin.Context, revoked bool) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	if !media.IsPrivate() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "仅私有媒体支持撤销访问"})
+		return
+	}
+	err := c.mediaData.SetRevoked(media.ID, revoked, time.Now().Unix())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"msg": "操作成功"})
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/optimize"
+	"enterprise-project1-mediahub/mediahub/pkg/storage"
+	"fmt"
+	"github.com/gin-gonic/gin"
+)
+
+// prepareContent 上传前处理图片内容
+// 公开媒体先加水印，再按配置重新编码优化；私有媒体只有属主能访问，不加水印
+// 返回：
+//   - 最终上传的内容
+//   - 未加水印的原图路径，未加水印时为空
+//   - 优化结果，OriginalSize 为用户上传的原始大小，Size 为最终上传的大小
+//   - 错误信息
+func (c *Controller) prepareContent(s storage.Storage, content []byte, ext string, userId int64, private bool) ([]byte, string, *optimize.Result, error) {
+	var err error
+	uploadSize := len(content)
+	originalPath := ""
+	if !private {
+		content, originalPath, err = c.applyWatermark(s, content, ext, userId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	cnf := c.config.Optimize
+	if !cnf.Enable {
+		return content, originalPath, &optimize.Result{Content: content, OriginalSize: uploadSize, Size: len(content)}, nil
+	}
+	rs, err := optimize.Optimize(content, optimize.Options{
+		JpegQuality:  cnf.JpegQuality,
+		MaxDimension: cnf.MaxDimension,
+		PngQuantize:  cnf.PngQuantize,
+		PngColors:    cnf.PngColors,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	// 节省的字节数与用户上传的原图比较，而不是与加水印后的内容比较
+	rs.OriginalSize = uploadSize
+	return rs.Content, originalPath, rs, nil
+}
+
+// optimizeSummary 上传响应中的优化信息
+func optimizeSummary(rs *optimize.Result) gin.H {
+	percent := 0.0
+	if rs.OriginalSize > 0 {
+		percent = float64(rs.SavedBytes()) * 100 / float64(rs.OriginalSize)
+	}
+	return gin.H{
+		"optimized":     rs.Optimized,
+		"original_size": rs.OriginalSize,
+		"size":          rs.Size,
+		"saved_bytes":   rs.SavedBytes(),
+		"saved_percent": fmt.Sprintf("%.1f", percent),
+	}
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adminRole 内置管理员角色，不能删除
+const adminRole = "admin"
+
+var (
+	roleNamePattern   = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+	permissionPattern = regexp.MustCompile(`^(\*|[a-z0-9_]+:(\*|[a-z0-9_]+))$`)
+)
+
+// RoleList 查询全部角色及其权限
+func (c *Controller) RoleList(ctx *gin.Context) {
+	roles, err := c.rbacData.ListRoles()
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// RoleSave 新增或修改角色，表单字段：description 说明，permissions 权限（可重复）
+// 角色原有的权限会被整体替换
+func (c *Controller) RoleSave(ctx *gin.Context) {
+	name := ctx.Param("name")
+	if !roleNamePattern.MatchString(name) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "角色名称只能包含小写字母、数字、下划线和连字符"})
+		return
+	}
+	perms := ctx.PostFormArray("permissions")
+	for _, p := range perms {
+		if !permissionPattern.MatchString(p) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "权限格式无效：" + p})
+			return
+		}
+	}
+
+	now := time.Now().Unix()
+	role := &data.RoleEntity{
+		Name:        name,
+		Description: ctx.PostForm("description"),
+		Permissions: perms,
+		CreateAt:    now,
+		UpdateAt:    now,
+	}
+	if err := c.rbacData.SaveRole(role); err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	// 角色权限变化影响所有持有该角色的用户
+	c.enforcer.Invalidate(0)
+	c.record(ctx, audit.ActionRoleSave, "role", name, "permissions="+strings.Join(perms, ","))
+	ctx.JSON(http.StatusOK, gin.H{"role": role, "msg": "保存成功"})
+}
+
+// RoleDelete 删除角色，同时撤销所有用户的该角色
+func (c *Controller) RoleDelete(ctx *gin.Context) {
+	name := ctx.Param("name")
+	if name == adminRole {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "不能删除内置管理员角色"})
+		return
+	}
+	ok, err := c.rbacData.DeleteRole(name)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+		return
+	}
+	c.enforcer.Invalidate(0)
+	c.record(ctx, audit.ActionRoleDelete, "role", name, "")
+	ctx.JSON(http.StatusOK, gin.H{"msg": "删除成功"})
+}
+
+// UserRoleList 查询用户的角色与权限
+func (c *Controller) UserRoleList(ctx *gin.Context) {
+	userId, ok := userIdParam(ctx)
+	if !ok {
+		return
+	}
+	roles, err := c.rbacData.UserRoles(userId)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	perms, err := c.rbacData.UserPermissions(userId)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"user_id": userId, "roles": roles, "permissions": perms})
+}
+
+// UserRoleAssign 授予用户角色，表单字段：role 角色名称
+func (c *Controller) UserRoleAssign(ctx *gin.Context) {
+	userId, ok := userIdParam(ctx)
+	if !ok {
+		return
+	}
+	ok, err := c.rbacData.AssignRole(userId, ctx.PostForm("role"), time.Now().Unix())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+		return
+	}
+	c.enforcer.Invalidate(userId)
+	c.record(ctx, audit.ActionRoleAssign, "user", strconv.FormatInt(userId, 10), "role="+ctx.PostForm("role"))
+	ctx.JSON(http.StatusOK, gin.H{"msg": "授权成功"})
+}
+
+// UserRoleUnassign 撤销用户的角色，管理员不能撤销自己的管理员角色，避免无人可以管理
+func (c *Controller) UserRoleUnassign(ctx *gin.Context) {
+	userId, ok := userIdParam(ctx)
+	if !ok {
+		return
+	}
+	role := ctx.Param("role")
+	if role == adminRole && userId == auth.UserID(ctx) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "不能撤销自己的管理员角色"})
+		return
+	}
+	ok, err := c.rbacData.UnassignRole(userId, role)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "用户没有该角色"})
+		return
+	}
+	c.enforcer.Invalidate(userId)
+	c.record(ctx, audit.ActionRoleUnassign, "user", strconv.FormatInt(userId, 10), "role="+role)
+	ctx.JSON(http.StatusOK, gin.H{"msg": "撤销成功"})
+}
+
+// userIdParam 读取路径参数中的用户ID，无效时已写回响应
+func userIdParam(ctx *gin.Context) (int64, bool) {
+	userId, err := strconv.ParseInt(ctx.Param("uid"), 10, 64)
+	if err != nil || userId <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return 0, false
+	}
+	return userId, true
+}
+
+// Permissions 返回当前用户的管理权限，前端据此显示管理入口
+func (c *Controller) Permissions(ctx *gin.Context) {
+	perms, err := c.enforcer.Permissions(auth.UserID(ctx))
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"permissions": perms})
+}
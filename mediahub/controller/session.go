@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/session"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// SessionCreate 使用 Bearer 令牌换取浏览器会话
+// 令牌已由认证中间件通过用户服务（或本地 JWT）校验，这里只为其身份创建会话并写入 Cookie；
+// 响应中的 csrf_token 与 CSRF Cookie 相同，后续状态变更请求需要放入 X-CSRF-Token 请求头
+func (c *Controller) SessionCreate(ctx *gin.Context) {
+	p := auth.GetPrincipal(ctx)
+	if p == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+		return
+	}
+	if p.Method != auth.MethodJwt && p.Method != auth.MethodRemote {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "只能使用登录令牌换取会话"})
+		return
+	}
+	sess, token, err := c.sessions.Create(p, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	c.cookies.Write(ctx, token, sess.CsrfToken)
+	c.record(ctx, audit.ActionSessionCreate, "session", sess.ID, "")
+	ctx.JSON(http.StatusOK, gin.H{
+		"csrf_token": sess.CsrfToken,
+		"session":    sess,
+	})
+}
+
+// SessionGet 查询当前会话，页面刷新后用于恢复登录状态与 CSRF 令牌
+func (c *Controller) SessionGet(ctx *gin.Context) {
+	sess := session.Get(ctx)
+	if sess == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "会话不存在或已过期"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"csrf_token": sess.CsrfToken,
+		"session":    sess,
+	})
+}
+
+// SessionDelete 退出登录，删除当前会话并清除 Cookie
+func (c *Controller) SessionDelete(ctx *gin.Context) {
+	if sess := session.Get(ctx); sess != nil {
+		if _, err := c.sessions.Delete(sess.Principal.UserID, sess.ID); err != nil {
+			c.log.Error(zerror.NewByErr(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		c.record(ctx, audit.ActionSessionRevoke, "session", sess.ID, "logout")
+	}
+	c.cookies.Clear(ctx)
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// SessionList 查询当前用户的全部会话，current 标记当前请求所用的会话
+func (c *Controller) SessionList(ctx *gin.Context) {
+	list, err := c.sessions.List(auth.UserID(ctx))
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	current := ""
+	if sess := session.Get(ctx); sess != nil {
+		current = sess.ID
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"list":    list,
+		"current": current,
+	})
+}
+
+// SessionRevoke 撤销当前用户的指定会话，用于在其他设备上退出登录
+func (c *Controller) SessionRevoke(ctx *gin.Context) {
+	ok, err := c.sessions.Delete(auth.UserID(ctx), ctx.Param("id"))
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "会话不存在"})
+		return
+	}
+	c.record(ctx, audit.ActionSessionRevoke, "session", ctx.Param("id"), "")
+	if sess := session.Get(ctx); sess != nil && sess.ID == ctx.Param("id") {
+		c.cookies.Clear(ctx)
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// SessionRevokeAll 撤销当前用户的全部会话，包括当前会话
+func (c *Controller) SessionRevokeAll(ctx *gin.Context) {
+	n, err := c.sessions.DeleteUser(auth.UserID(ctx))
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	c.record(ctx, audit.ActionSessionRevoke, "user", strconv.FormatInt(auth.UserID(ctx), 10), fmt.Sprintf("all count=%d", n))
+	if session.Get(ctx) != nil {
+		c.cookies.Clear(ctx)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"revoked": n})
+}
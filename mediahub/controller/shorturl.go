@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"enterprise-project1-mediahub/mediahub/services"
+	"enterprise-project1-mediahub/mediahub/services/shorturl"
+	"enterprise-project1-mediahub/mediahub/services/shorturl/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"strconv"
+)
+
+// shortUrlInfo 短链详情
+type shortUrlInfo struct {
+	ID          int64  `json:"id"`
+	ShortKey    string `json:"short_key"`
+	ShortUrl    string `json:"short_url"`
+	OriginalUrl string `json:"original_url"`
+	Times       int64  `json:"times"`      // 访问次数
+	Status      int32  `json:"status"`     // 0正常，1已下架，2已停用
+	Expired     bool   `json:"expired"`    // 是否已过期或已达到访问次数上限
+	ExpireAt    int64  `json:"expire_at"`  // 过期时间戳，0表示永不过期
+	MaxClicks   int64  `json:"max_clicks"` // 最大访问次数，0表示不限制
+	CreateAt    int64  `json:"create_at"`
+	UpdateAt    int64  `json:"update_at"`
+}
+
+func newShortUrlInfo(in *proto.ShortUrlInfo) shortUrlInfo {
+	return shortUrlInfo{
+		ID:          in.Id,
+		ShortKey:    in.ShortKey,
+		ShortUrl:    in.ShortUrl,
+		OriginalUrl: in.OriginalUrl,
+		Times:       in.Times,
+		Status:      in.Status,
+		Expired:     in.Expired,
+		ExpireAt:    in.ExpireAt,
+		MaxClicks:   in.MaxClicks,
+		CreateAt:    in.CreateAt,
+		UpdateAt:    in.UpdateAt,
+	}
+}
+
+// shortUrlContext 创建调用短链服务的上下文，携带访问令牌与客户端信息
+func (c *Controller) shortUrlContext(ctx *gin.Context) context.Context {
+	outGoingCtx := services.AppendBearerTokenToContext(context.Background(), c.config.DependOn.ShortUrl.AccessToken)
+	return services.AppendClientToContext(outGoingCtx, ctx.ClientIP(), ctx.Request.UserAgent())
+}
+
+// shortUrlError 将短链服务返回的错误写入响应
+func (c *Controller) shortUrlError(ctx *gin.Context, err error) {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": st.Message()})
+	case codes.NotFound, codes.PermissionDenied:
+		// 不区分不存在与无权访问，避免泄露其他用户的短链
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "短链不存在"})
+	default:
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+	}
+}
+
+// ShortUrlList 分页查询当前用户的短链
+// 查询参数：create_from、create_to（时间戳）、domain（目标域名）、
+// status（active、expired、paused、disabled）、sort（recent、clicks）、page、page_size
+func (c *Controller) ShortUrlList(ctx *gin.Context) {
+	in := &proto.ListShortUrlsRequest{
+		UserID: auth.UserID(ctx),
+		Domain: ctx.Query("domain"),
+		Status: ctx.Query("status"),
+		Sort:   ctx.Query("sort"),
+	}
+	int64s := []struct {
+		name string
+		dest *int64
+	}{
+		{"create_from", &in.CreateFrom},
+		{"create_to", &in.CreateTo},
+	}
+	for _, f := range int64s {
+		if v := ctx.Query(f.name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数无效：" + f.name})
+				return
+			}
+			*f.dest = n
+		}
+	}
+	int32s := []struct {
+		name string
+		dest *int32
+	}{
+		{"page", &in.Page},
+		{"page_size", &in.PageSize},
+	}
+	for _, f := range int32s {
+		if v := ctx.Query(f.name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil || n <= 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数无效：" + f.name})
+				return
+			}
+			*f.dest = int32(n)
+		}
+	}
+
+	shortPool := shorturl.NewShortUrlClientPool()
+	clientConn, err := shortPool.Get()
+	if err != nil {
+		c.shortUrlError(ctx, err)
+		return
+	}
+	defer shortPool.Put(clientConn)
+
+	rs, err := proto.NewShortUrlClient(clientConn).ListShortUrls(c.shortUrlContext(ctx), in)
+	if err != nil {
+		c.shortUrlError(ctx, err)
+		return
+	}
+	list := make([]shortUrlInfo, 0, len(rs.List))
+	for _, item := range rs.List {
+		list = append(list, newShortUrlInfo(item))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"list": list, "total": rs.Total})
+}
+
+// ShortUrlInfo 查询当前用户的短链详情，路径参数 key 为短链键或自定义别名
+func (c *Controller) ShortUrlInfo(ctx *gin.Context) {
+	shortPool := shorturl.NewShortUrlClientPool()
+	clientConn, err := shortPool.Get()
+	if err != nil {
+		c.shortUrlError(ctx, err)
+		return
+	}
+	defer shortPool.Put(clientConn)
+
+	rs, err := proto.NewShortUrlClient(clientConn).GetShortUrlInfo(c.shortUrlContext(ctx), &proto.ShortKey{
+		Key:    ctx.Param("key"),
+		UserID: auth.UserID(ctx),
+	})
+	if err != nil {
+		c.shortUrlError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"info": newShortUrlInfo(rs)})
+}
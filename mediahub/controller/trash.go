@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"enterprise-project1-mediahub/mediahub/services/shorturl"
+	"enterprise-project1-mediahub/mediahub/services/shorturl/proto"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// defaultTrashRetentionDays 回收站默认保留天数
+const defaultTrashRetentionDays = 30
+
+// trashRetention 返回回收站的保留时长（秒）
+func (c *Controller) trashRetention() int64 {
+	days := c.config.Media.TrashRetentionDays
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	return int64(days) * 86400
+}
+
+// MediaDelete 将媒体移入回收站
+// 保留期内公开对象改为私有读、短链停用，到期后由 shorturl-crontab 彻底清除对象并禁用短链
+func (c *Controller) MediaDelete(ctx *gin.Context) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	if media.IsDeleted() {
+		ctx.JSON(http.StatusOK, gin.H{"msg": "删除成功", "purge_at": media.PurgeAt})
+		return
+	}
+	err := c.hideTrashed(ctx, media)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	now := time.Now().Unix()
+	purgeAt := now + c.trashRetention()
+	err = c.mediaData.SoftDelete(media.ID, now, purgeAt)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	c.record(ctx, audit.ActionMediaDelete, "media", strconv.FormatInt(media.ID, 10), fmt.Sprintf("purge_at=%d", purgeAt))
+	ctx.JSON(http.StatusOK, gin.H{"msg": "删除成功", "purge_at": purgeAt})
+}
+
+// MediaRestore 将媒体从回收站恢复
+func (c *Controller) MediaRestore(ctx *gin.Context) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	now := time.Now().Unix()
+	if !media.IsDeleted() || media.PurgeAt <= now {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "媒体不在回收站中"})
+		return
+	}
+	err := c.showRestored(ctx, media)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	err = c.mediaData.Restore(media.ID, now)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	c.record(ctx, audit.ActionMediaRestore, "media", strconv.FormatInt(media.ID, 10), "")
+	ctx.JSON(http.StatusOK, gin.H{"msg": "恢复成功"})
+}
+
+// hideTrashed 媒体移入回收站前让访问路径同步失效：公开对象改为私有读，短链停用
+// 其他不在回收站中的媒体仍在使用同一对象或短链时保持不变
+func (c *Controller) hideTrashed(ctx *gin.Context, media *data.MediaEntity) error {
+	if !media.IsPrivate() {
+		n, err := c.mediaData.CountLiveObjectRefs(media.ObjectPath, media.ID)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			if err = c.sf.CreateStorage().SetPrivate(media.ObjectPath, true); err != nil {
+				return err
+			}
+		}
+	}
+	if media.ShortUrl == "" {
+		return nil
+	}
+	n, err := c.mediaData.CountLiveShortUrlRefs(media.ShortUrl, media.ID)
+	if err != nil || n > 0 {
+		return err
+	}
+	return c.setShortUrlEnabled(ctx, media, false)
+}
+
+// showRestored 媒体恢复前重新公开对象并启用短链
+func (c *Controller) showRestored(ctx *gin.Context, media *data.MediaEntity) error {
+	if !media.IsPrivate() {
+		if err := c.sf.CreateStorage().SetPrivate(media.ObjectPath, false); err != nil {
+			return err
+		}
+	}
+	if media.ShortUrl == "" {
+		return nil
+	}
+	return c.setShortUrlEnabled(ctx, media, true)
+}
+
+// setShortUrlEnabled 调用短链服务停用或启用媒体的短链
+// 短链已被管理员下架或已被删除时状态不再改变，按成功处理
+func (c *Controller) setShortUrlEnabled(ctx *gin.Context, media *data.MediaEntity, enabled bool) error {
+	shortPool := shorturl.NewShortUrlClientPool()
+	clientConn, err := shortPool.Get()
+	if err != nil {
+		return err
+	}
+	defer shortPool.Put(clientConn)
+
+	client := proto.NewShortUrlClient(clientConn)
+	in := &proto.ShortKey{Key: path.Base(media.ShortUrl), UserID: media.UserID}
+	if enabled {
+		_, err = client.EnableShortUrl(c.shortUrlContext(ctx), in)
+	} else {
+		_, err = client.DisableShortUrl(c.shortUrlContext(ctx), in)
+	}
+	switch status.Code(err) {
+	case codes.NotFound, codes.FailedPrecondition:
+		return nil
+	}
+	return err
+}
+
+// TrashList 查询当前用户的回收站
+func (c *Controller) TrashList(ctx *gin.Context) {
+	userId := auth.UserID(ctx)
+	if userId == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+		return
+	}
+	list, err := c.mediaData.ListTrash(userId, time.Now().Unix())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"list": list})
+}
+
+// TrashEmpty 清空当前用户的回收站，媒体会在定时任务下一次执行时被彻底清除
+func (c *Controller) TrashEmpty(ctx *gin.Context) {
+	userId := auth.UserID(ctx)
+	if userId == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+		return
+	}
+	n, err := c.mediaData.EmptyTrash(userId, time.Now().Unix())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	c.record(ctx, audit.ActionTrashEmpty, "user", strconv.FormatInt(userId, 10), fmt.Sprintf("count=%d", n))
+	ctx.JSON(http.StatusOK, gin.H{"msg": "回收站已清空", "count": n})
+}
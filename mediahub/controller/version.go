@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MediaReplace 为已有媒体上传新内容
+// 新内容写入新的对象路径并记录为新版本，旧版本保留可恢复；公开媒体的短链通过 UpdateTarget 指向新地址
+func (c *Controller) MediaReplace(ctx *gin.Context) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	if media.IsDeleted() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "媒体不存在"})
+		return
+	}
+	content, ext, ok := c.readImage(ctx)
+	if !ok {
+		return
+	}
+
+	s := c.sf.CreateStorage()
+	content, originalPath, optimized, err := c.prepareContent(s, content, ext, media.UserID, media.IsPrivate())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	md5Digest := calMD5Digest(content)
+	filePath, url, err := c.putObject(s, content, md5Digest, ext, media.UserID, media.IsPrivate())
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+
+	v := &data.MediaVersionEntity{
+		MediaID:      media.ID,
+		Md5:          fmt.Sprintf("%x", md5Digest),
+		ObjectPath:   filePath,
+		OriginalPath: originalPath,
+		Url:          url,
+		Size:         int64(optimized.Size),
+		SavedBytes:   int64(optimized.SavedBytes()),
+		CreateAt:     time.Now().Unix(),
+	}
+	err = c.versionData.Create(v)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	if c.switchVersion(ctx, media, v, gin.H{"optimize": optimizeSummary(optimized)}) {
+		c.record(ctx, audit.ActionMediaReplace, "media", strconv.FormatInt(media.ID, 10), fmt.Sprintf("version=%d", v.Version))
+	}
+}
+
+// MediaVersions 查询媒体的历史版本
+func (c *Controller) MediaVersions(ctx *gin.Context) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	list, err := c.versionData.List(media.ID)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"current": media.Version,
+		"list":    list,
+	})
+}
+
+// MediaVersionRestore 将媒体恢复到指定的历史版本
+func (c *Controller) MediaVersionRestore(ctx *gin.Context) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	if media.IsDeleted() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "媒体不存在"})
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数检查失败"})
+		return
+	}
+	v, err := c.versionData.Get(media.ID, version)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	if v == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "版本不存在"})
+		return
+	}
+	if c.switchVersion(ctx, media, v, nil) {
+		c.record(ctx, audit.ActionMediaReplace, "media", strconv.FormatInt(media.ID, 10), fmt.Sprintf("restore version=%d", v.Version))
+	}
+}
+
+// switchVersion 将媒体切换到版本 v 并同步短链指向
+// 私有媒体的短链指向固定的访问路由，签名时读取当前版本的对象路径，无需修改短链
+// extra 为附加到响应中的字段，返回是否切换成功，失败时已写回响应
+func (c *Controller) switchVersion(ctx *gin.Context, media *data.MediaEntity, v *data.MediaVersionEntity, extra gin.H) bool {
+	now := time.Now().Unix()
+	err := c.mediaData.SetCurrentVersion(media.ID, v, now)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return false
+	}
+	if !media.IsPrivate() && media.ShortUrl != "" {
+		if err = c.retargetShortUrl(ctx, media, v.Url, now); err != nil {
+			c.log.Error(zerror.NewByErr(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{})
+			return false
+		}
+	}
+	rs := gin.H{
+		"url":      media.ShortUrl,
+		"media_id": media.ID,
+		"version":  v.Version,
+		"msg":      "操作成功",
+	}
+	for k, val := range extra {
+		rs[k] = val
+	}
+	ctx.JSON(http.StatusOK, rs)
+	return true
+}
+
+// retargetShortUrl 将公开媒体的短链指向新地址
+// 相同内容的上传复用同一短链，短链仍被其他媒体使用时不修改其指向，而是为新地址生成短链并记录到本媒体，
+// 只有本媒体独占的短链才原地修改指向；media.ShortUrl 随之更新
+func (c *Controller) retargetShortUrl(ctx *gin.Context, media *data.MediaEntity, url string, now int64) error {
+	refs, err := c.mediaData.CountShortUrlRefs(media.ShortUrl, media.ID)
+	if err != nil {
+		return err
+	}
+	if refs == 0 {
+		return c.updateShortUrlTarget(ctx, media.ShortUrl, url, media.UserID)
+	}
+	shortUrl, err := c.getShortUrl(ctx, url, media.UserID)
+	if err != nil {
+		return err
+	}
+	if err = c.mediaData.UpdateShortUrl(media.ID, shortUrl, now); err != nil {
+		return err
+	}
+	media.ShortUrl = shortUrl
+	return nil
+}
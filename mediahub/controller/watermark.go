@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"bytes"
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/storage"
+	"enterprise-project1-mediahub/mediahub/pkg/watermark"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"time"
+)
+
+// 水印位置可选值
+var watermarkPositions = map[string]bool{
+	watermark.PositionTopLeft:     true,
+	watermark.PositionTopRight:    true,
+	watermark.PositionBottomLeft:  true,
+	watermark.PositionBottomRight: true,
+	watermark.PositionCenter:      true,
+}
+
+// defaultWatermarkImage 读取管理员配置的默认图片水印，只读取一次
+func (c *Controller) defaultWatermarkImage() image.Image {
+	c.defaultMarkOnce.Do(func() {
+		if c.config.Watermark.ImagePath == "" {
+			return
+		}
+		f, err := os.Open(c.config.Watermark.ImagePath)
+		if err != nil {
+			c.log.Error(zerror.NewByErr(err))
+			return
+		}
+		defer f.Close()
+		c.defaultMark, _, err = image.Decode(f)
+		if err != nil {
+			c.log.Error(zerror.NewByErr(err))
+		}
+	})
+	return c.defaultMark
+}
+
+// watermarkOptions 返回上传时使用的水印参数，不需要加水印时返回nil
+// 公共上传使用管理员配置的默认水印，登录用户只有自己开启了水印设置时才加水印
+func (c *Controller) watermarkOptions(s storage.Storage, userId int64) (*watermark.Options, error) {
+	if userId != 0 {
+		setting, err := c.watermarkData.Get(userId)
+		if err != nil {
+			return nil, err
+		}
+		if setting == nil || !setting.Enable {
+			return nil, nil
+		}
+		opt := &watermark.Options{
+			Text:     setting.Text,
+			Position: setting.Position,
+			Opacity:  setting.Opacity,
+			Scale:    setting.Scale,
+			Margin:   setting.Margin,
+		}
+		if setting.ImagePath != "" {
+			r, err := s.Download(setting.ImagePath)
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+			opt.Image, _, err = image.Decode(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return opt, nil
+	}
+
+	cnf := c.config.Watermark
+	if !cnf.Enable {
+		return nil, nil
+	}
+	opt := &watermark.Options{
+		Text:     cnf.Text,
+		Image:    c.defaultWatermarkImage(),
+		Position: cnf.Position,
+		Opacity:  cnf.Opacity,
+		Scale:    cnf.Scale,
+		Margin:   cnf.Margin,
+	}
+	if opt.IsEmpty() {
+		return nil, nil
+	}
+	return opt, nil
+}
+
+// applyWatermark 为公开上传的图片添加水印
+// 登录用户的原图以私有对象保存，仅属主可以下载；不支持的格式原样返回
+// 返回：
+//   - 加水印后的内容
+//   - 原图路径，未加水印时为空
+//   - 错误信息
+func (c *Controller) applyWatermark(s storage.Storage, content []byte, ext string, userId int64) ([]byte, string, error) {
+	opt, err := c.watermarkOptions(s, userId)
+	if err != nil || opt == nil {
+		return content, "", err
+	}
+	marked, err := watermark.ApplyBytes(content, *opt)
+	if errors.Is(err, watermark.ErrUnsupportedFormat) {
+		return content, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if userId == 0 {
+		return marked, "", nil
+	}
+
+	md5Digest := calMD5Digest(content)
+	originalPath := fmt.Sprintf("/original/%d/%x%s", userId, md5Digest, ext)
+	err = s.UploadPrivate(io.NopCloser(bytes.NewReader(content)), md5Digest, originalPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return marked, originalPath, nil
+}
+
+// WatermarkGet 查询当前用户的水印设置
+func (c *Controller) WatermarkGet(ctx *gin.Context) {
+	userId := auth.UserID(ctx)
+	if userId == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+		return
+	}
+	setting, err := c.watermarkData.Get(userId)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"setting": setting})
+}
+
+// WatermarkSave 保存当前用户的水印设置
+// 表单字段：enable、text、position、opacity、scale、margin，可选 image 文件作为图片水印
+func (c *Controller) WatermarkSave(ctx *gin.Context) {
+	userId := auth.UserID(ctx)
+	if userId == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+		return
+	}
+	old, err := c.watermarkData.Get(userId)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+
+	setting := &data.WatermarkEntity{
+		UserID:   userId,
+		Enable:   ctx.PostForm("enable") == "true" || ctx.PostForm("enable") == "1",
+		Text:     ctx.PostForm("text"),
+		Position: ctx.DefaultPostForm("position", watermark.PositionBottomRight),
+		Opacity:  watermark.DefaultOpacity,
+		Scale:    watermark.DefaultScale,
+		UpdateAt: time.Now().Unix(),
+	}
+	if old != nil {
+		setting.ImagePath = old.ImagePath
+	}
+	if !watermarkPositions[setting.Position] {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "水印位置无效"})
+		return
+	}
+	if v := ctx.PostForm("opacity"); v != "" {
+		setting.Opacity, err = strconv.ParseFloat(v, 64)
+		if err != nil || setting.Opacity <= 0 || setting.Opacity > 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "不透明度取值 (0, 1]"})
+			return
+		}
+	}
+	if v := ctx.PostForm("scale"); v != "" {
+		setting.Scale, err = strconv.ParseFloat(v, 64)
+		if err != nil || setting.Scale <= 0 || setting.Scale > 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "缩放比例取值 (0, 1]"})
+			return
+		}
+	}
+	if v := ctx.PostForm("margin"); v != "" {
+		setting.Margin, err = strconv.Atoi(v)
+		if err != nil || setting.Margin < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "边距无效"})
+			return
+		}
+	}
+
+	// 上传了新的图片水印时替换原有图片，clear_image 用于改回文字水印
+	if ctx.PostForm("clear_image") == "true" {
+		setting.ImagePath = ""
+	}
+	if _, err = ctx.FormFile("file"); err == nil {
+		content, ext, ok := c.readImage(ctx)
+		if !ok {
+			return
+		}
+		md5Digest := calMD5Digest(content)
+		setting.ImagePath = fmt.Sprintf("/watermark/%d/%x%s", userId, md5Digest, ext)
+		err = c.sf.CreateStorage().UploadPrivate(io.NopCloser(bytes.NewReader(content)), md5Digest, setting.ImagePath)
+		if err != nil {
+			c.log.Error(zerror.NewByErr(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+	}
+	if setting.Enable && setting.Text == "" && setting.ImagePath == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "请设置文字或图片水印"})
+		return
+	}
+
+	if err = c.watermarkData.Save(setting); err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"setting": setting, "msg": "保存成功"})
+}
+
+// MediaOriginal 属主下载未加水印的原图，未加水印的媒体返回当前内容
+func (c *Controller) MediaOriginal(ctx *gin.Context) {
+	media := c.getOwnMedia(ctx)
+	if media == nil {
+		return
+	}
+	if media.IsDeleted() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "媒体不存在"})
+		return
+	}
+	objectPath := media.OriginalPath
+	if objectPath == "" {
+		objectPath = media.ObjectPath
+	}
+
+	r, err := c.sf.CreateStorage().Download(objectPath)
+	if err != nil {
+		c.log.Error(zerror.NewByErr(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{})
+		return
+	}
+	defer r.Close()
+
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Header("Content-Type", mime.TypeByExtension(path.Ext(objectPath)))
+	ctx.Status(http.StatusOK)
+	if _, err = io.Copy(ctx.Writer, r); err != nil {
+		c.log.Error(zerror.NewByErr(err))
+	}
+}
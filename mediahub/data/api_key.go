@@ -0,0 +1,156 @@
+package data
+
+import (
+	"database/sql"
+	"enterprise-project1-mediahub/mediahub/pkg/constants"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ApiKeyEntity 用户创建的 API Key，只保存密钥哈希
+type ApiKeyEntity struct {
+	ID         int64    `json:"id"`           // 主键ID
+	UserID     int64    `json:"user_id"`      // 所属用户ID
+	Name       string   `json:"name"`         // 名称，便于用户辨认用途
+	Display    string   `json:"display"`      // 密钥开头的若干字符
+	KeyHash    string   `json:"-"`            // 密钥的 SHA-256 哈希
+	Scopes     []string `json:"scopes"`       // 权限范围，为nil表示不受限
+	ExpireAt   int64    `json:"expire_at"`    // 过期时间戳，0表示永不过期
+	LastUsedAt int64    `json:"last_used_at"` // 最后使用时间戳
+	RevokedAt  int64    `json:"revoked_at"`   // 撤销时间戳，0表示未撤销
+	CreateAt   int64    `json:"create_at"`    // 创建时间戳
+}
+
+// IsActive 判断密钥在 now 时是否可用
+func (e *ApiKeyEntity) IsActive(now int64) bool {
+	return e.RevokedAt == 0 && (e.ExpireAt == 0 || e.ExpireAt > now)
+}
+
+// IApiKeyData 定义 API Key 数据操作的接口规范
+type IApiKeyData interface {
+	// Create 新增 API Key 并回填ID
+	Create(e *ApiKeyEntity) error
+
+	// ListByUser 查询用户的全部 API Key，按创建时间倒序
+	ListByUser(userID int64) ([]*ApiKeyEntity, error)
+
+	// GetByHash 通过密钥哈希查询，不存在时返回nil
+	GetByHash(keyHash string) (*ApiKeyEntity, error)
+
+	// Revoke 撤销用户的 API Key，返回是否有记录被撤销
+	Revoke(id, userID int64, now int64) (bool, error)
+
+	// TouchLastUsed 更新最后使用时间
+	TouchLastUsed(id int64, now int64) error
+}
+
+type apiKeyData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewApiKeyData 创建 API Key 数据操作对象
+func NewApiKeyData(log log.ILogger, db *sql.DB) IApiKeyData {
+	return &apiKeyData{
+		log:       log,
+		db:        db,
+		tableName: constants.TABLENAME_API_KEY,
+	}
+}
+
+// apiKeyColumns 查询 API Key 时使用的字段列表，与 scanApiKey 的顺序一致
+const apiKeyColumns = "id,user_id,name,display,key_hash,scopes,expire_at,last_used_at,revoked_at,create_at"
+
+// scanApiKey 将一行查询结果扫描为 ApiKeyEntity
+// scopes 字段以空格分隔保存，空字符串表示不受限
+func scanApiKey(row interface{ Scan(dest ...any) error }) (*ApiKeyEntity, error) {
+	e := &ApiKeyEntity{}
+	var scopes string
+	err := row.Scan(&e.ID, &e.UserID, &e.Name, &e.Display, &e.KeyHash, &scopes, &e.ExpireAt, &e.LastUsedAt, &e.RevokedAt, &e.CreateAt)
+	if err != nil {
+		return nil, err
+	}
+	if scopes != "" {
+		e.Scopes = strings.Fields(scopes)
+	}
+	return e, nil
+}
+
+// Create 新增 API Key
+func (d *apiKeyData) Create(e *ApiKeyEntity) error {
+	sqlStr := fmt.Sprintf("insert into %s (user_id,name,display,key_hash,scopes,expire_at,create_at)values(?,?,?,?,?,?,?)", d.tableName)
+	res, err := d.db.Exec(sqlStr, e.UserID, e.Name, e.Display, e.KeyHash, strings.Join(e.Scopes, " "), e.ExpireAt, e.CreateAt)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	e.ID, err = res.LastInsertId()
+	return err
+}
+
+// ListByUser 查询用户的全部 API Key
+func (d *apiKeyData) ListByUser(userID int64) ([]*ApiKeyEntity, error) {
+	sqlStr := fmt.Sprintf("select %s from %s where user_id=? order by id desc", apiKeyColumns, d.tableName)
+	rows, err := d.db.Query(sqlStr, userID)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*ApiKeyEntity, 0)
+	for rows.Next() {
+		e, err := scanApiKey(rows)
+		if err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		results = append(results, e)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetByHash 通过密钥哈希查询
+func (d *apiKeyData) GetByHash(keyHash string) (*ApiKeyEntity, error) {
+	sqlStr := fmt.Sprintf("select %s from %s where key_hash=?", apiKeyColumns, d.tableName)
+	e, err := scanApiKey(d.db.QueryRow(sqlStr, keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return e, nil
+}
+
+// Revoke 撤销用户的 API Key，已撤销的记录不再更新
+func (d *apiKeyData) Revoke(id, userID int64, now int64) (bool, error) {
+	sqlStr := fmt.Sprintf("update %s set revoked_at=? where id=? and user_id=? and revoked_at=0", d.tableName)
+	res, err := d.db.Exec(sqlStr, now, id, userID)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// TouchLastUsed 更新最后使用时间
+func (d *apiKeyData) TouchLastUsed(id int64, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set last_used_at=? where id=?", d.tableName)
+	_, err := d.db.Exec(sqlStr, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package data
+
+import (
+	"database/sql"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/constants"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// auditColumns 审计记录的查询字段，与 scanAudit 的顺序一致
+const auditColumns = "id,service,actor_type,actor_id,actor_name,client,action,target_type,target_id,ip,user_agent,outcome,detail,create_at"
+
+// IAuditData 定义审计记录数据操作的接口规范，审计表只追加，不提供修改与删除
+type IAuditData interface {
+	// Append 批量写入审计记录
+	Append(records []*audit.Record) error
+
+	// Query 按条件分页查询审计记录，按ID倒序
+	Query(q *audit.Query) ([]*audit.Record, error)
+
+	// Export 按条件逐条读取全部审计记录，按ID倒序，fn 返回错误时停止
+	Export(q *audit.Query, fn func(r *audit.Record) error) error
+}
+
+type auditData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewAuditData 创建审计记录数据操作对象
+func NewAuditData(log log.ILogger, db *sql.DB) IAuditData {
+	return &auditData{
+		log:       log,
+		db:        db,
+		tableName: constants.TABLENAME_AUDIT_LOG,
+	}
+}
+
+// Append 使用一条多行 insert 写入审计记录
+func (d *auditData) Append(records []*audit.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	placeholders := make([]string, 0, len(records))
+	args := make([]any, 0, len(records)*13)
+	for _, r := range records {
+		placeholders = append(placeholders, "(?,?,?,?,?,?,?,?,?,?,?,?,?)")
+		args = append(args, r.Service, r.ActorType, r.ActorID, r.ActorName, r.Client, r.Action,
+			r.TargetType, r.TargetID, r.IP, r.UserAgent, r.Outcome, r.Detail, r.CreateAt)
+	}
+	sqlStr := fmt.Sprintf("insert into %s (service,actor_type,actor_id,actor_name,client,action,target_type,target_id,ip,user_agent,outcome,detail,create_at)values%s",
+		d.tableName, strings.Join(placeholders, ","))
+	if _, err := d.db.Exec(sqlStr, args...); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// Query 按条件分页查询审计记录
+func (d *auditData) Query(q *audit.Query) ([]*audit.Record, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+	where, args := auditWhere(q)
+	sqlStr := fmt.Sprintf("select %s from %s%s order by id desc limit ?", auditColumns, d.tableName, where)
+	rows, err := d.db.Query(sqlStr, append(args, limit)...)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*audit.Record, 0)
+	for rows.Next() {
+		r, err := scanAudit(rows)
+		if err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
+
+// Export 按条件逐条读取审计记录，不限制条数
+func (d *auditData) Export(q *audit.Query, fn func(r *audit.Record) error) error {
+	where, args := auditWhere(q)
+	sqlStr := fmt.Sprintf("select %s from %s%s order by id desc", auditColumns, d.tableName, where)
+	rows, err := d.db.Query(sqlStr, args...)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r, err := scanAudit(rows)
+		if err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return err
+		}
+		if err = fn(r); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// auditWhere 按查询条件拼接 where 子句
+func auditWhere(q *audit.Query) (string, []any) {
+	conds := make([]string, 0)
+	args := make([]any, 0)
+	add := func(cond string, arg any) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+	if q.ActorType != "" {
+		add("actor_type=?", q.ActorType)
+	}
+	if q.ActorID != 0 {
+		add("actor_id=?", q.ActorID)
+	}
+	if q.TargetType != "" {
+		add("target_type=?", q.TargetType)
+	}
+	if q.TargetID != "" {
+		add("target_id=?", q.TargetID)
+	}
+	if q.Action != "" {
+		add("action=?", q.Action)
+	}
+	if q.From != 0 {
+		add("create_at>=?", q.From)
+	}
+	if q.To != 0 {
+		add("create_at<?", q.To)
+	}
+	if q.BeforeID != 0 {
+		add("id<?", q.BeforeID)
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " where " + strings.Join(conds, " and "), args
+}
+
+func scanAudit(rows *sql.Rows) (*audit.Record, error) {
+	r := &audit.Record{}
+	err := rows.Scan(&r.ID, &r.Service, &r.ActorType, &r.ActorID, &r.ActorName, &r.Client, &r.Action,
+		&r.TargetType, &r.TargetID, &r.IP, &r.UserAgent, &r.Outcome, &r.Detail, &r.CreateAt)
+	return r, err
+}
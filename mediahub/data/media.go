@@ -0,0 +1,319 @@
+package data
+
+import (
+	"database/sql"
+	"enterprise-project1-mediahub/mediahub/pkg/constants"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
+	"fmt"
+)
+
+// MediaEntity 表示一次上传的媒体记录
+type MediaEntity struct {
+	ID           int64  `json:"id"`           // 主键ID
+	UserID       int64  `json:"user_id"`      // 所属用户ID（0表示公共上传）
+	Md5          string `json:"md5"`          // 文件内容的md5
+	ObjectPath   string `json:"object_path"`  // 对象存储中的路径
+	OriginalPath string `json:"-"`            // 未加水印的原图路径（私有对象），未加水印时为空
+	Url          string `json:"url"`          // 公开访问地址，私有媒体为空
+	Size         int64  `json:"size"`         // 文件大小（字节）
+	SavedBytes   int64  `json:"saved_bytes"`  // 上传优化节省的字节数
+	ShortUrl     string `json:"short_url"`    // 短链接
+	Visibility   int    `json:"visibility"`   // 可见性，见 constants.VISIBILITY_*
+	Version      int    `json:"version"`      // 当前版本号
+	SignVersion  int    `json:"sign_version"` // 签名版本，递增后历史签名全部失效
+	Revoked      bool   `json:"revoked"`      // 是否已撤销访问
+	DeletedAt    int64  `json:"deleted_at"`   // 移入回收站的时间戳，0表示未删除
+	PurgeAt      int64  `json:"purge_at"`     // 计划彻底清除的时间戳
+	CreateAt     int64  `json:"create_at"`    // 创建时间戳
+	UpdateAt     int64  `json:"update_at"`    // 最后更新时间戳
+}
+
+// IsPrivate 判断媒体是否为私有
+func (e *MediaEntity) IsPrivate() bool {
+	return e.Visibility == constants.VISIBILITY_PRIVATE
+}
+
+// IsDeleted 判断媒体是否已移入回收站
+func (e *MediaEntity) IsDeleted() bool {
+	return e.DeletedAt != 0
+}
+
+// IMediaData 定义媒体数据操作的接口规范
+type IMediaData interface {
+	// Create 新增媒体记录并返回自增ID
+	Create(e *MediaEntity) (int64, error)
+
+	// SetCurrentVersion 将媒体切换到指定版本的内容
+	SetCurrentVersion(id int64, v *MediaVersionEntity, now int64) error
+
+	// UpdateShortUrl 更新媒体的短链接
+	UpdateShortUrl(id int64, shortUrl string, now int64) error
+
+	// CountShortUrlRefs 统计除 excludeID 外使用同一短链的媒体数量
+	CountShortUrlRefs(shortUrl string, excludeID int64) (int64, error)
+
+	// CountLiveShortUrlRefs 统计除 excludeID 外使用同一短链且不在回收站中的媒体数量
+	CountLiveShortUrlRefs(shortUrl string, excludeID int64) (int64, error)
+
+	// CountLiveObjectRefs 统计除 excludeID 外使用同一对象且不在回收站中的媒体数量
+	CountLiveObjectRefs(objectPath string, excludeID int64) (int64, error)
+
+	// GetByID 通过ID查询媒体记录，不存在时返回nil
+	GetByID(id int64) (*MediaEntity, error)
+
+	// SetRevoked 设置撤销状态，撤销时同时递增签名版本使已发放的签名地址失效
+	SetRevoked(id int64, revoked bool, now int64) error
+
+	// SoftDelete 将媒体移入回收站，到达 purgeAt 后由定时任务彻底清除
+	SoftDelete(id int64, now, purgeAt int64) error
+
+	// Restore 将媒体从回收站恢复
+	Restore(id int64, now int64) error
+
+	// ListTrash 查询用户回收站中尚未到期清除的媒体
+	ListTrash(userID int64, now int64) ([]*MediaEntity, error)
+
+	// EmptyTrash 清空用户回收站，将其中的媒体标记为立即清除，返回受影响的条数
+	EmptyTrash(userID int64, now int64) (int64, error)
+
+	// Stats 统计全部媒体
+	Stats() (*MediaStats, error)
+}
+
+// MediaStats 媒体全局统计
+type MediaStats struct {
+	Total      int64 `json:"total"`       // 媒体总数（含回收站）
+	Private    int64 `json:"private"`     // 私有媒体数
+	Deleted    int64 `json:"deleted"`     // 回收站中的媒体数
+	Users      int64 `json:"users"`       // 上传过媒体的用户数
+	Size       int64 `json:"size"`        // 当前版本占用的存储（字节）
+	SavedBytes int64 `json:"saved_bytes"` // 上传优化累计节省的字节数
+}
+
+type mediaData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewMediaData 创建媒体数据操作对象
+func NewMediaData(log log.ILogger, db *sql.DB) IMediaData {
+	return &mediaData{
+		log:       log,
+		db:        db,
+		tableName: constants.TABLENAME_MEDIA,
+	}
+}
+
+// mediaColumns 查询媒体记录时使用的字段列表，与 scanMedia 的顺序一致
+const mediaColumns = "id,user_id,md5,object_path,original_path,url,size,saved_bytes,short_url,visibility,version,sign_version,revoked,deleted_at,purge_at,create_at,update_at"
+
+// scanMedia 将一行查询结果扫描为 MediaEntity
+func scanMedia(row interface{ Scan(dest ...any) error }) (*MediaEntity, error) {
+	e := &MediaEntity{}
+	err := row.Scan(&e.ID, &e.UserID, &e.Md5, &e.ObjectPath, &e.OriginalPath, &e.Url, &e.Size, &e.SavedBytes, &e.ShortUrl, &e.Visibility, &e.Version, &e.SignVersion, &e.Revoked, &e.DeletedAt, &e.PurgeAt, &e.CreateAt, &e.UpdateAt)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Create 新增媒体记录
+// 参数：
+//   - e: 媒体实体，ID 字段会被回填
+//
+// 返回：
+//   - 新生成的记录ID
+//   - 错误信息（数据库操作失败时）
+func (d *mediaData) Create(e *MediaEntity) (int64, error) {
+	sqlStr := fmt.Sprintf("insert into %s (user_id,md5,object_path,original_path,url,size,saved_bytes,short_url,visibility,version,create_at,update_at)values(?,?,?,?,?,?,?,?,?,?,?,?)", d.tableName)
+	res, err := d.db.Exec(sqlStr, e.UserID, e.Md5, e.ObjectPath, e.OriginalPath, e.Url, e.Size, e.SavedBytes, e.ShortUrl, e.Visibility, e.Version, e.CreateAt, e.UpdateAt)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return 0, err
+	}
+	e.ID, err = res.LastInsertId()
+	return e.ID, err
+}
+
+// SetCurrentVersion 将媒体切换到指定版本的内容
+// 参数：
+//   - id: 媒体ID
+//   - v: 目标版本
+//   - now: 当前时间戳
+func (d *mediaData) SetCurrentVersion(id int64, v *MediaVersionEntity, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set md5=?,object_path=?,original_path=?,url=?,size=?,saved_bytes=?,version=?,update_at=? where id=?", d.tableName)
+	_, err := d.db.Exec(sqlStr, v.Md5, v.ObjectPath, v.OriginalPath, v.Url, v.Size, v.SavedBytes, v.Version, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateShortUrl 更新媒体的短链接
+func (d *mediaData) UpdateShortUrl(id int64, shortUrl string, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set short_url=?,update_at=? where id=?", d.tableName)
+	_, err := d.db.Exec(sqlStr, shortUrl, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// CountShortUrlRefs 统计除 excludeID 外使用同一短链的媒体数量，包括回收站中的媒体
+// 相同内容的上传复用同一短链，只有没有其他媒体使用时才能修改短链的指向
+// 参数：
+//   - shortUrl: 短链接
+//   - excludeID: 排除的媒体ID
+//
+// 返回：
+//   - 使用该短链的其他媒体数量
+//   - 错误信息（数据库操作失败时）
+func (d *mediaData) CountShortUrlRefs(shortUrl string, excludeID int64) (int64, error) {
+	sqlStr := fmt.Sprintf("select count(*) from %s where short_url = ? and id <> ?", d.tableName)
+	var n int64
+	err := d.db.QueryRow(sqlStr, shortUrl, excludeID).Scan(&n)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return 0, err
+	}
+	return n, nil
+}
+
+// CountLiveShortUrlRefs 统计除 excludeID 外使用同一短链且不在回收站中的媒体数量
+// 媒体移入回收站时，只有没有其他正常媒体使用该短链才能停用
+func (d *mediaData) CountLiveShortUrlRefs(shortUrl string, excludeID int64) (int64, error) {
+	sqlStr := fmt.Sprintf("select count(*) from %s where short_url = ? and id <> ? and deleted_at = 0", d.tableName)
+	var n int64
+	err := d.db.QueryRow(sqlStr, shortUrl, excludeID).Scan(&n)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return 0, err
+	}
+	return n, nil
+}
+
+// CountLiveObjectRefs 统计除 excludeID 外使用同一对象且不在回收站中的媒体数量
+// 同一用户重复上传相同内容时共用对象，只有没有其他正常媒体使用该对象才能改为私有读
+func (d *mediaData) CountLiveObjectRefs(objectPath string, excludeID int64) (int64, error) {
+	sqlStr := fmt.Sprintf("select count(*) from %s where object_path = ? and id <> ? and deleted_at = 0", d.tableName)
+	var n int64
+	err := d.db.QueryRow(sqlStr, objectPath, excludeID).Scan(&n)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return 0, err
+	}
+	return n, nil
+}
+
+// GetByID 通过ID查询媒体记录
+// 返回：
+//   - 查询到的实体对象，不存在时为nil
+//   - 错误信息（数据库操作失败时）
+func (d *mediaData) GetByID(id int64) (*MediaEntity, error) {
+	sqlStr := fmt.Sprintf("select %s from %s where id = ?", mediaColumns, d.tableName)
+	e, err := scanMedia(d.db.QueryRow(sqlStr, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetRevoked 设置媒体的撤销状态
+// 撤销时递增 sign_version，已经发放出去的 hmac 签名地址随之失效
+func (d *mediaData) SetRevoked(id int64, revoked bool, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set revoked=?,update_at=? where id=?", d.tableName)
+	if revoked {
+		sqlStr = fmt.Sprintf("update %s set revoked=?,sign_version=sign_version+1,update_at=? where id=?", d.tableName)
+	}
+	_, err := d.db.Exec(sqlStr, revoked, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// SoftDelete 将媒体移入回收站
+// 参数：
+//   - id: 媒体ID
+//   - now: 当前时间戳
+//   - purgeAt: 计划彻底清除的时间戳
+func (d *mediaData) SoftDelete(id int64, now, purgeAt int64) error {
+	sqlStr := fmt.Sprintf("update %s set deleted_at=?,purge_at=?,update_at=? where id=? and deleted_at=0", d.tableName)
+	_, err := d.db.Exec(sqlStr, now, purgeAt, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// Restore 将媒体从回收站恢复，已到期等待清除的媒体不可恢复
+func (d *mediaData) Restore(id int64, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set deleted_at=0,purge_at=0,update_at=? where id=? and purge_at>?", d.tableName)
+	_, err := d.db.Exec(sqlStr, now, id, now)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// ListTrash 查询用户回收站中尚未到期清除的媒体，按删除时间倒序
+func (d *mediaData) ListTrash(userID int64, now int64) ([]*MediaEntity, error) {
+	sqlStr := fmt.Sprintf("select %s from %s where user_id=? and deleted_at>0 and purge_at>? order by deleted_at desc", mediaColumns, d.tableName)
+	rows, err := d.db.Query(sqlStr, userID, now)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*MediaEntity, 0)
+	for rows.Next() {
+		e, err := scanMedia(rows)
+		if err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		results = append(results, e)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
+
+// EmptyTrash 清空用户回收站
+// 只把清除时间提前到当前时间，真正的对象删除和短链禁用由定时任务完成
+func (d *mediaData) EmptyTrash(userID int64, now int64) (int64, error) {
+	sqlStr := fmt.Sprintf("update %s set purge_at=?,update_at=? where user_id=? and deleted_at>0 and purge_at>?", d.tableName)
+	res, err := d.db.Exec(sqlStr, now, now, userID, now)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Stats 统计全部媒体
+func (d *mediaData) Stats() (*MediaStats, error) {
+	sqlStr := fmt.Sprintf("select count(*),ifnull(sum(visibility=?),0),ifnull(sum(deleted_at>0),0),count(distinct nullif(user_id,0)),ifnull(sum(size),0),ifnull(sum(saved_bytes),0) from %s", d.tableName)
+	st := &MediaStats{}
+	err := d.db.QueryRow(sqlStr, constants.VISIBILITY_PRIVATE).Scan(&st.Total, &st.Private, &st.Deleted, &st.Users, &st.Size, &st.SavedBytes)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return st, nil
+}
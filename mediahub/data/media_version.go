@@ -0,0 +1,116 @@
+package data
+
+import (
+	"database/sql"
+	"enterprise-project1-mediahub/mediahub/pkg/constants"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
+	"fmt"
+)
+
+// MediaVersionEntity 表示媒体的一个历史版本
+type MediaVersionEntity struct {
+	ID           int64  `json:"id"`          // 主键ID
+	MediaID      int64  `json:"media_id"`    // 所属媒体ID
+	Version      int    `json:"version"`     // 版本号，从1开始递增
+	Md5          string `json:"md5"`         // 该版本内容的md5
+	ObjectPath   string `json:"object_path"` // 该版本在对象存储中的路径
+	OriginalPath string `json:"-"`           // 该版本未加水印的原图路径，未加水印时为空
+	Url          string `json:"url"`         // 该版本的公开访问地址，私有媒体为空
+	Size         int64  `json:"size"`        // 该版本文件大小（字节）
+	SavedBytes   int64  `json:"saved_bytes"` // 该版本上传优化节省的字节数
+	CreateAt     int64  `json:"create_at"`   // 创建时间戳
+}
+
+// IMediaVersionData 定义媒体版本数据操作的接口规范
+type IMediaVersionData interface {
+	// Create 新增版本记录，版本号取当前最大版本号加一并回填到 e.Version
+	Create(e *MediaVersionEntity) error
+
+	// List 查询媒体的全部版本，按版本号倒序
+	List(mediaID int64) ([]*MediaVersionEntity, error)
+
+	// Get 查询媒体的指定版本，不存在时返回nil
+	Get(mediaID int64, version int) (*MediaVersionEntity, error)
+}
+
+type mediaVersionData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewMediaVersionData 创建媒体版本数据操作对象
+func NewMediaVersionData(log log.ILogger, db *sql.DB) IMediaVersionData {
+	return &mediaVersionData{
+		log:       log,
+		db:        db,
+		tableName: constants.TABLENAME_MEDIA_VERSION,
+	}
+}
+
+// Create 新增版本记录
+// 版本号在同一条语句中由最大版本号计算，(media_id, version) 唯一索引保证并发替换时不会产生重复版本
+func (d *mediaVersionData) Create(e *MediaVersionEntity) error {
+	sqlStr := fmt.Sprintf("insert into %s (media_id,version,md5,object_path,original_path,url,size,saved_bytes,create_at) select ?,ifnull(max(version),0)+1,?,?,?,?,?,?,? from %s where media_id=?", d.tableName, d.tableName)
+	res, err := d.db.Exec(sqlStr, e.MediaID, e.Md5, e.ObjectPath, e.OriginalPath, e.Url, e.Size, e.SavedBytes, e.CreateAt, e.MediaID)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	e.ID, err = res.LastInsertId()
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	sqlStr = fmt.Sprintf("select version from %s where id=?", d.tableName)
+	err = d.db.QueryRow(sqlStr, e.ID).Scan(&e.Version)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// List 查询媒体的全部版本，按版本号倒序
+func (d *mediaVersionData) List(mediaID int64) ([]*MediaVersionEntity, error) {
+	sqlStr := fmt.Sprintf("select id,media_id,version,md5,object_path,original_path,url,size,saved_bytes,create_at from %s where media_id=? order by version desc", d.tableName)
+	rows, err := d.db.Query(sqlStr, mediaID)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*MediaVersionEntity, 0)
+	for rows.Next() {
+		e := &MediaVersionEntity{}
+		err = rows.Scan(&e.ID, &e.MediaID, &e.Version, &e.Md5, &e.ObjectPath, &e.OriginalPath, &e.Url, &e.Size, &e.SavedBytes, &e.CreateAt)
+		if err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		results = append(results, e)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
+
+// Get 查询媒体的指定版本
+func (d *mediaVersionData) Get(mediaID int64, version int) (*MediaVersionEntity, error) {
+	sqlStr := fmt.Sprintf("select id,media_id,version,md5,object_path,original_path,url,size,saved_bytes,create_at from %s where media_id=? and version=?", d.tableName)
+	e := &MediaVersionEntity{}
+	err := d.db.QueryRow(sqlStr, mediaID, version).Scan(&e.ID, &e.MediaID, &e.Version, &e.Md5, &e.ObjectPath, &e.OriginalPath, &e.Url, &e.Size, &e.SavedBytes, &e.CreateAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,240 @@
+package data
+
+import (
+	"database/sql"
+	"enterprise-project1-mediahub/mediahub/pkg/constants"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
+	"fmt"
+)
+
+// RoleEntity 管理角色及其权限
+type RoleEntity struct {
+	ID          int64    `json:"id"`          // 主键ID
+	Name        string   `json:"name"`        // 角色名称，唯一
+	Description string   `json:"description"` // 角色说明
+	Permissions []string `json:"permissions"` // 角色拥有的权限
+	CreateAt    int64    `json:"create_at"`   // 创建时间戳
+	UpdateAt    int64    `json:"update_at"`   // 最后更新时间戳
+}
+
+// IRbacData 定义角色、权限与授权数据操作的接口规范
+type IRbacData interface {
+	// ListRoles 查询全部角色及其权限，按名称排序
+	ListRoles() ([]*RoleEntity, error)
+
+	// SaveRole 按名称新增或修改角色，并用 e.Permissions 替换角色原有的权限
+	SaveRole(e *RoleEntity) error
+
+	// DeleteRole 删除角色及其权限和授权，返回角色是否存在
+	DeleteRole(name string) (bool, error)
+
+	// UserRoles 查询用户被授予的角色名称
+	UserRoles(userID int64) ([]string, error)
+
+	// AssignRole 授予用户角色，角色不存在时返回 false
+	AssignRole(userID int64, role string, now int64) (bool, error)
+
+	// UnassignRole 撤销用户的角色，返回是否有授权被撤销
+	UnassignRole(userID int64, role string) (bool, error)
+
+	// UserPermissions 查询用户通过角色获得的全部权限
+	UserPermissions(userID int64) ([]string, error)
+}
+
+type rbacData struct {
+	log             log.ILogger // 日志记录器
+	db              *sql.DB     // 数据库连接
+	roleTable       string      // 角色表名
+	permTable       string      // 角色权限表名
+	assignmentTable string      // 用户角色表名
+}
+
+// NewRbacData 创建角色权限数据操作对象
+func NewRbacData(log log.ILogger, db *sql.DB) IRbacData {
+	return &rbacData{
+		log:             log,
+		db:              db,
+		roleTable:       constants.TABLENAME_RBAC_ROLE,
+		permTable:       constants.TABLENAME_RBAC_ROLE_PERMISSION,
+		assignmentTable: constants.TABLENAME_RBAC_USER_ROLE,
+	}
+}
+
+// ListRoles 查询全部角色及其权限
+func (d *rbacData) ListRoles() ([]*RoleEntity, error) {
+	sqlStr := fmt.Sprintf("select r.id,r.name,r.description,r.create_at,r.update_at,ifnull(p.permission,'') from %s r left join %s p on p.role_id=r.id order by r.name,p.permission", d.roleTable, d.permTable)
+	rows, err := d.db.Query(sqlStr)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*RoleEntity, 0)
+	var last *RoleEntity
+	for rows.Next() {
+		e := &RoleEntity{Permissions: []string{}}
+		var perm string
+		if err = rows.Scan(&e.ID, &e.Name, &e.Description, &e.CreateAt, &e.UpdateAt, &perm); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		if last == nil || last.ID != e.ID {
+			last = e
+			results = append(results, e)
+		}
+		if perm != "" {
+			last.Permissions = append(last.Permissions, perm)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
+
+// SaveRole 在事务中写入角色并替换其权限，ID 字段会被回填
+func (d *rbacData) SaveRole(e *RoleEntity) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlStr := fmt.Sprintf("insert into %s (name,description,create_at,update_at)values(?,?,?,?) on duplicate key update description=values(description),update_at=values(update_at)", d.roleTable)
+	if _, err = tx.Exec(sqlStr, e.Name, e.Description, e.CreateAt, e.UpdateAt); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	sqlStr = fmt.Sprintf("select id,create_at from %s where name=?", d.roleTable)
+	if err = tx.QueryRow(sqlStr, e.Name).Scan(&e.ID, &e.CreateAt); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	sqlStr = fmt.Sprintf("delete from %s where role_id=?", d.permTable)
+	if _, err = tx.Exec(sqlStr, e.ID); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	sqlStr = fmt.Sprintf("insert ignore into %s (role_id,permission)values(?,?)", d.permTable)
+	for _, p := range e.Permissions {
+		if _, err = tx.Exec(sqlStr, e.ID, p); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteRole 在事务中删除角色及其权限和授权
+func (d *rbacData) DeleteRole(name string) (bool, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	sqlStr := fmt.Sprintf("select id from %s where name=? for update", d.roleTable)
+	err = tx.QueryRow(sqlStr, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	for _, table := range []string{d.assignmentTable, d.permTable} {
+		if _, err = tx.Exec(fmt.Sprintf("delete from %s where role_id=?", table), id); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return false, err
+		}
+	}
+	if _, err = tx.Exec(fmt.Sprintf("delete from %s where id=?", d.roleTable), id); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	if err = tx.Commit(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// UserRoles 查询用户被授予的角色名称
+func (d *rbacData) UserRoles(userID int64) ([]string, error) {
+	sqlStr := fmt.Sprintf("select r.name from %s u join %s r on r.id=u.role_id where u.user_id=? order by r.name", d.assignmentTable, d.roleTable)
+	return d.queryStrings(sqlStr, userID)
+}
+
+// AssignRole 授予用户角色，重复授予不报错
+func (d *rbacData) AssignRole(userID int64, role string, now int64) (bool, error) {
+	sqlStr := fmt.Sprintf("insert ignore into %s (user_id,role_id,create_at) select ?,id,? from %s where name=?", d.assignmentTable, d.roleTable)
+	if _, err := d.db.Exec(sqlStr, userID, now, role); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	// insert ignore 在已授予时影响行数为0，需要单独确认角色是否存在
+	var id int64
+	err := d.db.QueryRow(fmt.Sprintf("select id from %s where name=?", d.roleTable), role).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// UnassignRole 撤销用户的角色
+func (d *rbacData) UnassignRole(userID int64, role string) (bool, error) {
+	sqlStr := fmt.Sprintf("delete u from %s u join %s r on r.id=u.role_id where u.user_id=? and r.name=?", d.assignmentTable, d.roleTable)
+	res, err := d.db.Exec(sqlStr, userID, role)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// UserPermissions 查询用户通过角色获得的全部权限
+func (d *rbacData) UserPermissions(userID int64) ([]string, error) {
+	sqlStr := fmt.Sprintf("select distinct p.permission from %s u join %s p on p.role_id=u.role_id where u.user_id=?", d.assignmentTable, d.permTable)
+	return d.queryStrings(sqlStr, userID)
+}
+
+// queryStrings 查询单列字符串结果
+func (d *rbacData) queryStrings(sqlStr string, args ...any) ([]string, error) {
+	rows, err := d.db.Query(sqlStr, args...)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]string, 0)
+	for rows.Next() {
+		var s string
+		if err = rows.Scan(&s); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		results = append(results, s)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"database/sql"
+	"enterprise-project1-mediahub/mediahub/pkg/constants"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
+	"fmt"
+)
+
+// WatermarkEntity 用户的水印设置
+type WatermarkEntity struct {
+	UserID    int64   `json:"user_id"`    // 用户ID
+	Enable    bool    `json:"enable"`     // 是否为公开上传添加水印
+	Text      string  `json:"text"`       // 文字水印
+	ImagePath string  `json:"image_path"` // 图片水印在对象存储中的路径（私有对象），为空时使用文字水印
+	Position  string  `json:"position"`   // 水印位置，见 watermark.Position*
+	Opacity   float64 `json:"opacity"`    // 不透明度
+	Scale     float64 `json:"scale"`      // 水印宽度占原图宽度的比例
+	Margin    int     `json:"margin"`     // 水印与图片边缘的距离（像素）
+	UpdateAt  int64   `json:"update_at"`  // 最后更新时间戳
+}
+
+// IWatermarkData 定义水印设置数据操作的接口规范
+type IWatermarkData interface {
+	// Get 查询用户的水印设置，未设置时返回nil
+	Get(userID int64) (*WatermarkEntity, error)
+
+	// Save 保存用户的水印设置，已存在时覆盖
+	Save(e *WatermarkEntity) error
+}
+
+type watermarkData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewWatermarkData 创建水印设置数据操作对象
+func NewWatermarkData(log log.ILogger, db *sql.DB) IWatermarkData {
+	return &watermarkData{
+		log:       log,
+		db:        db,
+		tableName: constants.TABLENAME_WATERMARK,
+	}
+}
+
+// Get 查询用户的水印设置
+func (d *watermarkData) Get(userID int64) (*WatermarkEntity, error) {
+	sqlStr := fmt.Sprintf("select user_id,enable,text,image_path,position,opacity,scale,margin,update_at from %s where user_id=?", d.tableName)
+	e := &WatermarkEntity{}
+	err := d.db.QueryRow(sqlStr, userID).Scan(&e.UserID, &e.Enable, &e.Text, &e.ImagePath, &e.Position, &e.Opacity, &e.Scale, &e.Margin, &e.UpdateAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return e, nil
+}
+
+// Save 保存用户的水印设置
+func (d *watermarkData) Save(e *WatermarkEntity) error {
+	sqlStr := fmt.Sprintf("insert into %s (user_id,enable,text,image_path,position,opacity,scale,margin,update_at)values(?,?,?,?,?,?,?,?,?) "+
+		"on duplicate key update enable=values(enable),text=values(text),image_path=values(image_path),position=values(position),"+
+		"opacity=values(opacity),scale=values(scale),margin=values(margin),update_at=values(update_at)", d.tableName)
+	_, err := d.db.Exec(sqlStr, e.UserID, e.Enable, e.Text, e.ImagePath, e.Position, e.Opacity, e.Scale, e.Margin, e.UpdateAt)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
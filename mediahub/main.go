@@ -1,16 +1,26 @@
 package main
 
 import (
+	"context"
 	"enterprise-project1-mediahub/mediahub/controller"
+	"enterprise-project1-mediahub/mediahub/data"
 	"enterprise-project1-mediahub/mediahub/middleware"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
 	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"enterprise-project1-mediahub/mediahub/pkg/db/mysql"
+	"enterprise-project1-mediahub/mediahub/pkg/db/redis"
 	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/rbac"
+	"enterprise-project1-mediahub/mediahub/pkg/session"
 	"enterprise-project1-mediahub/mediahub/pkg/storage/cos"
+	"enterprise-project1-mediahub/mediahub/pkg/utils"
 	"enterprise-project1-mediahub/mediahub/routers"
+	"enterprise-project1-mediahub/mediahub/services/shorturl"
 	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
 var configFile = flag.String("config", "dev.config.yaml", "")
@@ -34,22 +44,65 @@ func main() {
 	logger.SetLevel(cnf.Log.Level)
 	logger.SetPrintCaller(true)
 
+	// 私有媒体的访问与下载链接使用该密钥签名，密钥为空或过短时任何人都能伪造签名
+	if len(cnf.Media.SignSecret) < utils.MinSignSecretLen {
+		log.FatalF("media.signSecret 不能少于 %d 个字符", utils.MinSignSecretLen)
+	}
+
+	// 初始化短链服务的 gRPC 连接池，TLS 证书配置无效时拒绝启动
+	if err := shorturl.InitShortUrlClientPool(cnf); err != nil {
+		log.FatalF("初始化短链服务连接池失败: %v", err)
+	}
+
+	// 初始化MySQL数据库连接池
+	mysql.InitMysql(cnf)
+	// 初始化Redis连接池
+	redis.InitRedisPool(cnf)
+	// 创建媒体、媒体版本、水印设置、API Key 及角色权限数据访问对象
+	mediaData := data.NewMediaData(logger, mysql.GetDB())
+	versionData := data.NewMediaVersionData(logger, mysql.GetDB())
+	watermarkData := data.NewWatermarkData(logger, mysql.GetDB())
+	apiKeyData := data.NewApiKeyData(logger, mysql.GetDB())
+	rbacData := data.NewRbacData(logger, mysql.GetDB())
+	// 管理权限校验器，用户权限按 TTL 缓存在内存中
+	enforcer := rbac.NewEnforcer(rbacData, time.Minute)
+
+	// 浏览器会话保存在 Redis 中
+	sessions := session.NewRedisStore(redis.GetPool(), time.Duration(cnf.Session.TTL)*time.Second)
+	cookies := session.NewCookies(cnf)
+
+	// 审计记录异步批量写入 MySQL，写入失败只记录日志
+	auditData := data.NewAuditData(logger, mysql.GetDB())
+	recorder := audit.NewRecorder("mediahub", auditData, func(err error) { log.Error(err) })
+
 	// 创建COS存储工厂实例，使用配置中的存储参数
 	sf := cos.NewCosStorageFactory(cnf.Cos.BucketUrl, cnf.Cos.SecretId, cnf.Cos.SecretKey, cnf.Cos.CDNDomain)
 
-	// 初始化控制器，传入存储工厂、日志记录器和全局配置
-	controller := controller.NewController(sf, logger, cnf)
+	// 初始化控制器，传入存储工厂、日志记录器、全局配置和数据访问对象
+	controller := controller.NewController(sf, logger, cnf, mediaData, versionData, watermarkData, apiKeyData, rbacData, enforcer, sessions, cookies, auditData, recorder)
 
 	// 设置Gin运行模式并创建路由分组
 	gin.SetMode(cnf.Http.Mode)
 	r := gin.Default()
-	r.Use(middleware.Cors(), middleware.Auth())
+	// 创建本地令牌校验器，remote 模式下为nil
+	verifier, err := middleware.NewJwtVerifier(context.Background(), cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// 用户服务令牌校验器，校验结果缓存在本地与 Redis
+	checker := middleware.NewRemoteChecker(cnf, redis.GetPool())
+	// 跨域策略来自配置，公开只读接口与需要认证的接口使用不同策略
+	corsHandler, err := middleware.Cors(cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r.Use(corsHandler, middleware.Audit(recorder), middleware.Auth(verifier, checker, apiKeyData, sessions, cookies))
 	// 这里是一次最简单的健康检查，后续可以进行健康检查的完善
 	r.GET("/health", func(*gin.Context) {})
 	api := r.Group("/api")
 
 	// 初始化API路由并绑定控制器
-	routers.InitRouters(api, controller)
+	routers.InitRouters(api, controller, enforcer)
 
 	// 配置静态文件服务和默认路由处理
 	fs := http.FileServer(http.Dir("www"))
@@ -61,7 +114,7 @@ func main() {
 	})
 
 	// 启动HTTP服务，监听指定IP和端口
-	err := r.Run(fmt.Sprintf("%s:%d", cnf.Http.IP, cnf.Http.Port))
+	err = r.Run(fmt.Sprintf("%s:%d", cnf.Http.IP, cnf.Http.Port))
 	if err != nil {
 	}
 	log.Fatal(err)
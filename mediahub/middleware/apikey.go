@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/utils"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiKeyTouchInterval 最后使用时间的更新间隔，避免每个请求都写库
+const apiKeyTouchInterval = 60
+
+// apiKeyFromRequest 从 X-Api-Key 头或 "Authorization: ApiKey ..." 中读取 API Key
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		return key
+	}
+	if after, ok := strings.CutPrefix(r.Header.Get("Authorization"), "ApiKey "); ok {
+		return strings.TrimSpace(after)
+	}
+	return ""
+}
+
+// apiKeyPrincipal 校验 API Key 并返回对应的身份，无效、过期或已撤销时返回nil
+func apiKeyPrincipal(apiKeys data.IApiKeyData, key string) (*auth.Principal, error) {
+	e, err := apiKeys.GetByHash(utils.HashApiKey(key))
+	if err != nil || e == nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if !e.IsActive(now) {
+		return nil, nil
+	}
+	if now-e.LastUsedAt >= apiKeyTouchInterval {
+		if err = apiKeys.TouchLastUsed(e.ID, now); err != nil {
+			log.Error(err)
+		}
+	}
+	return &auth.Principal{
+		UserID:   e.UserID,
+		Scopes:   e.Scopes,
+		Method:   auth.MethodApiKey,
+		ApiKeyID: e.ID,
+	}, nil
+}
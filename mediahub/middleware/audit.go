@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"github.com/gin-gonic/gin"
+)
+
+// auditKey 被拒绝请求的审计记录在 gin 上下文中的键
+const auditKey = "Audit.Rejected"
+
+// Audit 记录因认证失败或没有权限被拒绝的请求，需要放在认证中间件之前
+func Audit(recorder *audit.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if v, ok := c.Get(auditKey); ok {
+			recorder.Record(v.(*audit.Record))
+		}
+	}
+}
+
+// auditReject 标记请求被拒绝，由 Audit 中间件在请求结束后写入审计记录
+func auditReject(c *gin.Context, action, outcome, detail string) {
+	rec := audit.FromGin(c, action, outcome).
+		Target("route", c.Request.Method+" "+c.FullPath()).
+		WithDetail(detail)
+	c.Set(auditKey, rec)
+}
@@ -1,45 +1,189 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
+	"enterprise-project1-mediahub/mediahub/data"
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
 	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"enterprise-project1-mediahub/mediahub/pkg/jwtauth"
 	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/session"
 	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func Auth() gin.HandlerFunc {
+const authModeJwt = "jwt"
+
+// NewJwtVerifier 按配置创建本地令牌校验器，remote 模式返回nil
+// 配置了 JWKS 地址时立即拉取一次并在后台定时刷新，ctx 结束时停止刷新
+func NewJwtVerifier(ctx context.Context, cnf *config.Config) (*jwtauth.Verifier, error) {
+	if cnf.Auth.Mode != authModeJwt {
+		return nil, nil
+	}
+	jc := cnf.Auth.Jwt
+	staticKeys, err := jwtauth.NewStaticKeys(jc.HmacSecret, jc.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	keys := jwtauth.MultiKeys{staticKeys}
+	if jc.JwksUrl != "" {
+		jwks := jwtauth.NewJwksKeys(jc.JwksUrl, time.Duration(jc.JwksRefresh)*time.Second)
+		jwks.Start(ctx)
+		keys = append(keys, jwks)
+	}
+	return jwtauth.NewVerifier(jwtauth.Options{
+		Algorithms: jc.Algorithms,
+		Issuer:     jc.Issuer,
+		Audience:   jc.Audience,
+		ClockSkew:  time.Duration(jc.ClockSkew) * time.Second,
+	}, keys), nil
+}
+
+// Auth 解析请求中的凭证并把身份信息写入上下文
+// 凭证可以是 API Key（X-Api-Key 头或 "Authorization: ApiKey ..."），也可以是 Bearer 令牌：
+// verifier 为nil时调用用户服务校验令牌；否则在本地校验 JWT，开启 RemoteFallback 时本地无法校验的令牌回退到用户服务
+// 用户服务的校验通过 checker 进行，结果带缓存，用户服务不可用时降级使用缓存身份
+// 两者都没有时读取会话 Cookie，使用会话认证的状态变更请求需要通过 CSRF 校验
+func Auth(verifier *jwtauth.Verifier, checker *RemoteChecker, apiKeys data.IApiKeyData, sessions session.Store, cookies *session.Cookies) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := apiKeyFromRequest(c.Request); key != "" {
+			principal, err := apiKeyPrincipal(apiKeys, key)
+			if err != nil {
+				c.AbortWithStatus(http.StatusInternalServerError)
+				log.Error(err)
+				return
+			}
+			if principal == nil {
+				auditReject(c, audit.ActionAuthFailure, audit.OutcomeFailure, "API Key 无效或已过期")
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+			auth.SetPrincipal(c, principal)
+			c.Next()
+			return
+		}
+
 		token := strings.TrimPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
 		if token == "" {
-			c.Next()
+			if sessionAuth(c, sessions, cookies) {
+				c.Next()
+			}
 			return
 		}
-		user, err := checkAuth(token)
+
+		var principal *auth.Principal
+		var err error
+		if verifier != nil {
+			var claims jwt.MapClaims
+			claims, err = verifier.Verify(token)
+			if err == nil {
+				principal = principalFromClaims(claims)
+			}
+		}
+		if verifier == nil || (config.GetConfig().Auth.RemoteFallback &&
+			(errors.Is(err, jwtauth.ErrMalformed) || errors.Is(err, jwtauth.ErrNoKey))) {
+			var user *userInfo
+			user, err = checker.Check(token)
+			if err != nil {
+				c.AbortWithStatus(http.StatusServiceUnavailable)
+				log.Error(err)
+				return
+			}
+			if user != nil {
+				principal = &auth.Principal{
+					UserID:    user.ID,
+					Name:      user.Name,
+					AvatarUrl: user.AvatarUrl,
+					Roles:     user.Roles,
+					Method:    auth.MethodRemote,
+				}
+			}
+		}
 		if err != nil {
-			c.AbortWithStatus(http.StatusInternalServerError)
-			log.Error(err)
+			log.Debug(err)
+			auditReject(c, audit.ActionAuthFailure, audit.OutcomeFailure, "令牌无效："+err.Error())
+			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
-		if user == nil {
+		if principal == nil {
+			auditReject(c, audit.ActionAuthFailure, audit.OutcomeFailure, "令牌无效")
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
-		c.Set("User.ID", user.ID)
-		c.Set("User.Name", user.Name)
-		c.Set("User.AvatarUrl", user.AvatarUrl)
+		auth.SetPrincipal(c, principal)
 		c.Next()
 	}
 }
 
+// principalFromClaims 从 JWT 声明中读取身份信息
+// 用户ID优先取 uid，其次取 sub，两者都可以是数字或数字字符串；
+// 角色取 roles，权限范围取 scope（空格分隔）或 scp（数组），都没有时不限制权限范围
+func principalFromClaims(claims jwt.MapClaims) *auth.Principal {
+	id := claimInt64(claims["uid"])
+	if id == 0 {
+		id = claimInt64(claims["sub"])
+	}
+	if id == 0 {
+		return nil
+	}
+	p := &auth.Principal{UserID: id, Method: auth.MethodJwt}
+	p.Name, _ = claims["name"].(string)
+	p.AvatarUrl, _ = claims["avatar_url"].(string)
+	p.Roles = claimStrings(claims["roles"])
+	if scope, ok := claims["scope"]; ok {
+		p.Scopes = claimStrings(scope)
+	} else if scp, ok := claims["scp"]; ok {
+		p.Scopes = claimStrings(scp)
+	}
+	return p
+}
+
+func claimInt64(v any) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case json.Number:
+		id, _ := n.Int64()
+		return id
+	case string:
+		id, _ := strconv.ParseInt(n, 10, 64)
+		return id
+	}
+	return 0
+}
+
+// claimStrings 读取字符串数组或空格分隔的字符串声明
+func claimStrings(v any) []string {
+	switch s := v.(type) {
+	case string:
+		return strings.Fields(s)
+	case []any:
+		list := make([]string, 0, len(s))
+		for _, item := range s {
+			if str, ok := item.(string); ok {
+				list = append(list, str)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
 type userInfo struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	AvatarUrl string `json:"avatar_url"`
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	AvatarUrl string   `json:"avatar_url"`
+	Roles     []string `json:"roles"`
 }
 
 var httpClient = &http.Client{}
@@ -47,11 +191,13 @@ var httpClient = &http.Client{}
 func checkAuth(token string) (*userInfo, error) {
 	conf := config.GetConfig()
 	path := "/api/v1/login/check/auth"
-	url := fmt.Sprintf("%s%s?access_token=%s", conf.DependOn.User.Address, path, token)
+	url := fmt.Sprintf("%s%s", conf.DependOn.User.Address, path)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
+	// 令牌放在请求头中，避免出现在访问日志与代理日志里
+	req.Header.Set("Authorization", "Bearer "+token)
 	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -69,8 +215,6 @@ func checkAuth(token string) (*userInfo, error) {
 		return nil, err
 	}
 
-	log.Info("Response body: %s", string(body))
-
 	contentType := res.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "application/json") {
 		return nil, fmt.Errorf("unexpected content type: %s", contentType)
@@ -81,11 +225,5 @@ func checkAuth(token string) (*userInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal error: %w, response: %s", err, string(body))
 	}
-
-	//user := &userInfo{}
-	//err = json.Unmarshal(body, user)
-	//if err != nil {
-	//	return nil, err
-	//}
 	return user, nil
 }
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"enterprise-project1-mediahub/mediahub/pkg/breaker"
+	"enterprise-project1-mediahub/mediahub/pkg/cache/local"
+	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"enterprise-project1-mediahub/mediahub/pkg/db/redis"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+// 用户服务校验结果缓存的默认时长
+const (
+	defaultAuthCacheTTL    = 300  // 校验结果的新鲜期（秒），期内不再调用用户服务
+	defaultAuthNegativeTTL = 30   // 无效令牌的缓存时长（秒）
+	defaultAuthStaleTTL    = 3600 // 有效身份的最长保留时长（秒），用户服务不可用时降级使用
+)
+
+// ErrUserServiceUnavailable 用户服务不可用且没有可降级使用的缓存身份
+var ErrUserServiceUnavailable = zerror.NewByMsg("用户服务不可用")
+
+// authEntry 缓存的令牌校验结果
+type authEntry struct {
+	User      *userInfo `json:"user"`       // 有效令牌对应的用户，无效令牌为nil
+	CheckedAt int64     `json:"checked_at"` // 校验时间戳
+}
+
+// RemoteChecker 带缓存与熔断的用户服务令牌校验
+// 结果以令牌哈希为键写入本地缓存与 Redis，缓存时长不超过令牌本身的过期时间；
+// 用户服务连续失败时熔断，熔断期间及调用失败时降级使用已缓存的身份
+type RemoteChecker struct {
+	local       local.LocalCache
+	redisPool   redis.RedisPool
+	breaker     *breaker.Breaker
+	ttl         time.Duration
+	negativeTTL time.Duration
+	staleTTL    time.Duration
+	check       func(token string) (*userInfo, error)
+}
+
+// NewRemoteChecker 按配置创建用户服务令牌校验器，redisPool 为nil时只使用本地缓存
+func NewRemoteChecker(cnf *config.Config, redisPool redis.RedisPool) *RemoteChecker {
+	cc := cnf.Auth.Cache
+	return &RemoteChecker{
+		local:       local.NewMemoryCache(),
+		redisPool:   redisPool,
+		breaker:     breaker.NewBreaker(cnf.Auth.Breaker.FailureThreshold, time.Duration(cnf.Auth.Breaker.OpenSeconds)*time.Second),
+		ttl:         seconds(cc.TTL, defaultAuthCacheTTL),
+		negativeTTL: seconds(cc.NegativeTTL, defaultAuthNegativeTTL),
+		staleTTL:    seconds(cc.StaleTTL, defaultAuthStaleTTL),
+		check:       checkAuth,
+	}
+}
+
+func seconds(v, def int) time.Duration {
+	if v <= 0 {
+		v = def
+	}
+	return time.Duration(v) * time.Second
+}
+
+// Check 校验令牌，无效令牌返回nil
+func (r *RemoteChecker) Check(token string) (*userInfo, error) {
+	key := redis.GetKey("auth", tokenHash(token))
+	now := time.Now()
+	entry := r.get(key)
+	if entry != nil && now.Sub(time.Unix(entry.CheckedAt, 0)) < r.ttl {
+		return entry.User, nil
+	}
+
+	if !r.breaker.Allow() {
+		if entry != nil && entry.User != nil {
+			return entry.User, nil
+		}
+		return nil, ErrUserServiceUnavailable
+	}
+	user, err := r.check(token)
+	if err != nil {
+		r.breaker.Failure()
+		if entry != nil && entry.User != nil {
+			log.Warning("用户服务校验失败，使用缓存身份：", err)
+			return entry.User, nil
+		}
+		return nil, err
+	}
+	r.breaker.Success()
+
+	ttl := r.negativeTTL
+	if user != nil {
+		ttl = r.staleTTL
+		// 缓存时长不超过令牌本身的过期时间
+		if exp, ok := tokenExpiry(token); ok {
+			if left := time.Until(exp); left < ttl {
+				ttl = left
+			}
+		}
+	}
+	if ttl > 0 {
+		r.set(key, &authEntry{User: user, CheckedAt: now.Unix()}, ttl)
+	}
+	return user, nil
+}
+
+// get 依次从本地缓存与 Redis 读取，Redis 命中时回填本地缓存
+func (r *RemoteChecker) get(key string) *authEntry {
+	val, ok := r.local.Get(key)
+	if !ok && r.redisPool != nil {
+		client := r.redisPool.Get()
+		defer r.redisPool.Put(client)
+		ctx := context.Background()
+		v, err := client.Get(ctx, key).Result()
+		if err == nil {
+			val, ok = v, true
+			if ttl, err := client.TTL(ctx, key).Result(); err == nil && ttl > 0 {
+				r.local.Set(key, val, ttl)
+			}
+		}
+	}
+	if !ok {
+		return nil
+	}
+	entry := &authEntry{}
+	if err := json.Unmarshal([]byte(val), entry); err != nil {
+		log.Error(err)
+		return nil
+	}
+	return entry
+}
+
+// set 写入本地缓存与 Redis，Redis 写入失败只记录日志
+func (r *RemoteChecker) set(key string, entry *authEntry, ttl time.Duration) {
+	bs, err := json.Marshal(entry)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	r.local.Set(key, string(bs), ttl)
+	if r.redisPool == nil {
+		return
+	}
+	client := r.redisPool.Get()
+	defer r.redisPool.Put(client)
+	if err = client.Set(context.Background(), key, string(bs), ttl).Err(); err != nil {
+		log.Error(err)
+	}
+}
+
+// tokenHash 计算令牌的哈希，避免令牌明文出现在缓存键中
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenExpiry 读取 JWT 形式令牌中的过期时间（不校验签名），非 JWT 令牌返回 false
+func tokenExpiry(token string) (time.Time, bool) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return time.Time{}, false
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return time.Time{}, false
+	}
+	return exp.Time, true
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/breaker"
+	"enterprise-project1-mediahub/mediahub/pkg/cache/local"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestChecker(check func(string) (*userInfo, error)) *RemoteChecker {
+	return &RemoteChecker{
+		local:       local.NewMemoryCache(),
+		breaker:     breaker.NewBreaker(2, time.Minute),
+		ttl:         0, // 每次都视为过期，强制走用户服务，便于观察降级
+		negativeTTL: time.Minute,
+		staleTTL:    time.Hour,
+		check:       check,
+	}
+}
+
+func TestRemoteCheckerNegativeCache(t *testing.T) {
+	calls := 0
+	r := newTestChecker(func(string) (*userInfo, error) {
+		calls++
+		return nil, nil
+	})
+	r.ttl = time.Minute
+	for i := 0; i < 3; i++ {
+		user, err := r.Check("bad")
+		if err != nil || user != nil {
+			t.Fatalf("expected invalid token, got %v %v", user, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRemoteCheckerDegradesToCachedIdentity(t *testing.T) {
+	down := false
+	calls := 0
+	r := newTestChecker(func(string) (*userInfo, error) {
+		calls++
+		if down {
+			return nil, errors.New("connection refused")
+		}
+		return &userInfo{ID: 7, Name: "bob"}, nil
+	})
+	if user, err := r.Check("good"); err != nil || user.ID != 7 {
+		t.Fatalf("unexpected %v %v", user, err)
+	}
+
+	down = true
+	for i := 0; i < 5; i++ {
+		user, err := r.Check("good")
+		if err != nil || user == nil || user.ID != 7 {
+			t.Fatalf("expected cached identity, got %v %v", user, err)
+		}
+	}
+	// 熔断后不再调用用户服务
+	if calls != 3 {
+		t.Errorf("expected breaker to stop calls after 2 failures, got %d calls", calls)
+	}
+	if _, err := r.Check("unknown"); !errors.Is(err, ErrUserServiceUnavailable) {
+		t.Errorf("expected ErrUserServiceUnavailable, got %v", err)
+	}
+}
@@ -1,24 +1,153 @@
 package middleware
 
 import (
+	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"errors"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 )
 
-func Cors() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowAllOrigins: true,
-		AllowHeaders: []string{
-			"Origin", "Content-Length", "Content-Type", "Authorization",
-		},
-		AllowMethods: []string{
-			"GET", "POST", "PUT", "DELETE", "HEAD", "OPTIONS",
-		},
-		ExposeHeaders: []string{
-			"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Cache-Control", "Content-Language", "Content-Type",
-		},
-		AllowCredentials: true,
-	})
+// 跨域策略的默认值
+var (
+	defaultCorsHeaders = []string{
+		"Origin", "Content-Length", "Content-Type", "Authorization", "X-Api-Key", CsrfHeader,
+	}
+	defaultCorsExposeHeaders = []string{
+		"Content-Length", "Cache-Control", "Content-Language", "Content-Type",
+	}
+	defaultPublicMethods  = []string{"GET", "HEAD", "OPTIONS"}
+	defaultPrivateMethods = []string{"GET", "POST", "PUT", "DELETE", "HEAD", "OPTIONS"}
+)
+
+const defaultCorsMaxAge = 600
+
+// corsPolicy 编译后的跨域策略
+type corsPolicy struct {
+	origins  []string         // 精确匹配的来源
+	suffixes []string         // 通配子域名，如 https://*.example.com 保存为 scheme 与 .example.com
+	schemes  []string         // 与 suffixes 一一对应的协议
+	patterns []*regexp.Regexp // 反射匹配的来源正则，编译时加上 ^ 与 $ 匹配完整来源
+	any      bool             // 允许任意来源
+	handler  gin.HandlerFunc
+}
+
+// allow 判断来源是否符合策略
+func (p *corsPolicy) allow(origin string) bool {
+	if p.any {
+		return true
+	}
+	for _, o := range p.origins {
+		if o == origin {
+			return true
+		}
+	}
+	if len(p.suffixes) > 0 {
+		if u, err := url.Parse(origin); err == nil && u.Path == "" {
+			for i, suffix := range p.suffixes {
+				if u.Scheme == p.schemes[i] && strings.HasSuffix(u.Host, suffix) && len(u.Host) > len(suffix) {
+					return true
+				}
+			}
+		}
+	}
+	for _, re := range p.patterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCorsPolicy 编译跨域策略，defaultAny 为 true 时未配置来源则允许任意来源
+func newCorsPolicy(cnf config.CorsPolicy, defaultMethods []string, defaultAny bool) (*corsPolicy, error) {
+	p := &corsPolicy{any: defaultAny && len(cnf.AllowOrigins) == 0 && len(cnf.OriginPatterns) == 0}
+	for _, o := range cnf.AllowOrigins {
+		o = strings.TrimSuffix(strings.ToLower(o), "/")
+		switch {
+		case o == "*":
+			p.any = true
+		case strings.Contains(o, "://*."):
+			scheme, host, _ := strings.Cut(o, "://*.")
+			p.schemes = append(p.schemes, scheme)
+			p.suffixes = append(p.suffixes, "."+host)
+		default:
+			p.origins = append(p.origins, o)
+		}
+	}
+	for _, s := range cnf.OriginPatterns {
+		// 总是匹配完整来源，未写 ^...$ 的正则也不会匹配 https://pr-1.preview.example.com.evil.net 这样的来源
+		re, err := regexp.Compile("^(?:" + s + ")$")
+		if err != nil {
+			return nil, err
+		}
+		p.patterns = append(p.patterns, re)
+	}
+	if p.any && cnf.AllowCredentials {
+		return nil, errors.New("cors: allowing any origin together with credentials is not permitted")
+	}
+
+	c := cors.Config{
+		// 统一由 allow 判断，匹配的来源原样写入 Access-Control-Allow-Origin
+		AllowOriginFunc:  p.allow,
+		AllowMethods:     cnf.AllowMethods,
+		AllowHeaders:     cnf.AllowHeaders,
+		ExposeHeaders:    cnf.ExposeHeaders,
+		AllowCredentials: cnf.AllowCredentials,
+		MaxAge:           time.Duration(cnf.MaxAge) * time.Second,
+	}
+	if len(c.AllowMethods) == 0 {
+		c.AllowMethods = defaultMethods
+	}
+	if len(c.AllowHeaders) == 0 {
+		c.AllowHeaders = defaultCorsHeaders
+	}
+	if len(c.ExposeHeaders) == 0 {
+		c.ExposeHeaders = defaultCorsExposeHeaders
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = defaultCorsMaxAge * time.Second
+	}
+	p.handler = cors.New(c)
+	return p, nil
+}
+
+// Cors 按配置处理跨域请求
+// 来源符合 Private 策略时使用 Private 策略；否则只读请求（含其预检）使用 Public 策略；
+// 其余跨域请求按 Private 策略处理，来源不被允许时返回 403
+func Cors(cnf *config.Config) (gin.HandlerFunc, error) {
+	public, err := newCorsPolicy(cnf.Cors.Public, defaultPublicMethods, true)
+	if err != nil {
+		return nil, err
+	}
+	private, err := newCorsPolicy(cnf.Cors.Private, defaultPrivateMethods, false)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		switch {
+		case origin == "":
+			c.Next()
+		case !private.allow(origin) && isReadOnly(c.Request) && public.allow(origin):
+			public.handler(c)
+		default:
+			private.handler(c)
+		}
+	}, nil
+}
+
+// isReadOnly 判断请求是否为只读请求，预检请求按实际要发送的方法判断
+func isReadOnly(r *http.Request) bool {
+	method := r.Method
+	if method == http.MethodOptions {
+		method = r.Header.Get("Access-Control-Request-Method")
+	}
+	return method == http.MethodGet || method == http.MethodHead
 }
 
 // Cors 相较于手动实现，更推荐使用跨域包github.com/gin-contrib/cors
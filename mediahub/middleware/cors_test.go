@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newCorsEngine(t *testing.T, cnf *config.Config) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h, err := Cors(cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	r.Use(h)
+	r.GET("/home", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/upload", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+// corsRequest 发送跨域请求，返回状态码、允许的来源与是否允许携带凭证
+func corsRequest(r *gin.Engine, method, path, origin string) (int, string, string) {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code, w.Header().Get("Access-Control-Allow-Origin"), w.Header().Get("Access-Control-Allow-Credentials")
+}
+
+func TestCorsPolicies(t *testing.T) {
+	cnf := &config.Config{}
+	cnf.Cors.Private = config.CorsPolicy{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		OriginPatterns:   []string{`https://pr-[0-9]+\.preview\.example\.com`},
+		AllowCredentials: true,
+	}
+	r := newCorsEngine(t, cnf)
+
+	cases := []struct {
+		method, path, origin string
+		code                 int
+		allowOrigin, creds   string
+	}{
+		// 配置的来源、通配子域名与预览部署可以携带凭证调用写接口
+		{http.MethodPost, "/upload", "https://app.example.com", http.StatusOK, "https://app.example.com", "true"},
+		{http.MethodPost, "/upload", "https://a.b.example.org", http.StatusOK, "https://a.b.example.org", "true"},
+		{http.MethodOptions, "/upload", "https://pr-12.preview.example.com", http.StatusNoContent, "https://pr-12.preview.example.com", "true"},
+		// 通配子域名不匹配主域名本身、其他协议和相似域名
+		{http.MethodPost, "/upload", "https://example.org", http.StatusForbidden, "", ""},
+		{http.MethodPost, "/upload", "http://a.example.org", http.StatusForbidden, "", ""},
+		{http.MethodPost, "/upload", "https://evilexample.org", http.StatusForbidden, "", ""},
+		// 来源正则匹配完整来源，不匹配带前缀或后缀的域名
+		{http.MethodPost, "/upload", "https://pr-1.preview.example.com.evil.net", http.StatusForbidden, "", ""},
+		{http.MethodPost, "/upload", "https://evil.net/https://pr-1.preview.example.com", http.StatusForbidden, "", ""},
+		// 其他来源只能不带凭证读取公开接口
+		{http.MethodGet, "/home", "https://other.com", http.StatusOK, "https://other.com", ""},
+		{http.MethodPost, "/upload", "https://other.com", http.StatusForbidden, "", ""},
+		{http.MethodOptions, "/upload", "https://other.com", http.StatusForbidden, "", ""},
+	}
+	for _, c := range cases {
+		code, origin, creds := corsRequest(r, c.method, c.path, c.origin)
+		if code != c.code || origin != c.allowOrigin || creds != c.creds {
+			t.Errorf("%s %s from %s: got %d %q %q, want %d %q %q", c.method, c.path, c.origin, code, origin, creds, c.code, c.allowOrigin, c.creds)
+		}
+	}
+}
+
+func TestCorsRejectsAnyOriginWithCredentials(t *testing.T) {
+	cnf := &config.Config{}
+	cnf.Cors.Private = config.CorsPolicy{AllowOrigins: []string{"*"}, AllowCredentials: true}
+	if _, err := Cors(cnf); err == nil {
+		t.Error("expected error")
+	}
+}
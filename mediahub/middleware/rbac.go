@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/audit"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"enterprise-project1-mediahub/mediahub/pkg/rbac"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RequirePermission 要求已登录用户通过角色拥有指定权限
+// 管理操作只接受用户本人的登录凭证，API Key 不能用于管理操作
+func RequirePermission(enforcer *rbac.Enforcer, perm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := auth.GetPrincipal(c)
+		if p == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+			return
+		}
+		if p.Method == auth.MethodApiKey {
+			auditReject(c, audit.ActionAccessDenied, audit.OutcomeDenied, "API Key 不能用于管理操作")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API Key 不能用于管理操作"})
+			return
+		}
+		allowed, err := enforcer.Allow(p.UserID, perm)
+		if err != nil {
+			log.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		if !allowed {
+			auditReject(c, audit.ActionAccessDenied, audit.OutcomeDenied, "缺少权限 "+perm)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "没有权限"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RequireLogin 要求请求已登录
+func RequireLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth.GetPrincipal(c) == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole 要求已登录且拥有任一指定角色
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := auth.GetPrincipal(c)
+		if p == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+			return
+		}
+		if !p.HasRole(roles...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "没有权限"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope 要求调用方的凭证拥有全部指定权限范围
+// 权限范围用于限制凭证能做的事情，匿名请求是否放行由 RequireLogin 决定
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := auth.GetPrincipal(c)
+		if p != nil && !p.HasScope(scopes...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "凭证权限不足"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"enterprise-project1-mediahub/mediahub/pkg/session"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// memoryStore 测试用的内存会话存储
+type memoryStore struct {
+	sessions map[string]*session.Session
+}
+
+func (s *memoryStore) Create(p *auth.Principal, userAgent, ip string) (*session.Session, string, error) {
+	token, _ := session.NewToken()
+	csrf, _ := session.NewToken()
+	sess := &session.Session{ID: session.ID(token), Principal: p, CsrfToken: csrf, ExpireAt: time.Now().Add(time.Hour).Unix()}
+	s.sessions[sess.ID] = sess
+	return sess, token, nil
+}
+
+func (s *memoryStore) Get(token string) (*session.Session, error) {
+	return s.sessions[session.ID(token)], nil
+}
+
+func (s *memoryStore) List(userID int64) ([]*session.Session, error) { return nil, nil }
+
+func (s *memoryStore) Delete(userID int64, id string) (bool, error) {
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	return ok, nil
+}
+
+func (s *memoryStore) DeleteUser(userID int64) (int, error) { return 0, nil }
+
+func TestSessionCsrf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryStore{sessions: map[string]*session.Session{}}
+	cookies := session.NewCookies(&config.Config{})
+	sess, token, _ := store.Create(&auth.Principal{UserID: 7, Method: auth.MethodRemote}, "", "")
+
+	r := gin.New()
+	r.Use(Auth(nil, nil, nil, store, cookies))
+	handler := func(c *gin.Context) {
+		p := auth.GetPrincipal(c)
+		if p == nil {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		c.String(http.StatusOK, p.Method)
+	}
+	r.GET("/media", handler)
+	r.DELETE("/media", handler)
+
+	send := func(method, sessionToken, csrfCookie, csrfHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/media", nil)
+		if sessionToken != "" {
+			req.AddCookie(&http.Cookie{Name: cookies.Name, Value: sessionToken})
+		}
+		if csrfCookie != "" {
+			req.AddCookie(&http.Cookie{Name: cookies.CsrfName, Value: csrfCookie})
+		}
+		if csrfHeader != "" {
+			req.Header.Set(CsrfHeader, csrfHeader)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	cases := []struct {
+		name                  string
+		method                string
+		token, cookie, header string
+		code                  int
+	}{
+		{"read without csrf", http.MethodGet, token, "", "", http.StatusOK},
+		{"write with csrf", http.MethodDelete, token, sess.CsrfToken, sess.CsrfToken, http.StatusOK},
+		{"write without header", http.MethodDelete, token, sess.CsrfToken, "", http.StatusForbidden},
+		{"write without cookie", http.MethodDelete, token, "", sess.CsrfToken, http.StatusForbidden},
+		// 攻击者可以设置任意 CSRF Cookie，但不知道会话中保存的令牌
+		{"forged double submit", http.MethodDelete, token, "forged", "forged", http.StatusForbidden},
+		{"unknown session", http.MethodGet, "unknown", "", "", http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		if w := send(c.method, c.token, c.cookie, c.header); w.Code != c.code {
+			t.Errorf("%s: expected %d, got %d", c.name, c.code, w.Code)
+		}
+	}
+	if w := send(http.MethodGet, token, "", ""); w.Body.String() != auth.MethodSession {
+		t.Errorf("expected session method, got %q", w.Body.String())
+	}
+
+	// 缺少身份的会话按匿名请求处理
+	brokenToken, _ := session.NewToken()
+	store.sessions[session.ID(brokenToken)] = &session.Session{ID: session.ID(brokenToken), CsrfToken: sess.CsrfToken}
+	if w := send(http.MethodDelete, brokenToken, sess.CsrfToken, sess.CsrfToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("session without principal: expected 401, got %d", w.Code)
+	}
+
+	// 退出登录后会话 Cookie 不再有效
+	store.Delete(7, sess.ID)
+	if w := send(http.MethodGet, token, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("revoked session: expected 401, got %d", w.Code)
+	}
+}
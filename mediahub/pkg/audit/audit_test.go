@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type memoryWriter struct {
+	mu      sync.Mutex
+	records []*Record
+	batches int
+	err     error
+}
+
+func (w *memoryWriter) Append(records []*Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.batches++
+	w.records = append(w.records, records...)
+	return w.err
+}
+
+func TestRecorder(t *testing.T) {
+	w := &memoryWriter{}
+	r := NewRecorder("mediahub", w, nil)
+	for i := 0; i < 250; i++ {
+		r.Record(&Record{Action: ActionMediaUpload, Outcome: OutcomeSuccess})
+	}
+	r.Close()
+
+	if len(w.records) != 250 {
+		t.Fatalf("expected 250 records, got %d", len(w.records))
+	}
+	if w.batches > 250/batchSize+2 {
+		t.Errorf("records not batched: %d batches", w.batches)
+	}
+	for _, rec := range w.records {
+		if rec.Service != "mediahub" || rec.CreateAt == 0 {
+			t.Fatalf("record not filled: %+v", rec)
+		}
+	}
+
+	// 关闭后改为同步写入，不丢弃记录
+	r.Record(&Record{Action: ActionAuthFailure})
+	if len(w.records) != 251 {
+		t.Errorf("record after close dropped")
+	}
+
+	var nilRecorder *Recorder
+	nilRecorder.Record(&Record{})
+}
+
+func TestRecorderError(t *testing.T) {
+	w := &memoryWriter{err: errors.New("db down")}
+	var got error
+	r := NewRecorder("mediahub", w, func(err error) { got = err })
+	r.Record(&Record{Action: ActionLinkPurge})
+	r.Close()
+	if got == nil {
+		t.Error("write error not reported")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if s := truncate("abc", 5); s != "abc" {
+		t.Errorf("short string changed: %q", s)
+	}
+	// 多字节字符不会被截断一半
+	s := truncate(strings.Repeat("审", 10), 4)
+	if s != "审" {
+		t.Errorf("expected one full rune, got %q", s)
+	}
+}
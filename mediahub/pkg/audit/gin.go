@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// FromGin 按请求创建审计记录，操作者取自认证中间件写入的身份
+func FromGin(c *gin.Context, action, outcome string) *Record {
+	rec := &Record{
+		ActorType: ActorAnonymous,
+		Action:    action,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Outcome:   outcome,
+	}
+	if p := auth.GetPrincipal(c); p != nil {
+		rec.ActorType = ActorUser
+		rec.ActorID = p.UserID
+		rec.ActorName = p.Name
+		if p.Method == auth.MethodApiKey {
+			rec.ActorType = ActorApiKey
+		}
+	}
+	return rec
+}
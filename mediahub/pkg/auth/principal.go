@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+// principalKey 身份信息在 gin 上下文中的键
+const principalKey = "Auth.Principal"
+
+// 认证方式
+const (
+	MethodJwt     = "jwt"     // 本地校验 JWT
+	MethodRemote  = "remote"  // 用户服务校验令牌
+	MethodApiKey  = "api_key" // API Key
+	MethodSession = "session" // 浏览器会话 Cookie
+)
+
+// 权限范围
+const (
+	ScopeMediaRead   = "media:read"   // 查看媒体
+	ScopeMediaUpload = "media:upload" // 上传新媒体
+	ScopeMediaWrite  = "media:write"  // 替换、删除、撤销等修改已有媒体的操作
+)
+
+// Principal 已认证的调用方身份
+type Principal struct {
+	UserID    int64    `json:"user_id"`    // 用户ID
+	Name      string   `json:"name"`       // 用户名
+	AvatarUrl string   `json:"avatar_url"` // 头像地址
+	Roles     []string `json:"roles"`      // 角色
+	Scopes    []string `json:"scopes"`     // 权限范围，为nil表示不受限（交互式登录）
+	Method    string   `json:"method"`     // 认证方式，见 Method* 常量
+	ApiKeyID  int64    `json:"api_key_id"` // 使用 API Key 认证时的密钥ID
+}
+
+// HasRole 判断是否拥有任一角色
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, r := range roles {
+		for _, own := range p.Roles {
+			if own == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope 判断是否拥有全部权限范围
+// Scopes 为nil时不受限；否则逐个匹配，支持 "*" 与 "media:*" 形式的通配
+func (p *Principal) HasScope(scopes ...string) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range scopes {
+		if !p.hasScope(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Principal) hasScope(scope string) bool {
+	for _, own := range p.Scopes {
+		if own == "*" || own == scope {
+			return true
+		}
+		if strings.HasSuffix(own, ":*") && strings.HasPrefix(scope, strings.TrimSuffix(own, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPrincipal 将身份信息写入上下文，由认证中间件调用
+func SetPrincipal(ctx *gin.Context, p *Principal) {
+	ctx.Set(principalKey, p)
+}
+
+// GetPrincipal 读取上下文中的身份信息，匿名请求返回nil
+func GetPrincipal(ctx *gin.Context) *Principal {
+	v, ok := ctx.Get(principalKey)
+	if !ok {
+		return nil
+	}
+	p, _ := v.(*Principal)
+	return p
+}
+
+// UserID 返回当前登录用户ID，匿名请求返回0
+func UserID(ctx *gin.Context) int64 {
+	if p := GetPrincipal(ctx); p != nil {
+		return p.UserID
+	}
+	return 0
+}
+
+// UserName 返回当前登录用户名，匿名请求返回空字符串
+func UserName(ctx *gin.Context) string {
+	if p := GetPrincipal(ctx); p != nil {
+		return p.Name
+	}
+	return ""
+}
@@ -0,0 +1,29 @@
+package auth
+
+import "testing"
+
+func TestHasScope(t *testing.T) {
+	p := &Principal{}
+	if !p.HasScope(ScopeMediaWrite) {
+		t.Error("nil scopes should be unrestricted")
+	}
+	p.Scopes = []string{ScopeMediaRead}
+	if !p.HasScope(ScopeMediaRead) || p.HasScope(ScopeMediaWrite) {
+		t.Error("read-only scope mismatch")
+	}
+	p.Scopes = []string{"media:*"}
+	if !p.HasScope(ScopeMediaRead, ScopeMediaWrite) || p.HasScope("admin:read") {
+		t.Error("wildcard scope mismatch")
+	}
+	p.Scopes = []string{}
+	if p.HasScope(ScopeMediaRead) {
+		t.Error("empty scopes should grant nothing")
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	p := &Principal{Roles: []string{"editor"}}
+	if !p.HasRole("admin", "editor") || p.HasRole("admin") {
+		t.Error("role mismatch")
+	}
+}
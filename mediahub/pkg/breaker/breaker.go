@@ -0,0 +1,96 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// 熔断器状态
+const (
+	StateClosed   = "closed"    // 正常放行
+	StateOpen     = "open"      // 熔断中，拒绝调用
+	StateHalfOpen = "half-open" // 熔断时间已过，放行一次探测调用
+)
+
+// Breaker 熔断器
+// 连续失败 threshold 次后熔断，熔断 openTimeout 后放行一次探测调用：
+// 探测成功则恢复，失败则重新熔断
+type Breaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker 创建熔断器
+func NewBreaker(threshold int, openTimeout time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if openTimeout <= 0 {
+		openTimeout = 30 * time.Second
+	}
+	return &Breaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		state:       StateClosed,
+	}
+}
+
+// Allow 判断是否允许本次调用
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return true
+	case StateHalfOpen:
+		// 半开状态只放行一个探测调用
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// Success 记录一次成功调用
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure 记录一次失败调用
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State 返回当前状态
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,39 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	b := NewBreaker(2, 50*time.Millisecond)
+	b.Failure()
+	if !b.Allow() {
+		t.Fatal("should allow below threshold")
+	}
+	b.Failure()
+	if b.State() != StateOpen || b.Allow() {
+		t.Fatal("should open after threshold")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("should allow probe after timeout")
+	}
+	if b.Allow() {
+		t.Fatal("should allow only one probe")
+	}
+	b.Failure()
+	if b.State() != StateOpen {
+		t.Fatal("failed probe should reopen")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("should allow probe after timeout")
+	}
+	b.Success()
+	if b.State() != StateClosed || !b.Allow() {
+		t.Fatal("successful probe should close")
+	}
+}
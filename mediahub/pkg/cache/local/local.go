@@ -0,0 +1,103 @@
+package local
+
+import (
+	"sync"
+	"time"
+)
+
+// LocalCache 本地缓存接口
+type LocalCache interface {
+	// Get 从本地缓存中获取值
+	Get(key string) (string, bool)
+	// Set 将值存储到本地缓存中，ttl 为0表示不过期
+	Set(key string, value string, ttl time.Duration)
+	// Delete 从本地缓存中删除值
+	Delete(key string)
+	// Clear 清空本地缓存
+	Clear()
+}
+
+// MemoryCache 基于内存的本地缓存实现
+type MemoryCache struct {
+	cache map[string]cacheItem
+	mutex sync.RWMutex
+}
+
+// cacheItem 本地缓存的缓存项，包含值和过期时间
+type cacheItem struct {
+	value      string
+	expiration time.Time
+}
+
+// NewMemoryCache 创建一个新的内存缓存，并启动过期项的定期清理
+func NewMemoryCache() LocalCache {
+	cache := &MemoryCache{
+		cache: make(map[string]cacheItem),
+	}
+	go cache.cleanExpiredItems()
+	return cache
+}
+
+// Get 从本地缓存中获取值
+func (c *MemoryCache) Get(key string) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	item, exists := c.cache[key]
+	if !exists {
+		return "", false
+	}
+	if !item.expiration.IsZero() && time.Now().After(item.expiration) {
+		go c.Delete(key)
+		return "", false
+	}
+	return item.value, true
+}
+
+// Set 将值存储到本地缓存中
+func (c *MemoryCache) Set(key string, value string, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var expiration time.Time
+	if ttl > 0 {
+		expiration = time.Now().Add(ttl)
+	}
+	c.cache[key] = cacheItem{
+		value:      value,
+		expiration: expiration,
+	}
+}
+
+// Delete 从本地缓存中删除值
+func (c *MemoryCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.cache, key)
+}
+
+// Clear 清空本地缓存
+func (c *MemoryCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cache = make(map[string]cacheItem)
+}
+
+// cleanExpiredItems 定期清理过期的缓存项
+func (c *MemoryCache) cleanExpiredItems() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mutex.Lock()
+		now := time.Now()
+		for key, item := range c.cache {
+			if !item.expiration.IsZero() && now.After(item.expiration) {
+				delete(c.cache, key)
+			}
+		}
+		c.mutex.Unlock()
+	}
+}
@@ -64,8 +64,8 @@ func (f *Factory) NewRedisClient() *redis.Client {
 		DB:           f.config.DB,
 		PoolSize:     f.config.PoolSize,
 		MinIdleConns: f.config.MinIdleConns,
-		MaxConnAge:   f.config.MaxConnAge,
-		IdleTimeout:  f.config.IdleTimeout,
+		ConnMaxLifetime: f.config.MaxConnAge,
+		ConnMaxIdleTime: f.config.IdleTimeout,
 	})
 }
 
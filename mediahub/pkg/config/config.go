@@ -33,10 +33,74 @@ type Config struct {
 		BucketUrl string
 		CDNDomain string
 	}
+	Media struct {
+		BaseUrl            string // mediahub 对外访问地址，用于拼接私有媒体的访问与下载链接
+		SignMode           string // 私有媒体签名方式：cos 使用 COS 预签名地址，hmac 使用 mediahub 下载路由
+		SignSecret         string // 签名密钥，用于私有媒体访问链接与 hmac 下载链接，至少32个字符，未配置时无法启动
+		SignExpire         int    // 签名地址有效期（秒）
+		TrashRetentionDays int    // 回收站保留天数，到期后由定时任务彻底清除
+	}
+	Watermark struct {
+		Enable    bool    // 是否为公共上传添加默认水印，登录用户的上传只使用自己的水印设置
+		Text      string  // 默认文字水印
+		ImagePath string  // 默认图片水印的本地文件路径，设置后优先于文字水印
+		Position  string  // 水印位置：top-left、top-right、bottom-left、bottom-right、center
+		Opacity   float64 // 不透明度，取值 (0, 1]
+		Scale     float64 // 水印宽度占原图宽度的比例，取值 (0, 1]
+		Margin    int     // 水印与图片边缘的距离（像素）
+	}
+	Optimize struct {
+		Enable       bool // 是否在上传时优化图片
+		JpegQuality  int  // jpeg 重新编码质量，默认80
+		MaxDimension int  // 最长边上限（像素），0 表示不限制
+		PngQuantize  bool // 是否将 png 量化为调色板图片
+		PngColors    int  // 调色板颜色数，默认256
+	}
+	Auth struct {
+		Mode           string // 令牌校验方式：remote（默认）调用用户服务校验，jwt 本地校验
+		RemoteFallback bool   // jwt 模式下令牌无法在本地校验（不是 JWT 或找不到密钥）时回退到用户服务校验
+		Jwt            struct {
+			Algorithms    []string // 允许的签名算法，默认 HS256、RS256、ES256
+			Issuer        string   // 期望的签发者，为空时不校验
+			Audience      string   // 期望的受众，为空时不校验
+			ClockSkew     int      // 允许的时钟偏差（秒）
+			HmacSecret    string   // HS256 共享密钥
+			PublicKeyFile string   // RS256/ES256 公钥 PEM 文件
+			JwksUrl       string   // JWKS 地址，设置后按 kid 查找密钥
+			JwksRefresh   int      // JWKS 后台刷新间隔（秒），默认600
+		}
+		Cache struct {
+			TTL         int // 用户服务校验结果的新鲜期（秒），默认300
+			NegativeTTL int // 无效令牌的缓存时长（秒），默认30
+			StaleTTL    int // 有效身份的最长保留时长（秒），用户服务不可用时降级使用，默认3600
+		}
+		Breaker struct {
+			FailureThreshold int // 用户服务连续失败多少次后熔断，默认5
+			OpenSeconds      int // 熔断持续时间（秒），默认30
+		}
+	}
+	Cors struct {
+		Public  CorsPolicy // 公开只读接口（GET、HEAD）的跨域策略，未配置来源时允许任意来源且不携带凭证
+		Private CorsPolicy // 其他接口的跨域策略，未配置来源时只允许同源访问
+	}
+	Session struct {
+		CookieName     string // 会话 Cookie 名称，默认 mediahub_session
+		CsrfCookieName string // CSRF Cookie 名称，默认 mediahub_csrf，前端读取后放入 X-CSRF-Token 请求头
+		TTL            int    // 会话有效期（秒），默认7天
+		Domain         string // Cookie 的域，为空时只对当前主机有效
+		Secure         bool   // 是否只通过 HTTPS 发送 Cookie，生产环境应开启
+	}
 	DependOn struct {
 		ShortUrl struct {
 			Address     string
-			AccessToken string
+			AccessToken string // 使用 mTLS 客户端证书认证时可以为空
+			TLS         struct {
+				Enable     bool
+				CertFile   string `mapstructure:"certFile"`   // 客户端证书，启用 mTLS 时配置
+				KeyFile    string `mapstructure:"keyFile"`    // 客户端私钥
+				CaFile     string `mapstructure:"caFile"`     // 校验服务端证书的 CA，为空时使用系统根证书
+				ServerName string `mapstructure:"serverName"` // 服务端证书中的主机名，为空时使用 Address 中的主机名
+			} `mapstructure:"tls"`
 		}
 		User struct {
 			Address string
@@ -44,6 +108,17 @@ type Config struct {
 	}
 }
 
+// CorsPolicy 跨域策略
+type CorsPolicy struct {
+	AllowOrigins     []string // 允许的来源，如 https://app.example.com，https://*.example.com 匹配任意子域名，* 匹配任意来源（不能与 AllowCredentials 同时使用）
+	OriginPatterns   []string // 来源正则，匹配完整来源（自动加上 ^ 与 $），匹配的来源原样反射，用于预览部署，如 https://pr-[0-9]+\.preview\.example\.com
+	AllowMethods     []string // 允许的方法，为空时使用默认值
+	AllowHeaders     []string // 允许的请求头，为空时使用默认值
+	ExposeHeaders    []string // 允许前端读取的响应头
+	AllowCredentials bool     // 是否允许携带 Cookie 等凭证
+	MaxAge           int      // 预检结果缓存时长（秒），默认600
+}
+
 var conf *Config
 
 // InitConfig 初始化应用程序的配置。
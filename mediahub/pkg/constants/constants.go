@@ -1 +1,20 @@
 package constants
+
+const TABLENAME_MEDIA = "media"
+const TABLENAME_MEDIA_VERSION = "media_version"
+const TABLENAME_WATERMARK = "watermark"
+const TABLENAME_API_KEY = "api_key"
+const TABLENAME_AUDIT_LOG = "audit_log"
+
+// 媒体可见性
+const (
+	VISIBILITY_PUBLIC  = 0 // 公开，返回 CDN 地址
+	VISIBILITY_PRIVATE = 1 // 私有，仅能通过限时签名地址访问
+)
+
+// 角色权限相关表
+const (
+	TABLENAME_RBAC_ROLE            = "rbac_role"
+	TABLENAME_RBAC_ROLE_PERMISSION = "rbac_role_permission"
+	TABLENAME_RBAC_USER_ROLE       = "rbac_user_role"
+)
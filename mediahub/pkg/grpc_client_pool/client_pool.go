@@ -1,6 +1,7 @@
 package grpc_client_pool
 
 import (
+	"enterprise-project1-mediahub/mediahub/pkg/zerror"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 	"log"
@@ -8,7 +9,8 @@ import (
 )
 
 type ClientPool interface {
-	Get() *grpc.ClientConn
+	// Get 取出一个可用连接，无法建立连接时返回错误
+	Get() (*grpc.ClientConn, error)
 	Put(conn *grpc.ClientConn)
 }
 
@@ -21,6 +23,53 @@ type clientCusPool struct {
 	currIndex  int
 }
 
+// clientPool 基于 sync.Pool 复用连接，池中没有可用连接时新建
+type clientPool struct {
+	pool   sync.Pool
+	target string
+	opts   []grpc.DialOption
+}
+
+func NewPool(target string, opts ...grpc.DialOption) (ClientPool, error) {
+	return &clientPool{
+		target: target,
+		opts:   opts,
+	}, nil
+}
+
+// Get 从池中取出连接，取出的连接不可用时关闭后新建
+func (c *clientPool) Get() (*grpc.ClientConn, error) {
+	if conn, ok := c.pool.Get().(*grpc.ClientConn); ok && conn != nil {
+		if usable(conn) {
+			return conn, nil
+		}
+		conn.Close()
+	}
+	conn, err := grpc.Dial(c.target, c.opts...)
+	if err != nil {
+		return nil, zerror.NewByErr(err)
+	}
+	return conn, nil
+}
+
+// Put 归还连接，不可用的连接直接关闭
+func (c *clientPool) Put(conn *grpc.ClientConn) {
+	if conn == nil {
+		return
+	}
+	if !usable(conn) {
+		conn.Close()
+		return
+	}
+	c.pool.Put(conn)
+}
+
+// usable 判断连接是否可以继续使用
+func usable(conn *grpc.ClientConn) bool {
+	state := conn.GetState()
+	return state != connectivity.Shutdown && state != connectivity.TransientFailure
+}
+
 func NewClientCusPool(target string, maxConnNum int, opts ...grpc.DialOption) (ClientPool, error) {
 	if maxConnNum <= 0 {
 		maxConnNum = 1
@@ -35,15 +84,15 @@ func NewClientCusPool(target string, maxConnNum int, opts ...grpc.DialOption) (C
 	}, nil
 }
 
-func (c *clientCusPool) new() *grpc.ClientConn {
+func (c *clientCusPool) new() (*grpc.ClientConn, error) {
 	conn, err := grpc.Dial(c.target, c.opts...)
 	if err != nil {
 		log.Println(err)
-		return nil
+		return nil, err
 	}
-	return conn
+	return conn, nil
 }
-func (c *clientCusPool) Get() *grpc.ClientConn {
+func (c *clientCusPool) Get() (*grpc.ClientConn, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	c.currIndex += 1
@@ -51,18 +100,22 @@ func (c *clientCusPool) Get() *grpc.ClientConn {
 		c.currIndex = 0
 	}
 	conn := c.conns[c.currIndex]
-	if conn == nil || conn.GetState() == connectivity.Shutdown || conn.GetState() == connectivity.TransientFailure {
+	if conn == nil || !usable(conn) {
 		if conn != nil {
 			conn.Close()
 		}
-		conn = c.new()
+		var err error
+		conn, err = c.new()
 		c.conns[c.currIndex] = conn
+		if err != nil {
+			return nil, err
+		}
 	}
-	return conn
+	return conn, nil
 }
 
 func (c *clientCusPool) Put(conn *grpc.ClientConn) {
-	if conn.GetState() == connectivity.Shutdown || conn.GetState() == connectivity.TransientFailure {
+	if conn != nil && !usable(conn) {
 		conn.Close()
 	}
 }
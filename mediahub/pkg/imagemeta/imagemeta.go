@@ -0,0 +1,208 @@
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// jpeg 标记与 EXIF 标签
+const (
+	markerSOS      = 0xda   // jpeg 扫描开始，之后是压缩数据
+	tagOrientation = 0x0112 // EXIF 方向标签
+)
+
+// jpegKeepApp 去除元数据时保留的 jpeg APP 段：APP0 JFIF、APP2 ICC 色彩配置、APP14 Adobe 颜色变换
+var jpegKeepApp = map[byte]bool{0xe0: true, 0xe2: true, 0xee: true}
+
+// pngDropChunks 去除元数据时删除的 png 块：文本、EXIF 与修改时间
+var pngDropChunks = map[string]bool{"tEXt": true, "zTXt": true, "iTXt": true, "eXIf": true, "tIME": true}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Decode 解码图片，jpeg 按 EXIF 方向旋转为正向
+// 重新编码后 EXIF 不再保留，不旋转的话手机照片会显示为横躺或镜像
+func Decode(content []byte) (image.Image, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", err
+	}
+	if format == "jpeg" {
+		img = Orient(img, Orientation(content))
+	}
+	return img, format, nil
+}
+
+// Orientation 读取 jpeg 的 EXIF 方向，取值 1-8，没有或无法解析时返回 1
+func Orientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xff || content[1] != 0xd8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(content); {
+		if content[i] != 0xff {
+			return 1
+		}
+		marker := content[i+1]
+		if marker == 0xff {
+			i++
+			continue
+		}
+		if marker == markerSOS {
+			return 1
+		}
+		n := int(binary.BigEndian.Uint16(content[i+2:]))
+		if n < 2 || i+2+n > len(content) {
+			return 1
+		}
+		seg := content[i+4 : i+2+n]
+		if marker == 0xe1 && bytes.HasPrefix(seg, []byte("Exif\x00\x00")) {
+			return tiffOrientation(seg[6:])
+		}
+		i += 2 + n
+	}
+	return 1
+}
+
+// tiffOrientation 在 EXIF 的 TIFF 结构中查找 IFD0 的方向标签
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for k := 0; k < count; k++ {
+		e := ifd + 2 + k*12
+		if e+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[e:]) != tagOrientation {
+			continue
+		}
+		if v := int(order.Uint16(tiff[e+8:])); v >= 1 && v <= 8 {
+			return v
+		}
+		return 1
+	}
+	return 1
+}
+
+// Orient 按 EXIF 方向旋转或翻转图片，方向为 1 或无效时原样返回
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 水平翻转
+				dx, dy = w-1-x, y
+			case 3: // 旋转 180 度
+				dx, dy = w-1-x, h-1-y
+			case 4: // 垂直翻转
+				dx, dy = x, h-1-y
+			case 5: // 沿主对角线翻转
+				dx, dy = y, x
+			case 6: // 顺时针旋转 90 度
+				dx, dy = h-1-y, x
+			case 7: // 沿副对角线翻转
+				dx, dy = h-1-y, w-1-x
+			case 8: // 逆时针旋转 90 度
+				dx, dy = y, w-1-x
+			}
+			si, di := src.PixOffset(x, y), dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// Strip 无损去除 jpeg 与 png 的元数据，像素数据不变
+// jpeg 删除 EXIF（含 GPS）、XMP、IPTC 与注释，保留色彩相关的 APP 段；png 删除文本、EXIF 与时间块
+// 其他格式或无法解析时原样返回；去除后 EXIF 方向随之丢失，需要旋转的图片应先重新编码
+func Strip(content []byte, format string) []byte {
+	switch format {
+	case "jpeg":
+		return stripJpeg(content)
+	case "png":
+		return stripPng(content)
+	}
+	return content
+}
+
+func stripJpeg(content []byte) []byte {
+	if len(content) < 4 || content[0] != 0xff || content[1] != 0xd8 {
+		return content
+	}
+	out := make([]byte, 0, len(content))
+	out = append(out, 0xff, 0xd8)
+	for i := 2; i+4 <= len(content); {
+		if content[i] != 0xff {
+			return content
+		}
+		marker := content[i+1]
+		if marker == 0xff {
+			i++
+			continue
+		}
+		if marker == markerSOS {
+			return append(out, content[i:]...)
+		}
+		n := int(binary.BigEndian.Uint16(content[i+2:]))
+		if n < 2 || i+2+n > len(content) {
+			return content
+		}
+		isApp := marker >= 0xe0 && marker <= 0xef
+		if (!isApp || jpegKeepApp[marker]) && marker != 0xfe {
+			out = append(out, content[i:i+2+n]...)
+		}
+		i += 2 + n
+	}
+	return content
+}
+
+func stripPng(content []byte) []byte {
+	if !bytes.HasPrefix(content, pngSignature) {
+		return content
+	}
+	out := make([]byte, 0, len(content))
+	out = append(out, pngSignature...)
+	for i := len(pngSignature); i < len(content); {
+		if i+12 > len(content) {
+			return content
+		}
+		n := int(binary.BigEndian.Uint32(content[i:]))
+		end := i + 12 + n
+		if end > len(content) {
+			return content
+		}
+		if !pngDropChunks[string(content[i+4:i+8])] {
+			out = append(out, content[i:end]...)
+		}
+		i = end
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// withExif 在 jpeg 的 SOI 之后插入只包含方向标签的 EXIF 段
+func withExif(content []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(ifd, 1)
+	binary.BigEndian.PutUint16(ifd[2:], tagOrientation)
+	binary.BigEndian.PutUint16(ifd[4:], 3)
+	binary.BigEndian.PutUint32(ifd[6:], 1)
+	binary.BigEndian.PutUint16(ifd[10:], orientation)
+	seg := append([]byte("Exif\x00\x00"), append(tiff, ifd...)...)
+
+	out := []byte{0xff, 0xd8, 0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(seg)+2))
+	out = append(out, seg...)
+	return append(out, content[2:]...)
+}
+
+func encodeJpeg(t *testing.T, img image.Image) []byte {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOrientation(t *testing.T) {
+	plain := encodeJpeg(t, image.NewGray(image.Rect(0, 0, 8, 8)))
+	if o := Orientation(plain); o != 1 {
+		t.Errorf("plain jpeg orientation = %d", o)
+	}
+	if o := Orientation(withExif(plain, 6)); o != 6 {
+		t.Errorf("exif orientation = %d, want 6", o)
+	}
+	if o := Orientation([]byte("not an image")); o != 1 {
+		t.Errorf("invalid content orientation = %d", o)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// 左上角为红色的 4x2 图片
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	red := color.RGBA{R: 255, A: 255}
+	src.Set(0, 0, red)
+	cases := []struct {
+		orientation int
+		w, h        int
+		x, y        int // 红色像素旋转后的位置
+	}{
+		{1, 4, 2, 0, 0},
+		{2, 4, 2, 3, 0},
+		{3, 4, 2, 3, 1},
+		{6, 2, 4, 1, 0},
+		{8, 2, 4, 0, 3},
+	}
+	for _, c := range cases {
+		dst := Orient(src, c.orientation)
+		if dst.Bounds().Dx() != c.w || dst.Bounds().Dy() != c.h {
+			t.Errorf("orientation %d: size %v", c.orientation, dst.Bounds())
+			continue
+		}
+		if dst.At(c.x, c.y) != red {
+			t.Errorf("orientation %d: pixel (%d,%d) = %v", c.orientation, c.x, c.y, dst.At(c.x, c.y))
+		}
+	}
+}
+
+func TestStripJpeg(t *testing.T) {
+	plain := encodeJpeg(t, image.NewGray(image.Rect(0, 0, 8, 8)))
+	stripped := Strip(withExif(plain, 6), "jpeg")
+	if !bytes.Equal(stripped, plain) {
+		t.Fatalf("exif segment not removed: %d -> %d bytes", len(plain), len(stripped))
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,222 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"enterprise-project1-mediahub/mediahub/pkg/log"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultJwksRefresh  = 10 * time.Minute // 默认后台刷新间隔
+	minJwksRefreshDelay = 30 * time.Second // 遇到未知 kid 时两次拉取的最小间隔，防止被伪造 kid 刷爆
+)
+
+// jwk JWKS 中的单个密钥
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// JwksKeys 从 JWKS 地址加载的密钥，缓存在内存中并在后台定时刷新
+type JwksKeys struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]any // kid -> 公钥
+
+	fetchMu     sync.Mutex // 保证同一时间只有一个拉取请求
+	lastAttempt time.Time  // 上一次拉取的时间，无论成功与否，由 fetchMu 保护
+}
+
+// NewJwksKeys 创建 JWKS 密钥源，refresh 为后台刷新间隔
+func NewJwksKeys(url string, refresh time.Duration) *JwksKeys {
+	if refresh <= 0 {
+		refresh = defaultJwksRefresh
+	}
+	return &JwksKeys{
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		keys:    map[string]any{},
+	}
+}
+
+// Start 立即拉取一次并启动后台刷新，ctx 结束时停止
+func (j *JwksKeys) Start(ctx context.Context) {
+	if err := j.Refresh(ctx); err != nil {
+		log.Error(err)
+	}
+	go func() {
+		ticker := time.NewTicker(j.refresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := j.Refresh(ctx); err != nil {
+					// 刷新失败时继续使用上一次的密钥
+					log.Error(err)
+				}
+			}
+		}
+	}()
+}
+
+// Refresh 拉取 JWKS 并替换缓存的密钥
+func (j *JwksKeys) Refresh(ctx context.Context) error {
+	j.fetchMu.Lock()
+	defer j.fetchMu.Unlock()
+	return j.fetch(ctx)
+}
+
+// refreshUnknown 遇到未知 kid 时重新拉取，每个最小间隔内最多拉取一次
+// 并发请求在 fetchMu 上排队，拿到锁后重新检查上一次拉取时间，前一个请求刚拉取过时直接返回；
+// 拉取失败也计入间隔，伪造 kid 的请求不会在请求路径上反复发起拉取
+func (j *JwksKeys) refreshUnknown() {
+	j.fetchMu.Lock()
+	defer j.fetchMu.Unlock()
+	if time.Since(j.lastAttempt) < minJwksRefreshDelay {
+		return
+	}
+	if err := j.fetch(context.Background()); err != nil {
+		log.Error(err)
+	}
+}
+
+// fetch 拉取 JWKS 并替换缓存的密钥，调用方需要持有 fetchMu
+func (j *JwksKeys) fetch(ctx context.Context) error {
+	j.lastAttempt = time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwtauth: fetch jwks status %d", res.StatusCode)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.mu.Unlock()
+	return nil
+}
+
+// Key 按 kid 查找密钥，未知 kid 时在限频范围内重新拉取一次，以便及时获取轮换后的新密钥
+func (j *JwksKeys) Key(kid, alg string) (any, error) {
+	if key, ok := j.lookup(kid, alg); ok {
+		return key, nil
+	}
+	j.refreshUnknown()
+	if key, ok := j.lookup(kid, alg); ok {
+		return key, nil
+	}
+	return nil, ErrNoKey
+}
+
+func (j *JwksKeys) lookup(kid, alg string) (any, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	key, ok := j.keys[kid]
+	if ok && keyMatchesAlg(key, alg) {
+		return key, true
+	}
+	return nil, false
+}
+
+// keyMatchesAlg 检查密钥类型与算法是否匹配，防止用公钥当作 HMAC 密钥等算法混淆攻击
+func keyMatchesAlg(key any, alg string) bool {
+	switch key.(type) {
+	case []byte:
+		return strings.HasPrefix(alg, "HS")
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	}
+	return false
+}
+
+// publicKey 将 jwk 转换为公钥
+func (k *jwk) publicKey() (any, error) {
+	dec := base64.RawURLEncoding
+	switch k.Kty {
+	case "RSA":
+		n, err := dec.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := dec.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwtauth: unsupported curve %s", k.Crv)
+		}
+		x, err := dec.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := dec.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "oct":
+		return dec.DecodeString(k.K)
+	}
+	return nil, fmt.Errorf("jwtauth: unsupported key type %s", k.Kty)
+}
@@ -0,0 +1,107 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"strings"
+)
+
+// StaticKeys 从配置加载的固定密钥，不区分 kid
+type StaticKeys struct {
+	hmacSecret []byte
+	rsaKey     *rsa.PublicKey
+	ecKey      *ecdsa.PublicKey
+}
+
+// NewStaticKeys 创建固定密钥源
+// 参数：
+//   - hmacSecret: HS256 共享密钥，为空时不支持 HS256
+//   - publicKeyFile: PEM 格式的 RSA 或 EC 公钥（或证书）文件，为空时不支持 RS256、ES256
+func NewStaticKeys(hmacSecret, publicKeyFile string) (*StaticKeys, error) {
+	k := &StaticKeys{}
+	if hmacSecret != "" {
+		k.hmacSecret = []byte(hmacSecret)
+	}
+	if publicKeyFile == "" {
+		return k, nil
+	}
+	bs, err := os.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := ParsePublicKeyPEM(bs)
+	if err != nil {
+		return nil, err
+	}
+	switch p := pub.(type) {
+	case *rsa.PublicKey:
+		k.rsaKey = p
+	case *ecdsa.PublicKey:
+		k.ecKey = p
+	}
+	return k, nil
+}
+
+// Key 按算法返回密钥
+func (k *StaticKeys) Key(kid, alg string) (any, error) {
+	switch {
+	case strings.HasPrefix(alg, "HS") && k.hmacSecret != nil:
+		return k.hmacSecret, nil
+	case strings.HasPrefix(alg, "RS") && k.rsaKey != nil:
+		return k.rsaKey, nil
+	case strings.HasPrefix(alg, "ES") && k.ecKey != nil:
+		return k.ecKey, nil
+	}
+	return nil, ErrNoKey
+}
+
+// ParsePublicKeyPEM 解析 PEM 格式的 RSA/EC 公钥或证书
+func ParsePublicKeyPEM(bs []byte) (any, error) {
+	block, _ := pem.Decode(bs)
+	if block == nil {
+		return nil, errors.New("jwtauth: invalid pem")
+	}
+	var pub any
+	var err error
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			pub = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch pub.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return pub, nil
+	}
+	return nil, errors.New("jwtauth: unsupported public key type")
+}
+
+// MultiKeys 依次从多个密钥源查找密钥
+type MultiKeys []KeySource
+
+// Key 返回第一个找到的密钥
+func (m MultiKeys) Key(kid, alg string) (any, error) {
+	for _, ks := range m {
+		key, err := ks.Key(kid, alg)
+		if err == nil {
+			return key, nil
+		}
+		if !errors.Is(err, ErrNoKey) {
+			return nil, err
+		}
+	}
+	return nil, ErrNoKey
+}
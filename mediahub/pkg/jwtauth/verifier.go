@@ -0,0 +1,80 @@
+package jwtauth
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+var (
+	// ErrNoKey 没有找到与令牌匹配的验签密钥，通常说明令牌不是本地可校验的令牌
+	ErrNoKey = errors.New("jwtauth: no matching key")
+	// ErrMalformed 令牌不是合法的 JWT
+	ErrMalformed = errors.New("jwtauth: malformed token")
+	// ErrInvalid 令牌签名或声明校验失败
+	ErrInvalid = errors.New("jwtauth: invalid token")
+)
+
+// KeySource 提供验签密钥
+type KeySource interface {
+	// Key 返回 kid 与 alg 对应的验签密钥，找不到时返回 ErrNoKey
+	Key(kid, alg string) (any, error)
+}
+
+// Options 令牌校验参数
+type Options struct {
+	Algorithms []string      // 允许的签名算法，为空时允许 HS256、RS256、ES256
+	Issuer     string        // 期望的签发者，为空时不校验
+	Audience   string        // 期望的受众，为空时不校验
+	ClockSkew  time.Duration // 允许的时钟偏差
+}
+
+// Verifier 本地校验 JWT
+type Verifier struct {
+	keys   KeySource
+	parser *jwt.Parser
+}
+
+// NewVerifier 创建令牌校验器
+func NewVerifier(opt Options, keys KeySource) *Verifier {
+	algs := opt.Algorithms
+	if len(algs) == 0 {
+		algs = []string{"HS256", "RS256", "ES256"}
+	}
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(algs),
+		jwt.WithLeeway(opt.ClockSkew),
+		jwt.WithExpirationRequired(),
+	}
+	if opt.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opt.Issuer))
+	}
+	if opt.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opt.Audience))
+	}
+	return &Verifier{
+		keys:   keys,
+		parser: jwt.NewParser(parserOpts...),
+	}
+}
+
+// Verify 校验令牌并返回其中的声明
+// 返回的错误可以用 errors.Is 区分 ErrMalformed、ErrNoKey 与 ErrInvalid
+func (v *Verifier) Verify(token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(kid, t.Method.Alg())
+	})
+	if err == nil {
+		return claims, nil
+	}
+	switch {
+	case errors.Is(err, ErrNoKey):
+		return nil, ErrNoKey
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return nil, ErrMalformed
+	default:
+		return nil, errors.Join(ErrInvalid, err)
+	}
+}
@@ -0,0 +1,158 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	tk := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tk.Header["kid"] = kid
+	}
+	s, err := tk.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":  "42",
+		"name": "alice",
+		"iss":  "user-service",
+		"aud":  "mediahub",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	keys, err := NewStaticKeys("secret", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier(Options{Issuer: "user-service", Audience: "mediahub", ClockSkew: 30 * time.Second}, keys)
+
+	claims, err := v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), "", validClaims()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims["name"] != "alice" {
+		t.Errorf("unexpected claims %v", claims)
+	}
+
+	if _, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("other"), "", validClaims())); !errors.Is(err, ErrInvalid) {
+		t.Errorf("wrong secret: expected ErrInvalid, got %v", err)
+	}
+
+	c := validClaims()
+	c["aud"] = "other"
+	if _, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), "", c)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("wrong audience: expected ErrInvalid, got %v", err)
+	}
+
+	// 过期时间在时钟偏差范围内仍然有效
+	c = validClaims()
+	c["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	if _, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), "", c)); err != nil {
+		t.Errorf("within skew: %v", err)
+	}
+	c["exp"] = time.Now().Add(-time.Minute).Unix()
+	if _, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), "", c)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expired: expected ErrInvalid, got %v", err)
+	}
+
+	if _, err = v.Verify("not-a-jwt"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("expected ErrMalformed, got %v", err)
+	}
+}
+
+func TestVerifyJwks(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := base64.RawURLEncoding
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa1", "use": "sig", "n": enc.EncodeToString(rsaKey.N.Bytes()), "e": enc.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec1", "crv": "P-256", "x": enc.EncodeToString(ecKey.X.Bytes()), "y": enc.EncodeToString(ecKey.Y.Bytes())},
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	jwks := NewJwksKeys(srv.URL, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jwks.Start(ctx)
+	v := NewVerifier(Options{Audience: "mediahub"}, jwks)
+
+	if _, err = v.Verify(sign(t, jwt.SigningMethodRS256, rsaKey, "rsa1", validClaims())); err != nil {
+		t.Errorf("RS256: %v", err)
+	}
+	if _, err = v.Verify(sign(t, jwt.SigningMethodES256, ecKey, "ec1", validClaims())); err != nil {
+		t.Errorf("ES256: %v", err)
+	}
+	if _, err = v.Verify(sign(t, jwt.SigningMethodRS256, rsaKey, "unknown", validClaims())); !errors.Is(err, ErrNoKey) {
+		t.Errorf("unknown kid: expected ErrNoKey, got %v", err)
+	}
+	// 用 EC 密钥的 kid 签 RS256 令牌属于算法混淆，不能通过
+	if _, err = v.Verify(sign(t, jwt.SigningMethodRS256, rsaKey, "ec1", validClaims())); err == nil {
+		t.Error("algorithm mismatch accepted")
+	}
+
+	// 缺少过期时间的令牌不接受
+	c := validClaims()
+	delete(c, "exp")
+	if _, err = v.Verify(sign(t, jwt.SigningMethodRS256, rsaKey, "rsa1", c)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("missing exp: expected ErrInvalid, got %v", err)
+	}
+}
+
+func TestJwksUnknownKidFetchesOnce(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"keys": []any{}})
+	}))
+	defer srv.Close()
+
+	jwks := NewJwksKeys(srv.URL, time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := jwks.Key(fmt.Sprintf("forged-%d", i), "RS256"); !errors.Is(err, ErrNoKey) {
+				t.Errorf("expected ErrNoKey, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	// 伪造的 kid 并发到达时，最小间隔内只拉取一次
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected 1 fetch, got %d", n)
+	}
+}
@@ -0,0 +1,176 @@
+package optimize
+
+import (
+	"bytes"
+	"enterprise-project1-mediahub/mediahub/pkg/imagemeta"
+	"golang.org/x/image/draw"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"sort"
+)
+
+// 默认参数
+const (
+	DefaultJpegQuality = 80  // 默认 jpeg 质量
+	DefaultPngColors   = 256 // 默认调色板颜色数
+)
+
+// Options 优化规则
+type Options struct {
+	JpegQuality  int  // jpeg 重新编码质量，取值 1-100
+	MaxDimension int  // 最长边上限（像素），0 表示不限制
+	PngQuantize  bool // 是否将 png 量化为调色板图片
+	PngColors    int  // 调色板颜色数，取值 2-256
+}
+
+// Result 优化结果
+type Result struct {
+	Content      []byte // 优化后的内容，未优化时为原内容
+	Format       string // 图片格式
+	OriginalSize int    // 原始大小（字节）
+	Size         int    // 优化后大小（字节）
+	Optimized    bool   // 是否采用了优化后的内容
+}
+
+// SavedBytes 节省的字节数
+func (r *Result) SavedBytes() int {
+	return r.OriginalSize - r.Size
+}
+
+// Optimize 按规则重新编码图片
+// 重新编码时只写入像素数据，jpeg 先按 EXIF 方向旋转；结果不比原图小时保留无损去除元数据后的原图，
+// 但需要旋转的 jpeg 去除元数据后方向会丢失，总是使用重新编码的结果
+// 动图与 webp（标准库没有编码器）不做处理
+func Optimize(content []byte, opt Options) (*Result, error) {
+	rs := &Result{Content: content, OriginalSize: len(content), Size: len(content)}
+	img, format, err := imagemeta.Decode(content)
+	if err != nil {
+		return nil, err
+	}
+	rs.Format = format
+	if format != "jpeg" && format != "png" {
+		return rs, nil
+	}
+	rs.Content = imagemeta.Strip(content, format)
+	rs.Size = len(rs.Content)
+	mustEncode := format == "jpeg" && imagemeta.Orientation(content) != 1
+
+	img = clamp(img, opt.MaxDimension)
+
+	var candidates [][]byte
+	switch format {
+	case "jpeg":
+		quality := opt.JpegQuality
+		if quality <= 0 || quality > 100 {
+			quality = DefaultJpegQuality
+		}
+		buf := &bytes.Buffer{}
+		if err = jpeg.Encode(buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, buf.Bytes())
+	case "png":
+		enc := &png.Encoder{CompressionLevel: png.BestCompression}
+		buf := &bytes.Buffer{}
+		if err = enc.Encode(buf, img); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, buf.Bytes())
+		if opt.PngQuantize {
+			buf = &bytes.Buffer{}
+			if err = enc.Encode(buf, Quantize(img, opt.PngColors)); err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, buf.Bytes())
+		}
+	}
+
+	for i, c := range candidates {
+		if (mustEncode && i == 0) || len(c) < rs.Size {
+			rs.Content = c
+			rs.Size = len(c)
+			rs.Optimized = true
+		}
+	}
+	return rs, nil
+}
+
+// clamp 将图片等比缩小到最长边不超过 max
+func clamp(img image.Image, max int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if max <= 0 || (w <= max && h <= max) {
+		return img
+	}
+	if w >= h {
+		w, h = max, h*max/w
+	} else {
+		w, h = w*max/h, max
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// bucket 量化时一个颜色桶的统计
+type bucket struct {
+	count          int
+	r, g, b, alpha int
+}
+
+// Quantize 将图片量化为最多 colors 种颜色的调色板图片
+// 采用流行色算法：按 RGB 各 5 位、透明度 3 位分桶，取像素最多的桶的平均色作为调色板，
+// 再用 Floyd-Steinberg 抖动映射；截图等颜色较少的图片基本无损
+func Quantize(img image.Image, colors int) *image.Paletted {
+	if colors < 2 || colors > 256 {
+		colors = DefaultPngColors
+	}
+	b := img.Bounds()
+	buckets := make(map[uint32]*bucket)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			key := uint32(c.R>>3)<<13 | uint32(c.G>>3)<<8 | uint32(c.B>>3)<<3 | uint32(c.A>>5)
+			bk, ok := buckets[key]
+			if !ok {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.count++
+			bk.r += int(c.R)
+			bk.g += int(c.G)
+			bk.b += int(c.B)
+			bk.alpha += int(c.A)
+		}
+	}
+
+	list := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		list = append(list, bk)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].count > list[j].count })
+	if len(list) > colors {
+		list = list[:colors]
+	}
+	p := make(color.Palette, 0, len(list))
+	for _, bk := range list {
+		p = append(p, color.NRGBA{
+			R: uint8(bk.r / bk.count),
+			G: uint8(bk.g / bk.count),
+			B: uint8(bk.b / bk.count),
+			A: uint8(bk.alpha / bk.count),
+		})
+	}
+
+	dst := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), p)
+	draw.FloydSteinberg.Draw(dst, dst.Bounds(), img, b.Min)
+	return dst
+}
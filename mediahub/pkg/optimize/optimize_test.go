@@ -0,0 +1,112 @@
+package optimize
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// screenshot 生成只有少量颜色的大图，模拟截图
+func screenshot(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	colors := []color.RGBA{{255, 255, 255, 255}, {30, 30, 30, 255}, {0, 120, 215, 255}}
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, colors[(x/40+y/25)%len(colors)])
+		}
+	}
+	return img
+}
+
+func TestOptimizePngQuantize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := &png.Encoder{CompressionLevel: png.NoCompression}
+	if err := enc.Encode(buf, screenshot(800, 600)); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := Optimize(buf.Bytes(), Options{PngQuantize: true, MaxDimension: 400})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rs.Optimized || rs.Size >= rs.OriginalSize || rs.SavedBytes() <= 0 {
+		t.Fatalf("expected smaller output, got %d -> %d", rs.OriginalSize, rs.Size)
+	}
+	img, format, err := image.Decode(bytes.NewReader(rs.Content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || img.Bounds().Dx() != 400 || img.Bounds().Dy() != 300 {
+		t.Errorf("unexpected output %s %v", format, img.Bounds())
+	}
+}
+
+func TestOptimizeKeepsSmallerOriginal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, screenshot(64, 64), &jpeg.Options{Quality: 10}); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := Optimize(buf.Bytes(), Options{JpegQuality: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.Optimized || !bytes.Equal(rs.Content, buf.Bytes()) {
+		t.Errorf("expected original to be kept, got %d -> %d", rs.OriginalSize, rs.Size)
+	}
+}
+
+// withOrientation 在 jpeg 的 SOI 之后插入只包含方向标签的 EXIF 段
+func withOrientation(content []byte, orientation byte) []byte {
+	seg := []byte("\xff\xe1\x00\x22Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08" +
+		"\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00")
+	seg[len(seg)-7] = orientation
+	return append(append([]byte{0xff, 0xd8}, seg...), content[2:]...)
+}
+
+func TestOptimizeRotatesJpeg(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, screenshot(64, 32), &jpeg.Options{Quality: 10}); err != nil {
+		t.Fatal(err)
+	}
+	// 重新编码比原图大，但需要旋转时仍然使用重新编码的结果
+	rs, err := Optimize(withOrientation(buf.Bytes(), 6), Options{JpegQuality: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(rs.Content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rs.Optimized || img.Bounds().Dx() != 32 || img.Bounds().Dy() != 64 {
+		t.Errorf("expected rotated output, got %v", img.Bounds())
+	}
+}
+
+func TestOptimizeStripsKeptOriginal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, screenshot(64, 64), &jpeg.Options{Quality: 10}); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := Optimize(withOrientation(buf.Bytes(), 1), Options{JpegQuality: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.Optimized || !bytes.Equal(rs.Content, buf.Bytes()) {
+		t.Errorf("expected original without exif, got %d -> %d", rs.OriginalSize, rs.Size)
+	}
+}
+
+func TestQuantizeExactColors(t *testing.T) {
+	src := screenshot(100, 100)
+	dst := Quantize(src, 256)
+	if len(dst.Palette) != 3 {
+		t.Fatalf("expected 3 colors, got %d", len(dst.Palette))
+	}
+	r1, g1, b1, _ := src.At(50, 50).RGBA()
+	r2, g2, b2, _ := dst.At(50, 50).RGBA()
+	if r1 != r2 || g1 != g2 || b1 != b2 {
+		t.Error("quantize changed exact color")
+	}
+}
@@ -0,0 +1,61 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+)
+
+type countingStore struct {
+	perms map[int64][]string
+	calls int
+}
+
+func (s *countingStore) UserPermissions(userID int64) ([]string, error) {
+	s.calls++
+	return s.perms[userID], nil
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		granted []string
+		perm    string
+		want    bool
+	}{
+		{[]string{"*"}, PermRbacManage, true},
+		{[]string{"stats:view"}, PermStatsView, true},
+		{[]string{"stats:*"}, PermStatsView, true},
+		{[]string{"stats:view"}, PermRbacManage, false},
+		{[]string{"shorturl:*"}, PermStatsView, false},
+		{nil, PermStatsView, false},
+	}
+	for _, c := range cases {
+		if got := Match(c.granted, c.perm); got != c.want {
+			t.Errorf("Match(%v, %s) = %v, want %v", c.granted, c.perm, got, c.want)
+		}
+	}
+}
+
+func TestEnforcerCache(t *testing.T) {
+	store := &countingStore{perms: map[int64][]string{1: {PermStatsView}}}
+	e := NewEnforcer(store, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if ok, err := e.Allow(1, PermStatsView); err != nil || !ok {
+			t.Fatalf("allow: %v %v", ok, err)
+		}
+	}
+	if store.calls != 1 {
+		t.Errorf("expected 1 store call, got %d", store.calls)
+	}
+
+	// 授权变更后删除缓存立即生效
+	store.perms[1] = nil
+	e.Invalidate(1)
+	if ok, _ := e.Allow(1, PermStatsView); ok {
+		t.Error("revoked permission still allowed")
+	}
+
+	if ok, _ := e.Allow(0, PermStatsView); ok {
+		t.Error("anonymous user allowed")
+	}
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"enterprise-project1-mediahub/mediahub/pkg/config"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// Cookie 的默认名称
+const (
+	defaultCookieName     = "mediahub_session"
+	defaultCsrfCookieName = "mediahub_csrf"
+)
+
+// Cookies 会话与 CSRF Cookie 的配置
+type Cookies struct {
+	Name     string        // 会话 Cookie 名称
+	CsrfName string        // CSRF Cookie 名称
+	Domain   string        // Cookie 的域
+	Secure   bool          // 是否只通过 HTTPS 发送
+	TTL      time.Duration // Cookie 有效期，与会话有效期一致
+}
+
+// NewCookies 按配置创建 Cookie 配置
+func NewCookies(cnf *config.Config) *Cookies {
+	sc := &Cookies{
+		Name:     cnf.Session.CookieName,
+		CsrfName: cnf.Session.CsrfCookieName,
+		Domain:   cnf.Session.Domain,
+		Secure:   cnf.Session.Secure,
+		TTL:      time.Duration(cnf.Session.TTL) * time.Second,
+	}
+	if sc.Name == "" {
+		sc.Name = defaultCookieName
+	}
+	if sc.CsrfName == "" {
+		sc.CsrfName = defaultCsrfCookieName
+	}
+	if sc.TTL <= 0 {
+		sc.TTL = DefaultTTL
+	}
+	return sc
+}
+
+// Write 写入会话 Cookie 与 CSRF Cookie
+// 会话 Cookie 为 HttpOnly，前端脚本无法读取；CSRF Cookie 需要由前端读取后放入请求头
+func (sc *Cookies) Write(c *gin.Context, token, csrf string) {
+	maxAge := int(sc.TTL / time.Second)
+	sc.set(c, sc.Name, token, maxAge, true)
+	sc.set(c, sc.CsrfName, csrf, maxAge, false)
+}
+
+// Clear 清除会话 Cookie 与 CSRF Cookie
+func (sc *Cookies) Clear(c *gin.Context) {
+	sc.set(c, sc.Name, "", -1, true)
+	sc.set(c, sc.CsrfName, "", -1, false)
+}
+
+func (sc *Cookies) set(c *gin.Context, name, value string, maxAge int, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   sc.Domain,
+		MaxAge:   maxAge,
+		Secure:   sc.Secure,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,242 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/db/redis"
+	"errors"
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// sessionKey 当前会话在 gin 上下文中的键
+const sessionKey = "Auth.Session"
+
+// DefaultTTL 会话的默认有效期
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Session 浏览器会话，由令牌换取，凭 HttpOnly Cookie 访问
+type Session struct {
+	ID        string          `json:"id"`         // 会话ID，为 Cookie 中会话令牌的哈希
+	Principal *auth.Principal `json:"principal"`  // 换取会话时的身份
+	CsrfToken string          `json:"-"`          // CSRF 令牌，状态变更请求需要在请求头中回传
+	UserAgent string          `json:"user_agent"` // 创建会话的客户端
+	IP        string          `json:"ip"`         // 创建会话的IP
+	CreateAt  int64           `json:"create_at"`  // 创建时间戳
+	ExpireAt  int64           `json:"expire_at"`  // 过期时间戳
+}
+
+// storedSession 写入存储的会话，CSRF 令牌不随接口输出但需要持久化
+type storedSession struct {
+	Session
+	CsrfToken string `json:"csrf_token"`
+}
+
+// Store 定义会话存储的接口规范
+type Store interface {
+	// Create 为身份创建会话，返回会话与写入 Cookie 的会话令牌
+	Create(p *auth.Principal, userAgent, ip string) (*Session, string, error)
+
+	// Get 按会话令牌查询会话，不存在或已过期返回nil
+	Get(token string) (*Session, error)
+
+	// List 查询用户的全部有效会话
+	List(userID int64) ([]*Session, error)
+
+	// Delete 删除用户的会话，返回会话是否存在
+	Delete(userID int64, id string) (bool, error)
+
+	// DeleteUser 删除用户的全部会话，返回删除的数量
+	DeleteUser(userID int64) (int, error)
+}
+
+// NewToken 生成随机令牌，用作会话令牌与 CSRF 令牌
+func NewToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// ID 计算会话令牌对应的会话ID，存储中只保存哈希，避免令牌明文泄露
+func ID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Set 将当前会话写入上下文，由认证中间件调用
+func Set(ctx *gin.Context, s *Session) {
+	ctx.Set(sessionKey, s)
+}
+
+// Get 读取上下文中的当前会话，不是通过会话认证的请求返回nil
+func Get(ctx *gin.Context) *Session {
+	v, ok := ctx.Get(sessionKey)
+	if !ok {
+		return nil
+	}
+	s, _ := v.(*Session)
+	return s
+}
+
+// redisStore 基于 Redis 的会话存储
+// 会话以 ID 为键保存，另用集合记录用户的全部会话ID，用于列出与批量撤销
+type redisStore struct {
+	pool redis.RedisPool
+	ttl  time.Duration
+}
+
+// NewRedisStore 创建 Redis 会话存储，ttl 不大于0时使用默认值
+func NewRedisStore(pool redis.RedisPool, ttl time.Duration) Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &redisStore{pool: pool, ttl: ttl}
+}
+
+func sessionRedisKey(id string) string {
+	return redis.GetKey("session", id)
+}
+
+func userRedisKey(userID int64) string {
+	return redis.GetKey("session", "user", strconv.FormatInt(userID, 10))
+}
+
+// Create 创建会话并加入用户的会话集合
+func (s *redisStore) Create(p *auth.Principal, userAgent, ip string) (*Session, string, error) {
+	token, err := NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+	csrf, err := NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	sess := &Session{
+		ID:        ID(token),
+		Principal: p,
+		CsrfToken: csrf,
+		UserAgent: userAgent,
+		IP:        ip,
+		CreateAt:  now.Unix(),
+		ExpireAt:  now.Add(s.ttl).Unix(),
+	}
+	bs, err := json.Marshal(&storedSession{Session: *sess, CsrfToken: csrf})
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := s.pool.Get()
+	defer s.pool.Put(client)
+	ctx := context.Background()
+	userKey := userRedisKey(p.UserID)
+	pipe := client.TxPipeline()
+	pipe.Set(ctx, sessionRedisKey(sess.ID), bs, s.ttl)
+	pipe.SAdd(ctx, userKey, sess.ID)
+	pipe.Expire(ctx, userKey, s.ttl)
+	if _, err = pipe.Exec(ctx); err != nil {
+		return nil, "", err
+	}
+	return sess, token, nil
+}
+
+// Get 按会话令牌查询会话
+func (s *redisStore) Get(token string) (*Session, error) {
+	client := s.pool.Get()
+	defer s.pool.Put(client)
+	return s.load(context.Background(), client, ID(token))
+}
+
+// load 按会话ID读取会话，不存在返回nil
+func (s *redisStore) load(ctx context.Context, client *goredis.Client, id string) (*Session, error) {
+	val, err := client.Get(ctx, sessionRedisKey(id)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stored := &storedSession{}
+	if err = json.Unmarshal(val, stored); err != nil {
+		return nil, err
+	}
+	sess := stored.Session
+	sess.CsrfToken = stored.CsrfToken
+	if time.Now().Unix() >= sess.ExpireAt {
+		return nil, nil
+	}
+	return &sess, nil
+}
+
+// List 查询用户的全部有效会话，顺带清理集合中已过期的会话ID
+func (s *redisStore) List(userID int64) ([]*Session, error) {
+	client := s.pool.Get()
+	defer s.pool.Put(client)
+	ctx := context.Background()
+	userKey := userRedisKey(userID)
+	ids, err := client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*Session, 0, len(ids))
+	for _, id := range ids {
+		sess, err := s.load(ctx, client, id)
+		if err != nil {
+			return nil, err
+		}
+		if sess == nil {
+			client.SRem(ctx, userKey, id)
+			continue
+		}
+		results = append(results, sess)
+	}
+	return results, nil
+}
+
+// Delete 删除用户的会话，只能删除属于该用户的会话
+func (s *redisStore) Delete(userID int64, id string) (bool, error) {
+	client := s.pool.Get()
+	defer s.pool.Put(client)
+	ctx := context.Background()
+	n, err := client.SRem(ctx, userRedisKey(userID), id).Result()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if err = client.Del(ctx, sessionRedisKey(id)).Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteUser 删除用户的全部会话
+func (s *redisStore) DeleteUser(userID int64) (int, error) {
+	client := s.pool.Get()
+	defer s.pool.Put(client)
+	ctx := context.Background()
+	userKey := userRedisKey(userID)
+	ids, err := client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionRedisKey(id))
+	}
+	keys = append(keys, userKey)
+	if err = client.Del(ctx, keys...).Err(); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -10,6 +10,7 @@ import (
 	url1 "net/url"
 	"path"
 	"strings"
+	"time"
 )
 
 type cosStorageFactory struct {
@@ -58,12 +59,13 @@ func newCos(bucketUrl, secretId, secretKey, cdnDomain string) storage.Storage {
 //		return "application/octet-stream"
 //	}
 
-func (s *cosStorage) Upload(r io.Reader, md5Digest []byte, dstPath string) (url string, err error) {
+// client 创建访问存储桶的 COS 客户端
+func (s *cosStorage) client() *cos.Client {
 	// 存储桶名称，由 bucketname-appid 组成，appid 必须填入，可以在 COS 控制台查看存储桶名称。 https://console.cloud.tencent.com/cos5/bucket
 	// 替换为用户的 region，存储桶 region 可以在 COS 控制台“存储桶概览”查看 https://console.cloud.tencent.com/ ，关于地域的详情见 https://cloud.tencent.com/document/product/436/6224 。
 	u, _ := url1.Parse(s.bucketUrl)
 	b := &cos.BaseURL{BucketURL: u}
-	client := cos.NewClient(b, &http.Client{
+	return cos.NewClient(b, &http.Client{
 		Transport: &cos.AuthorizationTransport{
 			// 通过环境变量获取密钥
 			// 环境变量 SECRETID 表示用户的 SecretId，登录访问管理控制台查看密钥，https://console.cloud.tencent.com/cam/capi
@@ -72,19 +74,29 @@ func (s *cosStorage) Upload(r io.Reader, md5Digest []byte, dstPath string) (url
 			SecretKey: s.secretKey, // 用户的 SecretKey，建议使用子账号密钥，授权遵循最小权限指引，降低使用风险。子账号密钥获取可参见 https://cloud.tencent.com/document/product/598/37140
 		},
 	})
+}
 
+// put 上传对象，acl 为空时沿用存储桶的默认权限
+func (s *cosStorage) put(r io.Reader, md5Digest []byte, dstPath string, acl string) error {
 	opt := &cos.ObjectPutOptions{
 		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
 			ContentType: s.getContentType(dstPath),
 		},
-		ACLHeaderOptions: &cos.ACLHeaderOptions{},
+		ACLHeaderOptions: &cos.ACLHeaderOptions{
+			XCosACL: acl,
+		},
 	}
 
 	if len(md5Digest) != 0 {
 		opt.ObjectPutHeaderOptions.ContentMD5 = base64.StdEncoding.EncodeToString(md5Digest)
 	}
 
-	_, err = client.Object.Put(context.Background(), dstPath, r, opt)
+	_, err := s.client().Object.Put(context.Background(), dstPath, r, opt)
+	return err
+}
+
+func (s *cosStorage) Upload(r io.Reader, md5Digest []byte, dstPath string) (url string, err error) {
+	err = s.put(r, md5Digest, dstPath, "")
 	if err != nil {
 		return "", err
 	}
@@ -95,6 +107,43 @@ func (s *cosStorage) Upload(r io.Reader, md5Digest []byte, dstPath string) (url
 	return url, err
 }
 
+// UploadPrivate 以 private ACL 上传对象，对象只能通过预签名地址或服务端读取
+func (s *cosStorage) UploadPrivate(r io.Reader, md5Digest []byte, dstPath string) error {
+	return s.put(r, md5Digest, dstPath, "private")
+}
+
+// Download 读取对象内容
+func (s *cosStorage) Download(dstPath string) (io.ReadCloser, error) {
+	resp, err := s.client().Object.Get(context.Background(), dstPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// SignURL 生成 COS 预签名的 GET 地址，有效期为 expire
+// 预签名地址必须使用存储桶源站域名，CDN 域名无法校验 COS 签名
+func (s *cosStorage) SignURL(dstPath string, expire time.Duration) (url string, err error) {
+	u, err := s.client().Object.GetPresignedURL(context.Background(), http.MethodGet, dstPath, s.secretId, s.secretKey, expire, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// SetPrivate 修改对象 ACL，私有时只能通过预签名地址访问，否则沿用存储桶的默认权限
+// CDN 节点已缓存的内容要等缓存过期后才会失效
+func (s *cosStorage) SetPrivate(dstPath string, private bool) error {
+	acl := "default"
+	if private {
+		acl = "private"
+	}
+	_, err := s.client().Object.PutACL(context.Background(), dstPath, &cos.ObjectPutACLOptions{
+		Header: &cos.ACLHeaderOptions{XCosACL: acl},
+	})
+	return err
+}
+
 func (s *cosStorage) getContentType(dstPath string) string {
 	ext := strings.Trim(path.Ext(dstPath), ".")
 	if ext == "jpg" {
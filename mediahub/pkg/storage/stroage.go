@@ -1,9 +1,20 @@
 package storage
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 type Storage interface {
 	Upload(r io.Reader, md5Digest []byte, dstPath string) (url string, err error)
+	// UploadPrivate 以私有读权限上传对象，私有对象不返回可直接访问的地址
+	UploadPrivate(r io.Reader, md5Digest []byte, dstPath string) error
+	// Download 读取对象内容，调用方负责关闭返回的 io.ReadCloser
+	Download(dstPath string) (io.ReadCloser, error)
+	// SignURL 生成对象的限时预签名访问地址
+	SignURL(dstPath string, expire time.Duration) (url string, err error)
+	// SetPrivate 修改对象的读权限，private 为 false 时恢复为存储桶的默认权限
+	SetPrivate(dstPath string, private bool) error
 }
 
 type StorageFactory interface {
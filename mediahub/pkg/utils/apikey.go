@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ApiKeyPrefix API Key 的固定前缀，便于在日志和代码仓库扫描中识别泄露的密钥
+const ApiKeyPrefix = "mh_"
+
+// apiKeyDisplayLen 展示给用户用于辨认密钥的前缀长度（含固定前缀）
+const apiKeyDisplayLen = 11
+
+// GenerateApiKey 生成一个新的 API Key
+// 返回值:
+//
+//	key: 完整密钥，只在创建时返回给用户一次
+//	display: 密钥开头的若干字符，用于列表中辨认密钥
+//	err: 随机数生成失败时的错误
+func GenerateApiKey() (key, display string, err error) {
+	bs := make([]byte, 32)
+	if _, err = rand.Read(bs); err != nil {
+		return "", "", err
+	}
+	key = ApiKeyPrefix + hex.EncodeToString(bs)
+	return key, key[:apiKeyDisplayLen], nil
+}
+
+// HashApiKey 计算 API Key 的哈希，数据库中只保存哈希
+// 密钥本身是高熵随机数，使用 SHA-256 即可，无需慢哈希
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
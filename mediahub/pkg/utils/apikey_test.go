@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateApiKey(t *testing.T) {
+	key, display, err := GenerateApiKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, ApiKeyPrefix) || !strings.HasPrefix(key, display) || len(key) != len(ApiKeyPrefix)+64 {
+		t.Errorf("unexpected key %s display %s", key, display)
+	}
+	other, _, _ := GenerateApiKey()
+	if key == other || HashApiKey(key) == HashApiKey(other) || HashApiKey(key) != HashApiKey(key) {
+		t.Error("keys or hashes not unique")
+	}
+}
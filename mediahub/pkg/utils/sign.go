@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// MinSignSecretLen 签名密钥的最小长度（字节），密钥为空或过短时签名可被猜测或伪造
+const MinSignSecretLen = 32
+
+// HmacSign 使用 HMAC-SHA256 对参数签名，参数之间以 ":" 连接
+// 参数:
+//
+//	secret: 签名密钥
+//	parts: 参与签名的字段，顺序敏感
+//
+// 返回值:
+//
+//	十六进制编码的签名
+func HmacSign(secret string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join(parts, ":")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HmacVerify 校验签名，使用常量时间比较防止时序攻击
+// 参数:
+//
+//	secret: 签名密钥
+//	sig: 待校验的签名
+//	parts: 参与签名的字段，需与签名时一致
+//
+// 返回值:
+//
+//	签名是否有效
+func HmacVerify(secret, sig string, parts ...string) bool {
+	expected := HmacSign(secret, parts...)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
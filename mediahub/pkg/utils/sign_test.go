@@ -0,0 +1,16 @@
+package utils
+
+import "testing"
+
+func TestHmacSign(t *testing.T) {
+	sig := HmacSign("secret", "12", "0", "1700000000")
+	if !HmacVerify("secret", sig, "12", "0", "1700000000") {
+		t.Error("HmacVerify should accept its own signature")
+	}
+	if HmacVerify("secret", sig, "12", "1", "1700000000") {
+		t.Error("HmacVerify should reject a signature for another version")
+	}
+	if HmacVerify("other", sig, "12", "0", "1700000000") {
+		t.Error("HmacVerify should reject a signature made with another secret")
+	}
+}
@@ -0,0 +1,230 @@
+package watermark
+
+import (
+	"bytes"
+	"enterprise-project1-mediahub/mediahub/pkg/imagemeta"
+	"errors"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sync"
+)
+
+// 水印位置
+const (
+	PositionTopLeft     = "top-left"
+	PositionTopRight    = "top-right"
+	PositionBottomLeft  = "bottom-left"
+	PositionBottomRight = "bottom-right"
+	PositionCenter      = "center"
+)
+
+// 默认参数
+const (
+	DefaultOpacity = 0.5  // 默认不透明度
+	DefaultScale   = 0.2  // 默认水印宽度占原图宽度的比例
+	measureSize    = 48.0 // 计算文字宽度时使用的字号
+	jpegQuality    = 90   // 重新编码 jpeg 时的质量
+)
+
+// ErrUnsupportedFormat 不支持添加水印的图片格式
+// 动图与 webp（标准库没有编码器）保持原样上传
+var ErrUnsupportedFormat = errors.New("watermark: unsupported image format")
+
+// Options 水印参数
+type Options struct {
+	Text     string      // 文字水印，与 Image 同时设置时优先使用 Image
+	Image    image.Image // 图片水印
+	Position string      // 水印位置，见 Position* 常量
+	Opacity  float64     // 不透明度，取值 (0, 1]
+	Scale    float64     // 水印宽度占原图宽度的比例，取值 (0, 1]
+	Margin   int         // 水印与图片边缘的距离（像素）
+}
+
+// IsEmpty 判断是否没有可绘制的水印内容
+func (o *Options) IsEmpty() bool {
+	return o.Text == "" && o.Image == nil
+}
+
+var (
+	fontOnce sync.Once
+	goFont   *opentype.Font
+	fontErr  error
+)
+
+// loadFont 解析内置的 Go Bold 字体，只解析一次
+func loadFont() (*opentype.Font, error) {
+	fontOnce.Do(func() {
+		goFont, fontErr = opentype.Parse(gobold.TTF)
+	})
+	return goFont, fontErr
+}
+
+// Apply 在 src 上绘制水印并返回新图片，src 不会被修改
+func Apply(src image.Image, opt Options) (image.Image, error) {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	if opt.IsEmpty() {
+		return dst, nil
+	}
+
+	scale := opt.Scale
+	if scale <= 0 || scale > 1 {
+		scale = DefaultScale
+	}
+	opacity := opt.Opacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = DefaultOpacity
+	}
+
+	width := int(float64(dst.Bounds().Dx()) * scale)
+	if width < 1 {
+		return dst, nil
+	}
+	var mark image.Image
+	var err error
+	if opt.Image != nil {
+		mark = scaleImage(opt.Image, width)
+	} else {
+		mark, err = renderText(opt.Text, width)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pt := position(dst.Bounds(), mark.Bounds(), opt.Position, opt.Margin)
+	r := mark.Bounds().Sub(mark.Bounds().Min).Add(pt)
+	mask := image.NewUniform(color.Alpha{A: uint8(opacity * 255)})
+	draw.DrawMask(dst, r, mark, mark.Bounds().Min, mask, image.Point{}, draw.Over)
+	return dst, nil
+}
+
+// scaleImage 将图片等比缩放到指定宽度
+func scaleImage(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	return dst
+}
+
+// renderText 将文字渲染为宽度约为 width 的透明底图片
+// 白字加黑色描边，保证在深浅背景上都清晰可见
+func renderText(text string, width int) (image.Image, error) {
+	f, err := loadFont()
+	if err != nil {
+		return nil, err
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: measureSize, DPI: 72})
+	if err != nil {
+		return nil, err
+	}
+	measured := font.MeasureString(face, text).Ceil()
+	face.Close()
+	if measured == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1)), nil
+	}
+
+	size := measureSize * float64(width) / float64(measured)
+	face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	metrics := face.Metrics()
+	stroke := int(size/24) + 1
+	w := font.MeasureString(face, text).Ceil() + stroke*2
+	h := (metrics.Ascent + metrics.Descent).Ceil() + stroke*2
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	d := &font.Drawer{Dst: img, Face: face}
+	baseline := stroke + metrics.Ascent.Ceil()
+	d.Src = image.NewUniform(color.Black)
+	for dx := -stroke; dx <= stroke; dx++ {
+		for dy := -stroke; dy <= stroke; dy++ {
+			d.Dot = fixed.P(stroke+dx, baseline+dy)
+			d.DrawString(text)
+		}
+	}
+	d.Src = image.NewUniform(color.White)
+	d.Dot = fixed.P(stroke, baseline)
+	d.DrawString(text)
+	return img, nil
+}
+
+// position 计算水印左上角在图片中的坐标
+func position(dst, mark image.Rectangle, pos string, margin int) image.Point {
+	left, top := margin, margin
+	right := dst.Dx() - mark.Dx() - margin
+	bottom := dst.Dy() - mark.Dy() - margin
+	switch pos {
+	case PositionTopLeft:
+		return image.Pt(left, top)
+	case PositionTopRight:
+		return image.Pt(right, top)
+	case PositionBottomLeft:
+		return image.Pt(left, bottom)
+	case PositionCenter:
+		return image.Pt((dst.Dx()-mark.Dx())/2, (dst.Dy()-mark.Dy())/2)
+	default:
+		return image.Pt(right, bottom)
+	}
+}
+
+// ApplyBytes 解码图片、绘制水印并按原格式重新编码，jpeg 先按 EXIF 方向旋转为正向
+// 仅支持 jpeg、png 以及单帧 gif，其他格式返回 ErrUnsupportedFormat
+func ApplyBytes(content []byte, opt Options) ([]byte, error) {
+	src, format, err := imagemeta.Decode(content)
+	if err != nil {
+		return nil, err
+	}
+	if format == "gif" {
+		g, err := gif.DecodeAll(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		if len(g.Image) > 1 {
+			return nil, ErrUnsupportedFormat
+		}
+	}
+	if format != "jpeg" && format != "png" && format != "gif" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	img, err := Apply(src, opt)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err = Encode(buf, img, format); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode 按指定格式编码图片
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		return png.Encode(w, img)
+	case "gif":
+		return gif.Encode(w, img, nil)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,81 @@
+package watermark
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func newImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestApplyText(t *testing.T) {
+	src := newImage(400, 300, color.RGBA{R: 0, G: 128, B: 255, A: 255})
+	dst, err := Apply(src, Options{Text: "MediaHub", Position: PositionBottomRight, Opacity: 1, Scale: 0.5, Margin: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 水印只出现在右下角，左上角保持原样
+	if dst.At(5, 5) != src.At(5, 5) {
+		t.Errorf("top-left pixel changed: %v", dst.At(5, 5))
+	}
+	changed := false
+	for x := 200; x < 390 && !changed; x++ {
+		for y := 150; y < 290; y++ {
+			if dst.At(x, y) != src.At(x, y) {
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		t.Error("watermark not drawn in bottom-right area")
+	}
+	// 原图不被修改
+	if src.At(300, 250) != (color.RGBA{R: 0, G: 128, B: 255, A: 255}) {
+		t.Error("source image modified")
+	}
+}
+
+func TestApplyImage(t *testing.T) {
+	src := newImage(200, 200, color.White)
+	mark := newImage(50, 50, color.Black)
+	dst, err := Apply(src, Options{Image: mark, Position: PositionTopLeft, Opacity: 0.5, Scale: 0.25})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _, _, _ := dst.At(10, 10).RGBA()
+	if r == 0xffff || r == 0 {
+		t.Errorf("expected half transparent watermark, got r=%d", r)
+	}
+	if dst.At(100, 100) != src.At(100, 100) {
+		t.Error("pixel outside watermark changed")
+	}
+}
+
+func TestApplyBytes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, newImage(100, 80, color.White)); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ApplyBytes(buf.Bytes(), Options{Text: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, format, err := image.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || img.Bounds().Dx() != 100 || img.Bounds().Dy() != 80 {
+		t.Errorf("unexpected output %s %v", format, img.Bounds())
+	}
+}
@@ -2,12 +2,80 @@ package routers
 
 import (
 	"enterprise-project1-mediahub/mediahub/controller"
+	"enterprise-project1-mediahub/mediahub/middleware"
+	"enterprise-project1-mediahub/mediahub/pkg/auth"
+	"enterprise-project1-mediahub/mediahub/pkg/rbac"
 	"github.com/gin-gonic/gin"
 )
 
-func InitRouters(api *gin.RouterGroup, c *controller.Controller) {
+func InitRouters(api *gin.RouterGroup, c *controller.Controller, enforcer *rbac.Enforcer) {
+	read := middleware.RequireScope(auth.ScopeMediaRead)
+	upload := middleware.RequireScope(auth.ScopeMediaUpload)
+	write := middleware.RequireScope(auth.ScopeMediaWrite)
+
 	v1 := api.Group("/v1")
 	fileGroup := v1.Group("/file")
-	fileGroup.POST("/upload", c.Upload)
+	fileGroup.POST("/upload", upload, c.Upload)
 	v1.GET("/home", c.Home)
+
+	mediaGroup := v1.Group("/media")
+	// 私有媒体的短链落地与签名下载路由由签名保护，不要求登录
+	mediaGroup.GET("/:id/access/:sig", c.MediaAccess)
+	mediaGroup.GET("/:id/download", c.MediaDownload)
+
+	ownGroup := mediaGroup.Group("", middleware.RequireLogin())
+	ownGroup.GET("/:id/url", read, c.MediaSignedUrl)
+	ownGroup.POST("/:id/revoke", write, c.MediaRevoke)
+	ownGroup.POST("/:id/grant", write, c.MediaGrant)
+	ownGroup.DELETE("/:id", write, c.MediaDelete)
+	ownGroup.POST("/:id/restore", write, c.MediaRestore)
+	ownGroup.POST("/:id/replace", write, c.MediaReplace)
+	ownGroup.GET("/:id/versions", read, c.MediaVersions)
+	ownGroup.POST("/:id/versions/:version/restore", write, c.MediaVersionRestore)
+	ownGroup.GET("/:id/original", read, c.MediaOriginal)
+
+	trashGroup := v1.Group("/trash", middleware.RequireLogin())
+	trashGroup.GET("", read, c.TrashList)
+	trashGroup.DELETE("", write, c.TrashEmpty)
+
+	watermarkGroup := v1.Group("/watermark", middleware.RequireLogin())
+	watermarkGroup.GET("", read, c.WatermarkGet)
+	watermarkGroup.PUT("", write, c.WatermarkSave)
+
+	shortUrlGroup := v1.Group("/shorturls", middleware.RequireLogin())
+	shortUrlGroup.GET("", read, c.ShortUrlList)
+	shortUrlGroup.GET("/:key", read, c.ShortUrlInfo)
+
+	apiKeyGroup := v1.Group("/apikeys", middleware.RequireLogin())
+	apiKeyGroup.GET("", read, c.ApiKeyList)
+	apiKeyGroup.POST("", write, c.ApiKeyCreate)
+	apiKeyGroup.DELETE("/:id", write, c.ApiKeyRevoke)
+
+	v1.GET("/permissions", middleware.RequireLogin(), c.Permissions)
+
+	// 浏览器会话：使用令牌换取 HttpOnly 会话 Cookie，使用会话的状态变更请求需要回传 CSRF 令牌
+	v1.POST("/session", middleware.RequireLogin(), c.SessionCreate)
+	v1.GET("/session", c.SessionGet)
+	v1.DELETE("/session", c.SessionDelete)
+	sessionGroup := v1.Group("/sessions", middleware.RequireLogin())
+	sessionGroup.GET("", c.SessionList)
+	sessionGroup.DELETE("", c.SessionRevokeAll)
+	sessionGroup.DELETE("/:id", c.SessionRevoke)
+
+	// 管理接口按角色权限控制
+	adminGroup := v1.Group("/admin", middleware.RequireLogin())
+	adminGroup.GET("/stats", middleware.RequirePermission(enforcer, rbac.PermStatsView), c.AdminStats)
+	adminGroup.DELETE("/shorturls/:type/:key", middleware.RequirePermission(enforcer, rbac.PermShortUrlPurge), c.AdminPurgeShortUrl)
+
+	auditView := middleware.RequirePermission(enforcer, rbac.PermAuditView)
+	adminGroup.GET("/audit", auditView, c.AuditList)
+	adminGroup.GET("/audit/export", auditView, c.AuditExport)
+
+	manage := middleware.RequirePermission(enforcer, rbac.PermRbacManage)
+	adminGroup.GET("/roles", manage, c.RoleList)
+	adminGroup.PUT("/roles/:name", manage, c.RoleSave)
+	adminGroup.DELETE("/roles/:name", manage, c.RoleDelete)
+	adminGroup.GET("/users/:uid/roles", manage, c.UserRoleList)
+	adminGroup.POST("/users/:uid/roles", manage, c.UserRoleAssign)
+	adminGroup.DELETE("/users/:uid/roles/:role", manage, c.UserRoleUnassign)
 }
@@ -3,9 +3,24 @@ package services
 import (
 	"context"
 	"google.golang.org/grpc/metadata"
+	"strconv"
 )
 
+// AppendBearerTokenToContext 在请求元数据中携带访问令牌，令牌为空时（使用 mTLS 客户端证书认证）不添加
 func AppendBearerTokenToContext(ctx context.Context, accessToken string) context.Context {
+	if accessToken == "" {
+		return ctx
+	}
 	md := metadata.Pairs("Authorization", "Bearer "+accessToken)
 	return metadata.NewOutgoingContext(ctx, md)
 }
+
+// AppendOperatorToContext 在请求元数据中携带操作用户ID，短链服务据此校验管理接口的权限
+func AppendOperatorToContext(ctx context.Context, userID int64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-user-id", strconv.FormatInt(userID, 10))
+}
+
+// AppendClientToContext 在请求元数据中携带终端用户的IP与 User-Agent，短链服务写入审计记录
+func AppendClientToContext(ctx context.Context, ip, userAgent string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-client-ip", ip, "x-client-user-agent", userAgent)
+}
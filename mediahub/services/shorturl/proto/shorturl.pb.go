@@ -28,6 +28,14 @@ type Url struct {
 	Url      string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
 	UserID   int64  `protobuf:"varint,2,opt,name=userID,proto3" json:"userID,omitempty"`
 	IsPublic bool   `protobuf:"varint,3,opt,name=isPublic,proto3" json:"isPublic,omitempty"`
+	// 自定义别名（可选），创建时使用，为空时生成短链键
+	Alias string `protobuf:"bytes,4,opt,name=alias,proto3" json:"alias,omitempty"`
+	// 过期时间戳（可选），0表示永不过期
+	ExpireAt int64 `protobuf:"varint,5,opt,name=expireAt,proto3" json:"expireAt,omitempty"`
+	// 最大访问次数（可选），0表示不限制
+	MaxClicks int64 `protobuf:"varint,6,opt,name=maxClicks,proto3" json:"maxClicks,omitempty"`
+	// 幂等键（可选），最长64个字符，同一范围内相同幂等键的重复请求返回首次创建的短链，指定自定义别名时忽略
+	IdempotencyKey string `protobuf:"bytes,7,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
 }
 
 func (x *Url) Reset() {
@@ -81,6 +89,34 @@ func (x *Url) GetIsPublic() bool {
 	return false
 }
 
+func (x *Url) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *Url) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *Url) GetMaxClicks() int64 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *Url) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type ShortKey struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -142,33 +178,881 @@ func (x *ShortKey) GetIsPublic() bool {
 	return false
 }
 
+type UpdateTargetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	UserID   int64  `protobuf:"varint,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	IsPublic bool   `protobuf:"varint,3,opt,name=isPublic,proto3" json:"isPublic,omitempty"`
+	Url      string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+func (x *UpdateTargetRequest) Reset() {
+	*x = UpdateTargetRequest{}
+	mi := &file_shorturl_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTargetRequest) ProtoMessage() {}
+
+func (x *UpdateTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTargetRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTargetRequest) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateTargetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *UpdateTargetRequest) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *UpdateTargetRequest) GetIsPublic() bool {
+	if x != nil {
+		return x.IsPublic
+	}
+	return false
+}
+
+func (x *UpdateTargetRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type PurgeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	IsPublic bool   `protobuf:"varint,2,opt,name=isPublic,proto3" json:"isPublic,omitempty"`
+}
+
+func (x *PurgeRequest) Reset() {
+	*x = PurgeRequest{}
+	mi := &file_shorturl_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeRequest) ProtoMessage() {}
+
+func (x *PurgeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeRequest.ProtoReflect.Descriptor instead.
+func (*PurgeRequest) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PurgeRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PurgeRequest) GetIsPublic() bool {
+	if x != nil {
+		return x.IsPublic
+	}
+	return false
+}
+
+// 用户短链列表的查询条件，零值字段不参与过滤
+type ListShortUrlsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID int64 `protobuf:"varint,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	// 创建时间范围，createFrom 含，createTo 不含
+	CreateFrom int64 `protobuf:"varint,2,opt,name=createFrom,proto3" json:"createFrom,omitempty"`
+	CreateTo   int64 `protobuf:"varint,3,opt,name=createTo,proto3" json:"createTo,omitempty"`
+	// 目标域名，只匹配该域名本身，不含子域名
+	Domain string `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	// 状态：active（正常）、expired（已过期）、paused（已停用）、disabled（已下架），为空时返回全部未删除的短链
+	Status string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// 排序：recent（按创建时间倒序，默认）、clicks（按访问次数倒序）
+	Sort string `protobuf:"bytes,6,opt,name=sort,proto3" json:"sort,omitempty"`
+	// 页码从1开始，每页默认20条，最多100条
+	Page     int32 `protobuf:"varint,7,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32 `protobuf:"varint,8,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+}
+
+func (x *ListShortUrlsRequest) Reset() {
+	*x = ListShortUrlsRequest{}
+	mi := &file_shorturl_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListShortUrlsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListShortUrlsRequest) ProtoMessage() {}
+
+func (x *ListShortUrlsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListShortUrlsRequest.ProtoReflect.Descriptor instead.
+func (*ListShortUrlsRequest) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListShortUrlsRequest) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *ListShortUrlsRequest) GetCreateFrom() int64 {
+	if x != nil {
+		return x.CreateFrom
+	}
+	return 0
+}
+
+func (x *ListShortUrlsRequest) GetCreateTo() int64 {
+	if x != nil {
+		return x.CreateTo
+	}
+	return 0
+}
+
+func (x *ListShortUrlsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ListShortUrlsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListShortUrlsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListShortUrlsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListShortUrlsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ShortUrlInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserID      int64  `protobuf:"varint,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	ShortKey    string `protobuf:"bytes,3,opt,name=shortKey,proto3" json:"shortKey,omitempty"`
+	ShortUrl    string `protobuf:"bytes,4,opt,name=shortUrl,proto3" json:"shortUrl,omitempty"`
+	OriginalUrl string `protobuf:"bytes,5,opt,name=originalUrl,proto3" json:"originalUrl,omitempty"`
+	Times       int64  `protobuf:"varint,6,opt,name=times,proto3" json:"times,omitempty"`
+	// 状态：0正常，1已下架，2已停用
+	Status int32 `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	// 是否已过期或已达到访问次数上限
+	Expired   bool  `protobuf:"varint,8,opt,name=expired,proto3" json:"expired,omitempty"`
+	ExpireAt  int64 `protobuf:"varint,9,opt,name=expireAt,proto3" json:"expireAt,omitempty"`
+	MaxClicks int64 `protobuf:"varint,10,opt,name=maxClicks,proto3" json:"maxClicks,omitempty"`
+	CreateAt  int64 `protobuf:"varint,11,opt,name=createAt,proto3" json:"createAt,omitempty"`
+	UpdateAt  int64 `protobuf:"varint,12,opt,name=updateAt,proto3" json:"updateAt,omitempty"`
+}
+
+func (x *ShortUrlInfo) Reset() {
+	*x = ShortUrlInfo{}
+	mi := &file_shorturl_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortUrlInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortUrlInfo) ProtoMessage() {}
+
+func (x *ShortUrlInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortUrlInfo.ProtoReflect.Descriptor instead.
+func (*ShortUrlInfo) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ShortUrlInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ShortUrlInfo) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *ShortUrlInfo) GetShortKey() string {
+	if x != nil {
+		return x.ShortKey
+	}
+	return ""
+}
+
+func (x *ShortUrlInfo) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *ShortUrlInfo) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *ShortUrlInfo) GetTimes() int64 {
+	if x != nil {
+		return x.Times
+	}
+	return 0
+}
+
+func (x *ShortUrlInfo) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ShortUrlInfo) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+func (x *ShortUrlInfo) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *ShortUrlInfo) GetMaxClicks() int64 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *ShortUrlInfo) GetCreateAt() int64 {
+	if x != nil {
+		return x.CreateAt
+	}
+	return 0
+}
+
+func (x *ShortUrlInfo) GetUpdateAt() int64 {
+	if x != nil {
+		return x.UpdateAt
+	}
+	return 0
+}
+
+type ShortUrlList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List  []*ShortUrlInfo `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+	Total int64           `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *ShortUrlList) Reset() {
+	*x = ShortUrlList{}
+	mi := &file_shorturl_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortUrlList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortUrlList) ProtoMessage() {}
+
+func (x *ShortUrlList) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortUrlList.ProtoReflect.Descriptor instead.
+func (*ShortUrlList) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ShortUrlList) GetList() []*ShortUrlInfo {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *ShortUrlList) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 批量生成短链，每项的参数与 GetShortUrl 相同
+type BatchUrlRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Urls []*Url `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+}
+
+func (x *BatchUrlRequest) Reset() {
+	*x = BatchUrlRequest{}
+	mi := &file_shorturl_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUrlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUrlRequest) ProtoMessage() {}
+
+func (x *BatchUrlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUrlRequest.ProtoReflect.Descriptor instead.
+func (*BatchUrlRequest) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchUrlRequest) GetUrls() []*Url {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+// 批量解析短链，每项的参数与 GetOriginalUrl 相同
+type BatchShortKeyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keys []*ShortKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+}
+
+func (x *BatchShortKeyRequest) Reset() {
+	*x = BatchShortKeyRequest{}
+	mi := &file_shorturl_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchShortKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchShortKeyRequest) ProtoMessage() {}
+
+func (x *BatchShortKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchShortKeyRequest.ProtoReflect.Descriptor instead.
+func (*BatchShortKeyRequest) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchShortKeyRequest) GetKeys() []*ShortKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// 批量处理中单项的结果，成功时 code 为0；失败时 url 为空，code、error 为该项的 gRPC 状态码与错误信息
+type BatchUrlResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url   *Url   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Code  int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *BatchUrlResult) Reset() {
+	*x = BatchUrlResult{}
+	mi := &file_shorturl_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUrlResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUrlResult) ProtoMessage() {}
+
+func (x *BatchUrlResult) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUrlResult.ProtoReflect.Descriptor instead.
+func (*BatchUrlResult) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchUrlResult) GetUrl() *Url {
+	if x != nil {
+		return x.Url
+	}
+	return nil
+}
+
+func (x *BatchUrlResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchUrlResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 批量处理的结果，顺序与请求一致
+type BatchUrlResults struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*BatchUrlResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchUrlResults) Reset() {
+	*x = BatchUrlResults{}
+	mi := &file_shorturl_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUrlResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUrlResults) ProtoMessage() {}
+
+func (x *BatchUrlResults) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUrlResults.ProtoReflect.Descriptor instead.
+func (*BatchUrlResults) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchUrlResults) GetResults() []*BatchUrlResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type PermissionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID int64 `protobuf:"varint,1,opt,name=userID,proto3" json:"userID,omitempty"`
+}
+
+func (x *PermissionRequest) Reset() {
+	*x = PermissionRequest{}
+	mi := &file_shorturl_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionRequest) ProtoMessage() {}
+
+func (x *PermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionRequest.ProtoReflect.Descriptor instead.
+func (*PermissionRequest) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PermissionRequest) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type Permissions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Permissions []string `protobuf:"bytes,1,rep,name=permissions,proto3" json:"permissions,omitempty"`
+}
+
+func (x *Permissions) Reset() {
+	*x = Permissions{}
+	mi := &file_shorturl_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Permissions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Permissions) ProtoMessage() {}
+
+func (x *Permissions) ProtoReflect() protoreflect.Message {
+	mi := &file_shorturl_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Permissions.ProtoReflect.Descriptor instead.
+func (*Permissions) Descriptor() ([]byte, []int) {
+	return file_shorturl_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Permissions) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
 var File_shorturl_proto protoreflect.FileDescriptor
 
 var file_shorturl_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x14, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61,
-	0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x22, 0x4b, 0x0a, 0x03, 0x55, 0x72, 0x6c, 0x12, 0x10, 0x0a,
-	0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12,
-	0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62,
-	0x6c, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62,
-	0x6c, 0x69, 0x63, 0x22, 0x50, 0x0a, 0x08, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50,
-	0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50,
-	0x75, 0x62, 0x6c, 0x69, 0x63, 0x32, 0x9c, 0x01, 0x0a, 0x08, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
-	0x72, 0x6c, 0x12, 0x43, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72,
-	0x6c, 0x12, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65,
-	0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x1a, 0x19, 0x2e, 0x73,
-	0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x12, 0x4b, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4f, 0x72,
-	0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12, 0x1e, 0x2e, 0x73, 0x68, 0x6f, 0x72,
+	0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x22, 0xc3, 0x01, 0x0a, 0x03, 0x55, 0x72, 0x6c, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c,
+	0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69,
+	0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c,
+	0x69, 0x63, 0x6b, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x64,
+	0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x22, 0x50, 0x0a, 0x08,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72,
+	0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x22, 0x6d,
+	0x0a, 0x13, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12,
+	0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x3c, 0x0a,
+	0x0c, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x22, 0xde, 0x01, 0x0a, 0x14,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0xca, 0x02, 0x0a,
+	0x0c, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65,
+	0x79, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x20, 0x0a,
+	0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x41, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x74, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x74, 0x22, 0x5c, 0x0a, 0x0c, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x72, 0x6c, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x04, 0x6c, 0x69, 0x73,
+	0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75,
+	0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x6c, 0x69, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x40, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x55, 0x72, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x04, 0x75, 0x72,
+	0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e,
+	0x55, 0x72, 0x6c, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x22, 0x4a, 0x0a, 0x14, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x32, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61,
+	0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x52,
+	0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x67, 0x0a, 0x0e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72,
+	0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e,
+	0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x52,
+	0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x51,
+	0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x3e, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68,
+	0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55,
+	0x72, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x22, 0x2b, 0x0a, 0x11, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0x2f,
+	0x0a, 0x0b, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a,
+	0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x32,
+	0x90, 0x08, 0x0a, 0x08, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x43, 0x0a, 0x0b,
+	0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x19, 0x2e, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x1a, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72,
+	0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72,
+	0x6c, 0x12, 0x4b, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c,
+	0x55, 0x72, 0x6c, 0x12, 0x1e, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63,
+	0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x4b, 0x65, 0x79, 0x1a, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63,
+	0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x12, 0x60,
+	0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x72, 0x6c, 0x12, 0x25, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68,
+	0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55,
+	0x72, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x73, 0x68, 0x6f, 0x72,
 	0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x1a, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x72, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x12, 0x68, 0x0a, 0x13, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12, 0x2a, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75,
+	0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63,
+	0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x55, 0x72, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x54, 0x0a, 0x0c, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x29, 0x2e, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c,
+	0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c,
+	0x12, 0x4c, 0x0a, 0x0f, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x72, 0x6c, 0x12, 0x1e, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63,
+	0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x4b, 0x65, 0x79, 0x1a, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63,
+	0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x12, 0x4b,
+	0x0a, 0x0e, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c,
+	0x12, 0x1e, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e,
+	0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79,
+	0x1a, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e,
+	0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x12, 0x4b, 0x0a, 0x0e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x1e, 0x2e,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x1a, 0x19, 0x2e,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c, 0x12, 0x5f, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x73, 0x12, 0x2a, 0x2e, 0x73, 0x68, 0x6f, 0x72,
 	0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2e, 0x55, 0x72, 0x6c, 0x42, 0x10, 0x5a, 0x0e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c,
+	0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x72, 0x6c, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x55, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1e, 0x2e, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x1a, 0x22, 0x2e, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x4e, 0x0a, 0x0d, 0x50, 0x75, 0x72, 0x67, 0x65, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72,
+	0x6c, 0x12, 0x22, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65,
+	0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c,
+	0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x55, 0x72, 0x6c,
+	0x12, 0x60, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x50, 0x65, 0x72, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x27, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72,
+	0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61, 0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x50, 0x65,
+	0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x21, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2e, 0x63, 0x68, 0x65, 0x6e, 0x61,
+	0x77, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x42, 0x10, 0x5a, 0x0e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x72, 0x6c, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -183,21 +1067,57 @@ func file_shorturl_proto_rawDescGZIP() []byte {
 	return file_shorturl_proto_rawDescData
 }
 
-var file_shorturl_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_shorturl_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_shorturl_proto_goTypes = []any{
-	(*Url)(nil),      // 0: shorturl.chenaws.com.Url
-	(*ShortKey)(nil), // 1: shorturl.chenaws.com.ShortKey
+	(*Url)(nil),                  // 0: shorturl.chenaws.com.Url
+	(*ShortKey)(nil),             // 1: shorturl.chenaws.com.ShortKey
+	(*UpdateTargetRequest)(nil),  // 2: shorturl.chenaws.com.UpdateTargetRequest
+	(*PurgeRequest)(nil),         // 3: shorturl.chenaws.com.PurgeRequest
+	(*ListShortUrlsRequest)(nil), // 4: shorturl.chenaws.com.ListShortUrlsRequest
+	(*ShortUrlInfo)(nil),         // 5: shorturl.chenaws.com.ShortUrlInfo
+	(*ShortUrlList)(nil),         // 6: shorturl.chenaws.com.ShortUrlList
+	(*BatchUrlRequest)(nil),      // 7: shorturl.chenaws.com.BatchUrlRequest
+	(*BatchShortKeyRequest)(nil), // 8: shorturl.chenaws.com.BatchShortKeyRequest
+	(*BatchUrlResult)(nil),       // 9: shorturl.chenaws.com.BatchUrlResult
+	(*BatchUrlResults)(nil),      // 10: shorturl.chenaws.com.BatchUrlResults
+	(*PermissionRequest)(nil),    // 11: shorturl.chenaws.com.PermissionRequest
+	(*Permissions)(nil),          // 12: shorturl.chenaws.com.Permissions
 }
 var file_shorturl_proto_depIdxs = []int32{
-	0, // 0: shorturl.chenaws.com.ShortUrl.GetShortUrl:input_type -> shorturl.chenaws.com.Url
-	1, // 1: shorturl.chenaws.com.ShortUrl.GetOriginalUrl:input_type -> shorturl.chenaws.com.ShortKey
-	0, // 2: shorturl.chenaws.com.ShortUrl.GetShortUrl:output_type -> shorturl.chenaws.com.Url
-	0, // 3: shorturl.chenaws.com.ShortUrl.GetOriginalUrl:output_type -> shorturl.chenaws.com.Url
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5,  // 0: shorturl.chenaws.com.ShortUrlList.list:type_name -> shorturl.chenaws.com.ShortUrlInfo
+	0,  // 1: shorturl.chenaws.com.BatchUrlRequest.urls:type_name -> shorturl.chenaws.com.Url
+	1,  // 2: shorturl.chenaws.com.BatchShortKeyRequest.keys:type_name -> shorturl.chenaws.com.ShortKey
+	0,  // 3: shorturl.chenaws.com.BatchUrlResult.url:type_name -> shorturl.chenaws.com.Url
+	9,  // 4: shorturl.chenaws.com.BatchUrlResults.results:type_name -> shorturl.chenaws.com.BatchUrlResult
+	0,  // 5: shorturl.chenaws.com.ShortUrl.GetShortUrl:input_type -> shorturl.chenaws.com.Url
+	1,  // 6: shorturl.chenaws.com.ShortUrl.GetOriginalUrl:input_type -> shorturl.chenaws.com.ShortKey
+	7,  // 7: shorturl.chenaws.com.ShortUrl.BatchGetShortUrl:input_type -> shorturl.chenaws.com.BatchUrlRequest
+	8,  // 8: shorturl.chenaws.com.ShortUrl.BatchGetOriginalUrl:input_type -> shorturl.chenaws.com.BatchShortKeyRequest
+	2,  // 9: shorturl.chenaws.com.ShortUrl.UpdateTarget:input_type -> shorturl.chenaws.com.UpdateTargetRequest
+	1,  // 10: shorturl.chenaws.com.ShortUrl.DisableShortUrl:input_type -> shorturl.chenaws.com.ShortKey
+	1,  // 11: shorturl.chenaws.com.ShortUrl.EnableShortUrl:input_type -> shorturl.chenaws.com.ShortKey
+	1,  // 12: shorturl.chenaws.com.ShortUrl.DeleteShortUrl:input_type -> shorturl.chenaws.com.ShortKey
+	4,  // 13: shorturl.chenaws.com.ShortUrl.ListShortUrls:input_type -> shorturl.chenaws.com.ListShortUrlsRequest
+	1,  // 14: shorturl.chenaws.com.ShortUrl.GetShortUrlInfo:input_type -> shorturl.chenaws.com.ShortKey
+	3,  // 15: shorturl.chenaws.com.ShortUrl.PurgeShortUrl:input_type -> shorturl.chenaws.com.PurgeRequest
+	11, // 16: shorturl.chenaws.com.ShortUrl.GetUserPermissions:input_type -> shorturl.chenaws.com.PermissionRequest
+	0,  // 17: shorturl.chenaws.com.ShortUrl.GetShortUrl:output_type -> shorturl.chenaws.com.Url
+	0,  // 18: shorturl.chenaws.com.ShortUrl.GetOriginalUrl:output_type -> shorturl.chenaws.com.Url
+	10, // 19: shorturl.chenaws.com.ShortUrl.BatchGetShortUrl:output_type -> shorturl.chenaws.com.BatchUrlResults
+	10, // 20: shorturl.chenaws.com.ShortUrl.BatchGetOriginalUrl:output_type -> shorturl.chenaws.com.BatchUrlResults
+	0,  // 21: shorturl.chenaws.com.ShortUrl.UpdateTarget:output_type -> shorturl.chenaws.com.Url
+	0,  // 22: shorturl.chenaws.com.ShortUrl.DisableShortUrl:output_type -> shorturl.chenaws.com.Url
+	0,  // 23: shorturl.chenaws.com.ShortUrl.EnableShortUrl:output_type -> shorturl.chenaws.com.Url
+	0,  // 24: shorturl.chenaws.com.ShortUrl.DeleteShortUrl:output_type -> shorturl.chenaws.com.Url
+	6,  // 25: shorturl.chenaws.com.ShortUrl.ListShortUrls:output_type -> shorturl.chenaws.com.ShortUrlList
+	5,  // 26: shorturl.chenaws.com.ShortUrl.GetShortUrlInfo:output_type -> shorturl.chenaws.com.ShortUrlInfo
+	0,  // 27: shorturl.chenaws.com.ShortUrl.PurgeShortUrl:output_type -> shorturl.chenaws.com.Url
+	12, // 28: shorturl.chenaws.com.ShortUrl.GetUserPermissions:output_type -> shorturl.chenaws.com.Permissions
+	17, // [17:29] is the sub-list for method output_type
+	5,  // [5:17] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_shorturl_proto_init() }
@@ -211,7 +1131,7 @@ func file_shorturl_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_shorturl_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
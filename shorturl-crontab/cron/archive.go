@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"context"
+	"shorturl-crontab/data"
+	"shorturl-crontab/pkg/db/mysql"
+	"shorturl-crontab/pkg/db/redis"
+	"shorturl-crontab/pkg/log"
+	"time"
+)
+
+const (
+	archiveBatchSize = 500 // 每批归档的短链数量
+
+	// archiveDelay 短链过期后在原表中保留的时间（秒），期间访问展示失效页面，归档后按短链不存在处理
+	archiveDelay = 7 * 86400
+)
+
+// archiveExpiredUrls 将过期超过 archiveDelay 的短链移入归档表，并清理对应的缓存
+func archiveExpiredUrls() {
+	db := data.NewData(mysql.GetDB())
+
+	redisPool := redis.GetPool()
+	client := redisPool.Get()
+	defer redisPool.Put(client)
+
+	tables := []struct {
+		name      string
+		keyPrefix string // 与 shorturl-server 一致的缓存键前缀
+	}{
+		{"url_map", ""},
+		{"url_map_user", "user_"},
+	}
+	for _, t := range tables {
+		archived := 0
+		for {
+			now := time.Now().Unix()
+			list, err := db.GetExpiredUrls(t.name, now-archiveDelay, archiveBatchSize)
+			if err != nil {
+				log.Error(err)
+				break
+			}
+			if len(list) == 0 {
+				break
+			}
+
+			ids := make([]int64, 0, len(list))
+			for _, u := range list {
+				ids = append(ids, u.ID)
+			}
+			if err = db.ArchiveUrls(t.name, ids, now); err != nil {
+				log.Error(err)
+				break
+			}
+			archived += len(list)
+
+			// 删除分布式缓存并通知 shorturl-server 各实例删除本地缓存
+			for _, u := range list {
+				cacheKey := t.keyPrefix + u.ShortKey
+				if err = client.Del(context.Background(), redis.GetKey(cacheKey)).Err(); err != nil {
+					log.Error(err)
+				}
+				if err = client.Publish(context.Background(), redis.GetKey(cacheInvalidateChannel), cacheKey).Err(); err != nil {
+					log.Error(err)
+				}
+			}
+
+			if len(list) < archiveBatchSize {
+				break
+			}
+		}
+		log.InfoF("过期短链归档完成，表 %s 共 %d 条", t.name, archived)
+	}
+}
@@ -12,13 +12,16 @@ import (
 
 const DefaultUrlMapTTL = 30 * 86400 // 默认URL映射缓存有效期30天（单位：秒）
 
-// Run 启动定时任务，每天凌晨3点执行setUrlMapID函数，并立即执行一次初始化。
+// Run 启动定时任务，每天凌晨3点执行setUrlMapID函数，凌晨4点15分清理孤立的短链记录，每小时执行一次回收站清除和过期短链归档，并立即执行一次初始化。
 // 该函数负责初始化和运行cron调度器。
 func Run() {
 	setUrlMapID() // 初始化时立即执行一次
 	c := cron.New()
 	// (min hour day month year)
-	c.AddFunc("0 3 * * *", setUrlMapID) // 每日3点执行定时任务
+	c.AddFunc("0 3 * * *", setUrlMapID)         // 每日3点执行定时任务
+	c.AddFunc("0 * * * *", purgeTrash)          // 每小时清除回收站中到期的媒体
+	c.AddFunc("30 * * * *", archiveExpiredUrls) // 每小时归档过期的短链
+	c.AddFunc("15 4 * * *", cleanOrphanUrls)    // 每日4点15分清理没有短链键的孤立记录
 	c.Run()
 }
 
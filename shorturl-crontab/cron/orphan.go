@@ -0,0 +1,35 @@
+package cron
+
+import (
+	"shorturl-crontab/data"
+	"shorturl-crontab/pkg/db/mysql"
+	"shorturl-crontab/pkg/log"
+	"time"
+)
+
+const (
+	orphanBatchSize = 1000 // 每批删除的孤立记录数量
+
+	// orphanDelay 孤立记录创建后保留的时间（秒），避免删除旧版本实例正在回填短链键的记录
+	orphanDelay = 3600
+)
+
+// cleanOrphanUrls 分批删除没有短链键的孤立记录
+func cleanOrphanUrls() {
+	db := data.NewData(mysql.GetDB())
+	for _, t := range []string{"url_map", "url_map_user"} {
+		var deleted int64
+		for {
+			n, err := db.DeleteOrphanUrls(t, time.Now().Unix()-orphanDelay, orphanBatchSize)
+			if err != nil {
+				log.Error(err)
+				break
+			}
+			deleted += n
+			if n < orphanBatchSize {
+				break
+			}
+		}
+		log.InfoF("孤立短链记录清理完成，表 %s 共 %d 条", t, deleted)
+	}
+}
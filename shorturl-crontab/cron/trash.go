@@ -0,0 +1,141 @@
+package cron
+
+import (
+	"context"
+	"path"
+	"shorturl-crontab/data"
+	"shorturl-crontab/pkg/config"
+	"shorturl-crontab/pkg/db/mysql"
+	"shorturl-crontab/pkg/db/redis"
+	"shorturl-crontab/pkg/log"
+	"shorturl-crontab/pkg/storage/cos"
+	"time"
+)
+
+const (
+	purgeBatchSize = 100 // 每批清除的媒体数量
+
+	// cacheInvalidateChannel 与 shorturl-server 约定的本地缓存失效频道（不含服务前缀）
+	cacheInvalidateChannel = "cache_invalidate"
+)
+
+// deleteObjects 删除没有其他引用的存储对象
+func deleteObjects(storage *cos.Storage, paths []string, countRefs func(string) (int64, error)) error {
+	for _, p := range paths {
+		refs, err := countRefs(p)
+		if err != nil {
+			return err
+		}
+		if refs > 0 {
+			continue
+		}
+		if err = storage.Delete(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// disableShortUrl 禁用媒体的短链，返回需要清理的缓存键
+// 相同内容的媒体复用同一短链，仍被其他媒体使用时不禁用，返回空的缓存键
+// 参数:
+//
+//	shortUrl: 媒体的短链，格式为 域名 + short_key
+//	userID: 媒体所属用户，用户上传对应用户短链表
+//	countRefs: 统计仍使用该短链的其他媒体数量
+//	disable: 在指定表中禁用短链键
+func disableShortUrl(shortUrl string, userID int64, countRefs func(string) (int64, error), disable func(tableName, shortKey string) error) (string, error) {
+	refs, err := countRefs(shortUrl)
+	if err != nil || refs > 0 {
+		return "", err
+	}
+	shortKey := path.Base(shortUrl)
+	tableName, cacheKey := "url_map", shortKey
+	if userID != 0 {
+		tableName, cacheKey = "url_map_user", "user_"+shortKey
+	}
+	if err = disable(tableName, shortKey); err != nil {
+		return "", err
+	}
+	return cacheKey, nil
+}
+
+// purgeTrash 彻底清除回收站中到期的媒体
+// 依次删除存储对象、禁用没有其他媒体使用的短链并清理短链缓存，最后删除媒体记录
+// 任一步骤失败时保留媒体记录，下次执行时重试
+func purgeTrash() {
+	cnf := config.GetConfig()
+	storage := cos.NewStorage(cnf.Cos.BucketUrl, cnf.Cos.SecretId, cnf.Cos.SecretKey)
+	db := data.NewData(mysql.GetDB())
+
+	redisPool := redis.GetPool()
+	client := redisPool.Get()
+	defer redisPool.Put(client)
+
+	for {
+		now := time.Now().Unix()
+		list, err := db.GetExpiredTrash(now, purgeBatchSize)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+
+		purged := 0
+		for _, m := range list {
+			// 删除媒体所有版本的存储对象，仍被其他媒体引用的对象跳过
+			paths, err := db.GetMediaObjectPaths(m.ID)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			if len(paths) == 0 {
+				// 版本功能上线前的媒体没有版本记录
+				paths = []string{m.ObjectPath}
+				if m.OriginalPath != "" {
+					paths = append(paths, m.OriginalPath)
+				}
+			}
+			if err = deleteObjects(storage, paths, func(p string) (int64, error) {
+				return db.CountObjectRefs(p, m.ID)
+			}); err != nil {
+				log.Error(err)
+				continue
+			}
+
+			// 禁用短链并清理短链缓存，短链仍被其他媒体使用时跳过
+			cacheKey := ""
+			if m.ShortUrl != "" {
+				cacheKey, err = disableShortUrl(m.ShortUrl, m.UserID, func(u string) (int64, error) {
+					return db.CountShortUrlRefs(u, m.ID)
+				}, func(tableName, shortKey string) error {
+					return db.DisableShortUrl(tableName, shortKey, now)
+				})
+				if err != nil {
+					log.Error(err)
+					continue
+				}
+			}
+			if cacheKey != "" {
+				// 删除分布式缓存并通知 shorturl-server 各实例删除本地缓存
+				if err = client.Del(context.Background(), redis.GetKey(cacheKey)).Err(); err != nil {
+					log.Error(err)
+				}
+				if err = client.Publish(context.Background(), redis.GetKey(cacheInvalidateChannel), cacheKey).Err(); err != nil {
+					log.Error(err)
+				}
+			}
+
+			if err = db.DeleteMedia(m.ID); err != nil {
+				log.Error(err)
+				continue
+			}
+			purged++
+		}
+
+		log.InfoF("回收站清除完成，本批 %d 条，成功 %d 条", len(list), purged)
+		// 本批全部失败时不再继续，避免对同一批数据反复重试
+		if len(list) < purgeBatchSize || purged == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package cron
+
+import "testing"
+
+func TestDisableShortUrl(t *testing.T) {
+	// 两条媒体共用同一短链：清除第一条时另一条仍在使用，不禁用
+	refs := map[string]int64{"https://u.example.com/abc123": 2}
+	var disabled []string
+	countRefs := func(u string) (int64, error) { return refs[u] - 1, nil }
+	disable := func(tableName, shortKey string) error {
+		disabled = append(disabled, tableName+"/"+shortKey)
+		return nil
+	}
+
+	cacheKey, err := disableShortUrl("https://u.example.com/abc123", 7, countRefs, disable)
+	if err != nil || cacheKey != "" || len(disabled) != 0 {
+		t.Fatalf("shared link: cacheKey=%q err=%v disabled=%v", cacheKey, err, disabled)
+	}
+
+	// 清除最后一条媒体时禁用短链
+	refs["https://u.example.com/abc123"] = 1
+	cacheKey, err = disableShortUrl("https://u.example.com/abc123", 7, countRefs, disable)
+	if err != nil || cacheKey != "user_abc123" || len(disabled) != 1 || disabled[0] != "url_map_user/abc123" {
+		t.Fatalf("last link: cacheKey=%q err=%v disabled=%v", cacheKey, err, disabled)
+	}
+
+	// 公共上传对应公共短链表
+	cacheKey, err = disableShortUrl("https://s.example.com/xyz", 0, func(string) (int64, error) { return 0, nil }, disable)
+	if err != nil || cacheKey != "xyz" || disabled[1] != "url_map/xyz" {
+		t.Fatalf("public link: cacheKey=%q err=%v disabled=%v", cacheKey, err, disabled)
+	}
+}
@@ -0,0 +1,103 @@
+package data
+
+import "fmt"
+
+// TrashMedia 回收站中到期待清除的媒体
+type TrashMedia struct {
+	ID           int64
+	UserID       int64
+	ObjectPath   string
+	OriginalPath string
+	ShortUrl     string
+}
+
+// GetExpiredTrash 查询到期待清除的回收站媒体
+func (d *data) GetExpiredTrash(now int64, limit int) ([]TrashMedia, error) {
+	sqlStr := "select id, user_id, object_path, original_path, short_url from media where deleted_at > 0 and purge_at <= ? limit ?"
+	rows, err := d.db.Query(sqlStr, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []TrashMedia
+	for rows.Next() {
+		var m TrashMedia
+		err = rows.Scan(&m.ID, &m.UserID, &m.ObjectPath, &m.OriginalPath, &m.ShortUrl)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, m)
+	}
+	return results, rows.Err()
+}
+
+// GetMediaObjectPaths 查询媒体各版本使用的对象路径及原图路径（去重）
+func (d *data) GetMediaObjectPaths(mediaID int64) ([]string, error) {
+	sqlStr := "select object_path from media_version where media_id = ? " +
+		"union select original_path from media_version where media_id = ? and original_path <> ''"
+	rows, err := d.db.Query(sqlStr, mediaID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var p string
+		if err = rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, rows.Err()
+}
+
+// CountObjectRefs 统计除 excludeID 外仍引用同一对象的媒体及媒体版本数量
+// 同一用户重复上传相同内容会得到相同的对象路径，只有没有其他引用时才能删除对象
+func (d *data) CountObjectRefs(objectPath string, excludeID int64) (int64, error) {
+	sqlStr := "select (select count(*) from media where (object_path = ? or original_path = ?) and id <> ?) + " +
+		"(select count(*) from media_version where (object_path = ? or original_path = ?) and media_id <> ?)"
+	var n int64
+	err := d.db.QueryRow(sqlStr, objectPath, objectPath, excludeID, objectPath, objectPath, excludeID).Scan(&n)
+	return n, err
+}
+
+// CountShortUrlRefs 统计除 excludeID 外仍使用同一短链的媒体数量，包括回收站中尚未清除的媒体
+// 同一用户重复上传相同内容、公共上传相同对象时复用同一短链，只有没有其他媒体使用时才能禁用短链
+func (d *data) CountShortUrlRefs(shortUrl string, excludeID int64) (int64, error) {
+	var n int64
+	err := d.db.QueryRow("select count(*) from media where short_url = ? and id <> ?", shortUrl, excludeID).Scan(&n)
+	return n, err
+}
+
+// DeleteMedia 删除媒体记录及其版本记录
+func (d *data) DeleteMedia(id int64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec("delete from media_version where media_id = ?", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err = tx.Exec("delete from media where id = ?", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// 短链状态，与 shorturl 的 constants.URL_STATUS_* 一致
+const (
+	urlStatusNormal   = 0 // 正常
+	urlStatusDisabled = 1 // 已禁用（管理员下架或媒体清除）
+	urlStatusPaused   = 2 // 已停用（属主停用或媒体移入回收站），属主可以重新启用
+)
+
+// DisableShortUrl 禁用正常或已停用的短链，禁用后属主不能再启用；已删除的短链保持原状态
+func (d *data) DisableShortUrl(tableName, shortKey string, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set status = ?, update_at = ? where short_key = ? and status in (?, ?)", tableName)
+	_, err := d.db.Exec(sqlStr, urlStatusDisabled, now, shortKey, urlStatusNormal, urlStatusPaused)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/pkg/errors"
+	"strings"
 )
 
 type data struct {
@@ -27,3 +28,77 @@ func (d *data) GetMaxID(tableName string) (int64, error) {
 	}
 	return 0, nil
 }
+
+// urlMapArchiveColumns 归档时复制的字段，用户短链表额外复制 user_id
+const urlMapArchiveColumns = "id,short_key,alias,original_url,times,status,expire_at,max_clicks,create_at,update_at"
+
+// ExpiredUrl 待归档的过期短链
+type ExpiredUrl struct {
+	ID       int64
+	ShortKey string
+}
+
+// GetExpiredUrls 查询在 before 之前过期，或已达到访问次数上限且 before 之后没有再更新的短链
+func (d *data) GetExpiredUrls(tableName string, before int64, limit int) ([]ExpiredUrl, error) {
+	sqlStr := fmt.Sprintf("select id, short_key from %s where (expire_at > 0 and expire_at < ?) "+
+		"or (max_clicks > 0 and times >= max_clicks and update_at < ?) order by id limit ?", tableName)
+	rows, err := d.db.Query(sqlStr, before, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]ExpiredUrl, 0)
+	for rows.Next() {
+		var u ExpiredUrl
+		if err = rows.Scan(&u.ID, &u.ShortKey); err != nil {
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	return list, rows.Err()
+}
+
+// ArchiveUrls 在同一事务中将短链复制到归档表（表名加 _archive 后缀）并从原表删除
+func (d *data) ArchiveUrls(tableName string, ids []int64, now int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	columns := urlMapArchiveColumns
+	if tableName == "url_map_user" {
+		columns += ",user_id"
+	}
+	in := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	sqlStr := fmt.Sprintf("insert into %s_archive (%s,archive_at) select %s,? from %s where id in (%s)",
+		tableName, columns, columns, tableName, in)
+	if _, err = tx.Exec(sqlStr, append([]any{now}, args...)...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	sqlStr = fmt.Sprintf("delete from %s where id in (%s)", tableName, in)
+	if _, err = tx.Exec(sqlStr, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteOrphanUrls 删除在 before 之前创建、没有短链键的记录，返回删除的条数
+// 这类记录由旧版本先插入空记录再回填短链键的流程在中途失败时遗留，不会被访问到
+func (d *data) DeleteOrphanUrls(tableName string, before int64, limit int) (int64, error) {
+	sqlStr := fmt.Sprintf("delete from %s where short_key = '' and create_at < ? limit ?", tableName)
+	rs, err := d.db.Exec(sqlStr, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return rs.RowsAffected()
+}
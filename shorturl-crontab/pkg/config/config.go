@@ -22,6 +22,11 @@ type Config struct {
 		Level   string
 		LogPath string `mapstructure:"logPath"`
 	} `mapstructure:"log"`
+	Cos struct {
+		SecretId  string
+		SecretKey string
+		BucketUrl string
+	}
 }
 
 var conf *Config
@@ -0,0 +1,32 @@
+package cos
+
+import (
+	"context"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"net/http"
+	url1 "net/url"
+)
+
+// Storage 定时任务只需要删除对象，因此只封装删除操作
+type Storage struct {
+	client *cos.Client
+}
+
+// NewStorage 创建 COS 存储访问对象
+func NewStorage(bucketUrl, secretId, secretKey string) *Storage {
+	u, _ := url1.Parse(bucketUrl)
+	b := &cos.BaseURL{BucketURL: u}
+	client := cos.NewClient(b, &http.Client{
+		Transport: &cos.AuthorizationTransport{
+			SecretID:  secretId,
+			SecretKey: secretKey,
+		},
+	})
+	return &Storage{client: client}
+}
+
+// Delete 删除对象，对象不存在时 COS 同样返回成功
+func (s *Storage) Delete(dstPath string) error {
+	_, err := s.client.Object.Delete(context.Background(), dstPath)
+	return err
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"enterprise-project1-mediahub/shorturl-proxy/middleware"
 	"enterprise-project1-mediahub/shorturl-proxy/pkg/config"
 	"enterprise-project1-mediahub/shorturl-proxy/pkg/log"
+	"enterprise-project1-mediahub/shorturl-proxy/pkg/rbac"
 	"enterprise-project1-mediahub/shorturl-proxy/proxy"
+	"enterprise-project1-mediahub/shorturl-proxy/services/shorturl"
 	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"time"
 )
 
 var configFile = flag.String("config", "dev.config.yaml", "")
@@ -30,6 +34,11 @@ func main() {
 	logger.SetLevel(cnf.Log.Level)
 	logger.SetPrintCaller(true)
 
+	// 初始化短链服务的 gRPC 连接池，TLS 证书配置无效时拒绝启动
+	if err := shorturl.InitShortUrlClientPool(cnf); err != nil {
+		log.FatalF("初始化短链服务连接池失败: %v", err)
+	}
+
 	// 设置Gin运行模式并创建路由分组
 	gin.SetMode(cnf.Http.Mode)
 	r := gin.Default()
@@ -42,6 +51,13 @@ func main() {
 	user := r.Group("/u")
 	user.GET("/:short_key", p.UserProxy)
 
+	// 管理接口：配置了用户服务 JWT 密钥时开放，权限由短链服务按角色查询并缓存一分钟
+	if cnf.Admin.JwtSecret != "" {
+		enforcer := rbac.NewEnforcer(proxy.NewPermissionStore(cnf.DependOn.ShortUrl.AccessToken), time.Minute)
+		admin := r.Group("/admin")
+		admin.DELETE("/:type/:short_key", middleware.RequirePermission(cnf.Admin.JwtSecret, enforcer, rbac.PermShortUrlPurge), p.AdminPurge)
+	}
+
 	// 启动HTTP服务，监听指定IP和端口
 	r.Run(fmt.Sprintf("%s:%d", cnf.Http.IP, cnf.Http.Port))
 }
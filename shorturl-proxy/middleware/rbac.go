@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"encoding/json"
+	"enterprise-project1-mediahub/shorturl-proxy/pkg/log"
+	"enterprise-project1-mediahub/shorturl-proxy/pkg/rbac"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserIDKey 管理员用户ID在 gin.Context 中的键
+const UserIDKey = "user_id"
+
+// RequirePermission 校验 Authorization 中用户服务签发的 JWT，并要求用户通过角色拥有指定权限
+func RequirePermission(jwtSecret string, enforcer *rbac.Enforcer, perm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := jwtUserID(jwtSecret, c.GetHeader("Authorization"))
+		if userID == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "请先登录"})
+			return
+		}
+		allowed, err := enforcer.Allow(userID, perm)
+		if err != nil {
+			log.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "没有权限"})
+			return
+		}
+		c.Set(UserIDKey, userID)
+		c.Next()
+	}
+}
+
+// jwtUserID 校验 Bearer JWT 并返回用户ID（uid，其次 sub），无效时返回0
+func jwtUserID(secret, authorization string) int64 {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || secret == "" {
+		return 0
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired(), jwt.WithLeeway(30*time.Second))
+	if err != nil {
+		return 0
+	}
+	id := claimInt64(claims["uid"])
+	if id == 0 {
+		id = claimInt64(claims["sub"])
+	}
+	return id
+}
+
+func claimInt64(v any) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case json.Number:
+		id, _ := n.Int64()
+		return id
+	case string:
+		id, _ := strconv.ParseInt(n, 10, 64)
+		return id
+	}
+	return 0
+}
@@ -16,10 +16,21 @@ type Config struct {
 		Level   string
 		LogPath string `mapstructure:"logPath"`
 	} `mapstructure:"log"`
+	// Admin 管理接口配置，管理员使用用户服务签发的 JWT 访问，权限由短链服务查询
+	Admin struct {
+		JwtSecret string `mapstructure:"jwtSecret"` // 用户服务 JWT 的 HS256 密钥，为空时不开放管理接口
+	}
 	DependOn struct {
 		ShortUrl struct {
 			Address     string
-			AccessToken string
+			AccessToken string // 使用 mTLS 客户端证书认证时可以为空
+			TLS         struct {
+				Enable     bool
+				CertFile   string `mapstructure:"certFile"`   // 客户端证书，启用 mTLS 时配置
+				KeyFile    string `mapstructure:"keyFile"`    // 客户端私钥
+				CaFile     string `mapstructure:"caFile"`     // 校验服务端证书的 CA，为空时使用系统根证书
+				ServerName string `mapstructure:"serverName"` // 服务端证书中的主机名，为空时使用 Address 中的主机名
+			} `mapstructure:"tls"`
 		}
 	}
 }
@@ -1,7 +1,6 @@
 package grpc_client_pool
 
 import (
-	"enterprise-project1-mediahub/shorturl-proxy/pkg/log"
 	"enterprise-project1-mediahub/shorturl-proxy/pkg/zerror"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
@@ -9,41 +8,54 @@ import (
 )
 
 type ClientPool interface {
-	Get() *grpc.ClientConn
+	// Get 取出一个可用连接，无法建立连接时返回错误
+	Get() (*grpc.ClientConn, error)
 	Put(conn *grpc.ClientConn)
 }
 
+// clientPool 基于 sync.Pool 复用连接，池中没有可用连接时新建
 type clientPool struct {
-	pool sync.Pool
+	pool   sync.Pool
+	target string
+	opts   []grpc.DialOption
 }
 
 func NewPool(target string, opts ...grpc.DialOption) (ClientPool, error) {
 	return &clientPool{
-		pool: sync.Pool{
-			New: func() any {
-				conn, err := grpc.Dial(target, opts...)
-				if err != nil {
-					log.Error(zerror.NewByErr(err))
-					return nil
-				}
-				return conn
-			},
-		},
+		target: target,
+		opts:   opts,
 	}, nil
 }
 
-func (c *clientPool) Get() *grpc.ClientConn {
-	conn := c.pool.Get().(*grpc.ClientConn)
-	if conn.GetState() == connectivity.Shutdown || conn.GetState() == connectivity.TransientFailure {
-		conn = c.pool.Get().(*grpc.ClientConn) // 为什么要类型推断，因为pool源码里返回的是一个any类型
+// Get 从池中取出连接，取出的连接不可用时关闭后新建
+func (c *clientPool) Get() (*grpc.ClientConn, error) {
+	if conn, ok := c.pool.Get().(*grpc.ClientConn); ok && conn != nil {
+		if usable(conn) {
+			return conn, nil
+		}
+		conn.Close()
+	}
+	conn, err := grpc.Dial(c.target, c.opts...)
+	if err != nil {
+		return nil, zerror.NewByErr(err)
 	}
-	return conn
+	return conn, nil
 }
 
+// Put 归还连接，不可用的连接直接关闭
 func (c *clientPool) Put(conn *grpc.ClientConn) {
-	if conn.GetState() == connectivity.Shutdown || conn.GetState() == connectivity.TransientFailure {
+	if conn == nil {
+		return
+	}
+	if !usable(conn) {
 		conn.Close()
 		return
 	}
 	c.pool.Put(conn)
 }
+
+// usable 判断连接是否可以继续使用
+func usable(conn *grpc.ClientConn) bool {
+	state := conn.GetState()
+	return state != connectivity.Shutdown && state != connectivity.TransientFailure
+}
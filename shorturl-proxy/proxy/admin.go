@@ -0,0 +1,67 @@
+package proxy
+
+import (
+	"context"
+	"enterprise-project1-mediahub/shorturl-proxy/middleware"
+	"enterprise-project1-mediahub/shorturl-proxy/services"
+	"enterprise-project1-mediahub/shorturl-proxy/services/shorturl"
+	"enterprise-project1-mediahub/shorturl-proxy/services/shorturl/proto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// PermissionStore 通过短链服务查询用户的管理权限，代理服务不直接访问数据库
+type PermissionStore struct {
+	accessToken string
+}
+
+// NewPermissionStore 创建基于短链服务的用户权限存储
+func NewPermissionStore(accessToken string) *PermissionStore {
+	return &PermissionStore{accessToken: accessToken}
+}
+
+// UserPermissions 查询用户通过角色获得的全部权限
+func (s *PermissionStore) UserPermissions(userID int64) ([]string, error) {
+	pool := shorturl.NewShortUrlClientPool()
+	conn, err := pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Put(conn)
+
+	ctx := services.AppendBearerTokenToContext(context.Background(), s.accessToken)
+	rs, err := proto.NewShortUrlClient(conn).GetUserPermissions(ctx, &proto.PermissionRequest{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	return rs.Permissions, nil
+}
+
+// AdminPurge 下架短链，路径参数 type 为 p（公共短链）或 u（用户短链）
+// 下架后的短链访问时返回错误，短链服务会再次校验操作用户的权限
+func (p *Proxy) AdminPurge(ctx *gin.Context) {
+	typ := ctx.Param("type")
+	if typ != "p" && typ != "u" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "短链类型无效"})
+		return
+	}
+
+	pool := shorturl.NewShortUrlClientPool()
+	conn, err := pool.Get()
+	if err != nil {
+		p.log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "下架失败"})
+		return
+	}
+	defer pool.Put(conn)
+
+	outGoingCtx := services.AppendBearerTokenToContext(context.Background(), p.config.DependOn.ShortUrl.AccessToken)
+	outGoingCtx = services.AppendOperatorToContext(outGoingCtx, ctx.GetInt64(middleware.UserIDKey))
+	rs, err := proto.NewShortUrlClient(conn).PurgeShortUrl(outGoingCtx, &proto.PurgeRequest{Key: ctx.Param("short_key"), IsPublic: typ == "p"})
+	if err != nil {
+		p.log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "下架失败"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"short_url": rs.Url, "msg": "已下架"})
+}
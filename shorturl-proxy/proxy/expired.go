@@ -0,0 +1,35 @@
+package proxy
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// expiredPage 短链已过期或已达到访问次数上限时展示的页面
+const expiredPage = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>链接已失效</title>
+<style>
+body{margin:0;font-family:-apple-system,"PingFang SC","Microsoft YaHei",sans-serif;background:#f5f6f8;color:#333}
+.box{max-width:420px;margin:18vh auto 0;padding:40px 32px;background:#fff;border-radius:8px;text-align:center;box-shadow:0 2px 12px rgba(0,0,0,.06)}
+h1{margin:0 0 12px;font-size:22px}
+p{margin:0;color:#888;font-size:14px;line-height:1.6}
+</style>
+</head>
+<body>
+<div class="box">
+<h1>链接已失效</h1>
+<p>该短链接已过期或访问次数已达上限，请联系分享者获取新的链接。</p>
+</div>
+</body>
+</html>`
+
+// expired 返回链接已失效页面，使用 410 表示资源已永久失效
+func expired(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusGone, "text/html; charset=utf-8", []byte(expiredPage))
+}
@@ -8,6 +8,8 @@ import (
 	"enterprise-project1-mediahub/shorturl-proxy/services/shorturl"
 	"enterprise-project1-mediahub/shorturl-proxy/services/shorturl/proto"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"net/http"
 )
 
@@ -58,13 +60,17 @@ func (p *Proxy) UserProxy(ctx *gin.Context) {
 // 流程:
 // 1. 从URL路径提取短链接标识符
 // 2. 调用服务获取原始URL
-// 3. 处理错误并返回500状态码
+// 3. 短链已过期时展示失效页面，其他错误返回500状态码
 // 4. 成功时执行HTTP重定向
 func (p *Proxy) redirection(ctx *gin.Context, isPublic bool) {
 	shortKey := ctx.Param("short_key")
 	originalUrl, err := p.getOriginalUrl(shortKey, isPublic)
 
 	// 错误处理模块
+	if status.Code(err) == codes.FailedPrecondition {
+		expired(ctx)
+		return
+	}
 	if err != nil {
 		p.log.Error(err)
 		ctx.JSON(http.StatusInternalServerError, nil)
@@ -90,7 +96,10 @@ func (p *Proxy) redirection(ctx *gin.Context, isPublic bool) {
 func (p *Proxy) getOriginalUrl(shortKey string, isPublic bool) (string, error) {
 	// 客户端池管理模块
 	pool := shorturl.NewShortUrlClientPool()
-	conn := pool.Get()
+	conn, err := pool.Get()
+	if err != nil {
+		return "", err
+	}
 	defer pool.Put(conn)
 
 	// gRPC客户端初始化模块
@@ -3,39 +3,45 @@ package shorturl
 import (
 	"enterprise-project1-mediahub/shorturl-proxy/pkg/config"
 	"enterprise-project1-mediahub/shorturl-proxy/pkg/grpc_client_pool"
-	"enterprise-project1-mediahub/shorturl-proxy/pkg/log"
+	"enterprise-project1-mediahub/shorturl-proxy/pkg/tlsutil"
 	"enterprise-project1-mediahub/shorturl-proxy/pkg/zerror"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"sync"
 )
 
-// pool 用于存储 gRPC 客户端连接，以实现连接复用
+// pool 用于存储 gRPC 客户端连接，以实现连接复用，由 InitShortUrlClientPool 在服务启动时初始化
 var pool grpc_client_pool.ClientPool
 
-// once 确保在全局范围内只执行一次客户端连接池的初始化
-var once sync.Once
-
-// NewShortUrlClientPool 返回一个 gRPC 客户端连接池
-// 该函数确保在程序的任何地方只初始化一次连接池
-func NewShortUrlClientPool() grpc_client_pool.ClientPool {
-	var err error
-	// 如果连接池已经初始化，则直接返回
-	if pool != nil {
-		return pool
+// InitShortUrlClientPool 按配置初始化 gRPC 客户端连接池
+// 证书、私钥或 CA 文件无效时返回错误，调用方应拒绝启动，避免之后每个请求都拿到空连接池
+func InitShortUrlClientPool(cnf *config.Config) error {
+	creds, err := transportCredentials(cnf)
+	if err != nil {
+		return zerror.NewByErr(err)
+	}
+	p, err := grpc_client_pool.NewPool(cnf.DependOn.ShortUrl.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return zerror.NewByErr(err)
 	}
+	pool = p
+	return nil
+}
 
-	// 使用 sync.Once 确保连接池只被初始化一次
-	once.Do(func() {
-		// 获取配置信息
-		cnf := config.GetConfig()
-		// 初始化 gRPC 客户端连接池
-		pool, err = grpc_client_pool.NewPool(cnf.DependOn.ShortUrl.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		// 如果初始化过程中出现错误，记录错误日志
-		if err != nil {
-			log.Error(zerror.NewByErr(err))
-		}
-	})
-	// 返回初始化后的 gRPC 客户端连接池
+// NewShortUrlClientPool 返回服务启动时初始化的 gRPC 客户端连接池
+func NewShortUrlClientPool() grpc_client_pool.ClientPool {
 	return pool
 }
+
+// transportCredentials 按配置返回 TLS 或明文传输凭证，证书文件更新后在新建连接时自动生效
+func transportCredentials(cnf *config.Config) (credentials.TransportCredentials, error) {
+	tc := cnf.DependOn.ShortUrl.TLS
+	if !tc.Enable {
+		return insecure.NewCredentials(), nil
+	}
+	reloader, err := tlsutil.NewReloader(tc.CertFile, tc.KeyFile, tc.CaFile)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsutil.ClientConfig(reloader, tc.ServerName)), nil
+}
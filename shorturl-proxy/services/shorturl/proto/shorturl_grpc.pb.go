@@ -19,8 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ShortUrl_GetShortUrl_FullMethodName    = "/shorturl.chenaws.com.ShortUrl/GetShortUrl"
-	ShortUrl_GetOriginalUrl_FullMethodName = "/shorturl.chenaws.com.ShortUrl/GetOriginalUrl"
+	ShortUrl_GetShortUrl_FullMethodName         = "/shorturl.chenaws.com.ShortUrl/GetShortUrl"
+	ShortUrl_GetOriginalUrl_FullMethodName      = "/shorturl.chenaws.com.ShortUrl/GetOriginalUrl"
+	ShortUrl_BatchGetShortUrl_FullMethodName    = "/shorturl.chenaws.com.ShortUrl/BatchGetShortUrl"
+	ShortUrl_BatchGetOriginalUrl_FullMethodName = "/shorturl.chenaws.com.ShortUrl/BatchGetOriginalUrl"
+	ShortUrl_UpdateTarget_FullMethodName        = "/shorturl.chenaws.com.ShortUrl/UpdateTarget"
+	ShortUrl_DisableShortUrl_FullMethodName     = "/shorturl.chenaws.com.ShortUrl/DisableShortUrl"
+	ShortUrl_EnableShortUrl_FullMethodName      = "/shorturl.chenaws.com.ShortUrl/EnableShortUrl"
+	ShortUrl_DeleteShortUrl_FullMethodName      = "/shorturl.chenaws.com.ShortUrl/DeleteShortUrl"
+	ShortUrl_ListShortUrls_FullMethodName       = "/shorturl.chenaws.com.ShortUrl/ListShortUrls"
+	ShortUrl_GetShortUrlInfo_FullMethodName     = "/shorturl.chenaws.com.ShortUrl/GetShortUrlInfo"
+	ShortUrl_PurgeShortUrl_FullMethodName       = "/shorturl.chenaws.com.ShortUrl/PurgeShortUrl"
+	ShortUrl_GetUserPermissions_FullMethodName  = "/shorturl.chenaws.com.ShortUrl/GetUserPermissions"
 )
 
 // ShortUrlClient is the client API for ShortUrl service.
@@ -28,7 +38,28 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ShortUrlClient interface {
 	GetShortUrl(ctx context.Context, in *Url, opts ...grpc.CallOption) (*Url, error)
+	// 短链已过期或已达到访问次数上限时返回 FAILED_PRECONDITION
 	GetOriginalUrl(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*Url, error)
+	// 批量生成短链，单项失败不影响其他项，每批最多500项
+	BatchGetShortUrl(ctx context.Context, in *BatchUrlRequest, opts ...grpc.CallOption) (*BatchUrlResults, error)
+	// 批量解析短链，单项失败不影响其他项，每批最多500项
+	BatchGetOriginalUrl(ctx context.Context, in *BatchShortKeyRequest, opts ...grpc.CallOption) (*BatchUrlResults, error)
+	// 修改短链指向的原始URL，短链键保持不变
+	UpdateTarget(ctx context.Context, in *UpdateTargetRequest, opts ...grpc.CallOption) (*Url, error)
+	// 属主停用用户短链，停用后访问返回短链不存在
+	DisableShortUrl(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*Url, error)
+	// 属主重新启用已停用的用户短链，管理员下架的短链不能启用
+	EnableShortUrl(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*Url, error)
+	// 属主删除用户短链，删除后不能恢复
+	DeleteShortUrl(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*Url, error)
+	// 分页查询用户的短链
+	ListShortUrls(ctx context.Context, in *ListShortUrlsRequest, opts ...grpc.CallOption) (*ShortUrlList, error)
+	// 查询短链详情，用户短链只有属主可以查询
+	GetShortUrlInfo(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*ShortUrlInfo, error)
+	// 下架短链（管理操作），操作用户通过元数据 x-user-id 传递，需要拥有 shorturl:purge 权限
+	PurgeShortUrl(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*Url, error)
+	// 查询用户的管理权限，供没有数据库访问的服务鉴权
+	GetUserPermissions(ctx context.Context, in *PermissionRequest, opts ...grpc.CallOption) (*Permissions, error)
 }
 
 type shortUrlClient struct {
@@ -59,12 +90,133 @@ func (c *shortUrlClient) GetOriginalUrl(ctx context.Context, in *ShortKey, opts
 	return out, nil
 }
 
+func (c *shortUrlClient) BatchGetShortUrl(ctx context.Context, in *BatchUrlRequest, opts ...grpc.CallOption) (*BatchUrlResults, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchUrlResults)
+	err := c.cc.Invoke(ctx, ShortUrl_BatchGetShortUrl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) BatchGetOriginalUrl(ctx context.Context, in *BatchShortKeyRequest, opts ...grpc.CallOption) (*BatchUrlResults, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchUrlResults)
+	err := c.cc.Invoke(ctx, ShortUrl_BatchGetOriginalUrl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) UpdateTarget(ctx context.Context, in *UpdateTargetRequest, opts ...grpc.CallOption) (*Url, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Url)
+	err := c.cc.Invoke(ctx, ShortUrl_UpdateTarget_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) DisableShortUrl(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*Url, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Url)
+	err := c.cc.Invoke(ctx, ShortUrl_DisableShortUrl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) EnableShortUrl(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*Url, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Url)
+	err := c.cc.Invoke(ctx, ShortUrl_EnableShortUrl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) DeleteShortUrl(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*Url, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Url)
+	err := c.cc.Invoke(ctx, ShortUrl_DeleteShortUrl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) ListShortUrls(ctx context.Context, in *ListShortUrlsRequest, opts ...grpc.CallOption) (*ShortUrlList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShortUrlList)
+	err := c.cc.Invoke(ctx, ShortUrl_ListShortUrls_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) GetShortUrlInfo(ctx context.Context, in *ShortKey, opts ...grpc.CallOption) (*ShortUrlInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShortUrlInfo)
+	err := c.cc.Invoke(ctx, ShortUrl_GetShortUrlInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) PurgeShortUrl(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*Url, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Url)
+	err := c.cc.Invoke(ctx, ShortUrl_PurgeShortUrl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortUrlClient) GetUserPermissions(ctx context.Context, in *PermissionRequest, opts ...grpc.CallOption) (*Permissions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Permissions)
+	err := c.cc.Invoke(ctx, ShortUrl_GetUserPermissions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortUrlServer is the server API for ShortUrl service.
 // All implementations must embed UnimplementedShortUrlServer
 // for forward compatibility.
 type ShortUrlServer interface {
 	GetShortUrl(context.Context, *Url) (*Url, error)
+	// 短链已过期或已达到访问次数上限时返回 FAILED_PRECONDITION
 	GetOriginalUrl(context.Context, *ShortKey) (*Url, error)
+	// 批量生成短链，单项失败不影响其他项，每批最多500项
+	BatchGetShortUrl(context.Context, *BatchUrlRequest) (*BatchUrlResults, error)
+	// 批量解析短链，单项失败不影响其他项，每批最多500项
+	BatchGetOriginalUrl(context.Context, *BatchShortKeyRequest) (*BatchUrlResults, error)
+	// 修改短链指向的原始URL，短链键保持不变
+	UpdateTarget(context.Context, *UpdateTargetRequest) (*Url, error)
+	// 属主停用用户短链，停用后访问返回短链不存在
+	DisableShortUrl(context.Context, *ShortKey) (*Url, error)
+	// 属主重新启用已停用的用户短链，管理员下架的短链不能启用
+	EnableShortUrl(context.Context, *ShortKey) (*Url, error)
+	// 属主删除用户短链，删除后不能恢复
+	DeleteShortUrl(context.Context, *ShortKey) (*Url, error)
+	// 分页查询用户的短链
+	ListShortUrls(context.Context, *ListShortUrlsRequest) (*ShortUrlList, error)
+	// 查询短链详情，用户短链只有属主可以查询
+	GetShortUrlInfo(context.Context, *ShortKey) (*ShortUrlInfo, error)
+	// 下架短链（管理操作），操作用户通过元数据 x-user-id 传递，需要拥有 shorturl:purge 权限
+	PurgeShortUrl(context.Context, *PurgeRequest) (*Url, error)
+	// 查询用户的管理权限，供没有数据库访问的服务鉴权
+	GetUserPermissions(context.Context, *PermissionRequest) (*Permissions, error)
 	mustEmbedUnimplementedShortUrlServer()
 }
 
@@ -81,6 +233,36 @@ func (UnimplementedShortUrlServer) GetShortUrl(context.Context, *Url) (*Url, err
 func (UnimplementedShortUrlServer) GetOriginalUrl(context.Context, *ShortKey) (*Url, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOriginalUrl not implemented")
 }
+func (UnimplementedShortUrlServer) BatchGetShortUrl(context.Context, *BatchUrlRequest) (*BatchUrlResults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetShortUrl not implemented")
+}
+func (UnimplementedShortUrlServer) BatchGetOriginalUrl(context.Context, *BatchShortKeyRequest) (*BatchUrlResults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetOriginalUrl not implemented")
+}
+func (UnimplementedShortUrlServer) UpdateTarget(context.Context, *UpdateTargetRequest) (*Url, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTarget not implemented")
+}
+func (UnimplementedShortUrlServer) DisableShortUrl(context.Context, *ShortKey) (*Url, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableShortUrl not implemented")
+}
+func (UnimplementedShortUrlServer) EnableShortUrl(context.Context, *ShortKey) (*Url, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableShortUrl not implemented")
+}
+func (UnimplementedShortUrlServer) DeleteShortUrl(context.Context, *ShortKey) (*Url, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteShortUrl not implemented")
+}
+func (UnimplementedShortUrlServer) ListShortUrls(context.Context, *ListShortUrlsRequest) (*ShortUrlList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShortUrls not implemented")
+}
+func (UnimplementedShortUrlServer) GetShortUrlInfo(context.Context, *ShortKey) (*ShortUrlInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShortUrlInfo not implemented")
+}
+func (UnimplementedShortUrlServer) PurgeShortUrl(context.Context, *PurgeRequest) (*Url, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeShortUrl not implemented")
+}
+func (UnimplementedShortUrlServer) GetUserPermissions(context.Context, *PermissionRequest) (*Permissions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserPermissions not implemented")
+}
 func (UnimplementedShortUrlServer) mustEmbedUnimplementedShortUrlServer() {}
 func (UnimplementedShortUrlServer) testEmbeddedByValue()                  {}
 
@@ -138,6 +320,186 @@ func _ShortUrl_GetOriginalUrl_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortUrl_BatchGetShortUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUrlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).BatchGetShortUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_BatchGetShortUrl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).BatchGetShortUrl(ctx, req.(*BatchUrlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_BatchGetOriginalUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchShortKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).BatchGetOriginalUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_BatchGetOriginalUrl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).BatchGetOriginalUrl(ctx, req.(*BatchShortKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_UpdateTarget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTargetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).UpdateTarget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_UpdateTarget_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).UpdateTarget(ctx, req.(*UpdateTargetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_DisableShortUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).DisableShortUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_DisableShortUrl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).DisableShortUrl(ctx, req.(*ShortKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_EnableShortUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).EnableShortUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_EnableShortUrl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).EnableShortUrl(ctx, req.(*ShortKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_DeleteShortUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).DeleteShortUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_DeleteShortUrl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).DeleteShortUrl(ctx, req.(*ShortKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_ListShortUrls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListShortUrlsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).ListShortUrls(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_ListShortUrls_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).ListShortUrls(ctx, req.(*ListShortUrlsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_GetShortUrlInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).GetShortUrlInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_GetShortUrlInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).GetShortUrlInfo(ctx, req.(*ShortKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_PurgeShortUrl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).PurgeShortUrl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_PurgeShortUrl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).PurgeShortUrl(ctx, req.(*PurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortUrl_GetUserPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortUrlServer).GetUserPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortUrl_GetUserPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortUrlServer).GetUserPermissions(ctx, req.(*PermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShortUrl_ServiceDesc is the grpc.ServiceDesc for ShortUrl service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +515,46 @@ var ShortUrl_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOriginalUrl",
 			Handler:    _ShortUrl_GetOriginalUrl_Handler,
 		},
+		{
+			MethodName: "BatchGetShortUrl",
+			Handler:    _ShortUrl_BatchGetShortUrl_Handler,
+		},
+		{
+			MethodName: "BatchGetOriginalUrl",
+			Handler:    _ShortUrl_BatchGetOriginalUrl_Handler,
+		},
+		{
+			MethodName: "UpdateTarget",
+			Handler:    _ShortUrl_UpdateTarget_Handler,
+		},
+		{
+			MethodName: "DisableShortUrl",
+			Handler:    _ShortUrl_DisableShortUrl_Handler,
+		},
+		{
+			MethodName: "EnableShortUrl",
+			Handler:    _ShortUrl_EnableShortUrl_Handler,
+		},
+		{
+			MethodName: "DeleteShortUrl",
+			Handler:    _ShortUrl_DeleteShortUrl_Handler,
+		},
+		{
+			MethodName: "ListShortUrls",
+			Handler:    _ShortUrl_ListShortUrls_Handler,
+		},
+		{
+			MethodName: "GetShortUrlInfo",
+			Handler:    _ShortUrl_GetShortUrlInfo_Handler,
+		},
+		{
+			MethodName: "PurgeShortUrl",
+			Handler:    _ShortUrl_PurgeShortUrl_Handler,
+		},
+		{
+			MethodName: "GetUserPermissions",
+			Handler:    _ShortUrl_GetUserPermissions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shorturl/proto/shorturl.proto",
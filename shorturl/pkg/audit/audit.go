@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 操作
+const (
+	ActionAuthFailure   = "auth.failure"   // 凭证无效、过期或 CSRF 校验失败
+	ActionAccessDenied  = "access.denied"  // 已认证但没有权限
+	ActionMediaUpload   = "media.upload"   // 上传媒体
+	ActionMediaReplace  = "media.replace"  // 替换媒体内容
+	ActionMediaDelete   = "media.delete"   // 删除媒体（移入回收站）
+	ActionMediaRestore  = "media.restore"  // 从回收站恢复媒体
+	ActionTrashEmpty    = "trash.empty"    // 清空回收站
+	ActionLinkCreate    = "link.create"    // 创建短链
+	ActionLinkUpdate    = "link.update"    // 修改短链指向
+	ActionLinkPurge     = "link.purge"     // 下架短链
+	ActionLinkDisable   = "link.disable"   // 属主停用短链
+	ActionLinkEnable    = "link.enable"    // 属主重新启用短链
+	ActionLinkDelete    = "link.delete"    // 属主删除短链
+	ActionApiKeyCreate  = "apikey.create"  // 创建 API Key
+	ActionApiKeyRevoke  = "apikey.revoke"  // 撤销 API Key
+	ActionRoleSave      = "role.save"      // 新增或修改角色
+	ActionRoleDelete    = "role.delete"    // 删除角色
+	ActionRoleAssign    = "role.assign"    // 授予用户角色
+	ActionRoleUnassign  = "role.unassign"  // 撤销用户角色
+	ActionSessionCreate = "session.create" // 令牌换取会话
+	ActionSessionRevoke = "session.revoke" // 退出登录或撤销会话
+	ActionAuditExport   = "audit.export"   // 导出审计记录
+)
+
+// 操作结果
+const (
+	OutcomeSuccess = "success" // 成功
+	OutcomeFailure = "failure" // 认证失败或处理失败
+	OutcomeDenied  = "denied"  // 没有权限
+)
+
+// 操作者类型
+const (
+	ActorAnonymous = "anonymous" // 匿名
+	ActorUser      = "user"      // 登录用户
+	ActorApiKey    = "api_key"   // 用户的 API Key
+	ActorClient    = "client"    // 内部服务调用方
+)
+
+// 字段长度上限，与表结构一致，超出部分截断
+const (
+	maxUserAgent = 255
+	maxDetail    = 1024
+	maxTargetID  = 128
+)
+
+// Record 审计记录
+type Record struct {
+	ID         int64  `json:"id"`          // 主键ID
+	Service    string `json:"service"`     // 记录来源服务
+	ActorType  string `json:"actor_type"`  // 操作者类型，见 Actor* 常量
+	ActorID    int64  `json:"actor_id"`    // 操作用户ID，没有用户时为0
+	ActorName  string `json:"actor_name"`  // 操作者名称
+	Client     string `json:"client"`      // 经由的内部调用方，直接访问时为空
+	Action     string `json:"action"`      // 操作，见 Action* 常量
+	TargetType string `json:"target_type"` // 操作对象类型，如 media、url_map_user、role
+	TargetID   string `json:"target_id"`   // 操作对象ID
+	IP         string `json:"ip"`          // 客户端IP
+	UserAgent  string `json:"user_agent"`  // 客户端 User-Agent
+	Outcome    string `json:"outcome"`     // 结果，见 Outcome* 常量
+	Detail     string `json:"detail"`      // 补充说明
+	CreateAt   int64  `json:"create_at"`   // 记录时间戳
+}
+
+// Target 设置操作对象
+func (r *Record) Target(targetType, targetID string) *Record {
+	r.TargetType = targetType
+	r.TargetID = targetID
+	return r
+}
+
+// WithDetail 设置补充说明
+func (r *Record) WithDetail(detail string) *Record {
+	r.Detail = detail
+	return r
+}
+
+// Query 审计记录的查询条件，零值字段不参与过滤
+// 结果按ID倒序，BeforeID 用于翻页，传入上一页最后一条记录的ID
+type Query struct {
+	ActorType  string
+	ActorID    int64
+	TargetType string
+	TargetID   string
+	Action     string
+	From       int64 // 起始时间戳（含）
+	To         int64 // 截止时间戳（不含）
+	BeforeID   int64
+	Limit      int
+}
+
+// Writer 审计记录的存储，只追加不修改
+type Writer interface {
+	// Append 批量写入审计记录
+	Append(records []*Record) error
+}
+
+const (
+	bufferSize    = 1024        // 待写入记录的缓冲区大小
+	batchSize     = 100         // 单次批量写入的记录数上限
+	flushInterval = time.Second // 未攒满一批时的最长等待时间
+)
+
+// Recorder 异步批量写入审计记录，写入不阻塞业务请求
+// 缓冲区满或已关闭时改为同步写入，审计记录不会因为积压被丢弃
+type Recorder struct {
+	service string
+	writer  Writer
+	onError func(error)
+
+	ch     chan *Record
+	done   chan struct{}
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewRecorder 创建审计记录器并启动后台写入，onError 用于记录写入失败
+func NewRecorder(service string, writer Writer, onError func(error)) *Recorder {
+	r := &Recorder{
+		service: service,
+		writer:  writer,
+		onError: onError,
+		ch:      make(chan *Record, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record 写入审计记录，自动填充来源服务与记录时间，r 为nil时不做任何事
+func (r *Recorder) Record(rec *Record) {
+	if r == nil || rec == nil {
+		return
+	}
+	rec.Service = r.service
+	if rec.CreateAt == 0 {
+		rec.CreateAt = time.Now().Unix()
+	}
+	rec.UserAgent = truncate(rec.UserAgent, maxUserAgent)
+	rec.Detail = truncate(rec.Detail, maxDetail)
+	rec.TargetID = truncate(rec.TargetID, maxTargetID)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.closed {
+		select {
+		case r.ch <- rec:
+			return
+		default:
+		}
+	}
+	r.write([]*Record{rec})
+}
+
+// Close 停止后台写入并写入缓冲区中剩余的记录
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.ch)
+	r.mu.Unlock()
+	<-r.done
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Record, 0, batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			r.write(batch)
+			batch = make([]*Record, 0, batchSize)
+		}
+	}
+	for {
+		select {
+		case rec, ok := <-r.ch:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, rec)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (r *Recorder) write(records []*Record) {
+	if err := r.writer.Append(records); err != nil && r.onError != nil {
+		r.onError(err)
+	}
+}
+
+// truncate 按字节截断字符串，不保留被截断的半个多字节字符
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 	"log"
+	"sync"
+	"sync/atomic"
 )
 
 // Config 结构体定义了应用程序的配置项，包括HTTP、MySQL、Redis和日志相关的配置。
@@ -10,9 +13,19 @@ type Config struct {
 	Server struct {
 		IP          string
 		Port        int
-		AccessToken string
+		AccessToken string // 兼容旧配置的共享令牌，拥有全部权限
+		TLS         struct {
+			Enable     bool
+			CertFile   string `mapstructure:"certFile"`
+			KeyFile    string `mapstructure:"keyFile"`
+			CaFile     string `mapstructure:"caFile"`     // 校验客户端证书的 CA
+			ClientAuth string `mapstructure:"clientAuth"` // 客户端证书校验方式：空（不校验）、optional、require
+		} `mapstructure:"tls"`
 	}
-	Mysql struct {
+	// Clients 调用方列表，每个调用方使用各自的令牌或 JWT 密钥，并按 Scopes 限制可调用的接口
+	// 修改后无需重启服务即可生效，轮换令牌时可同时配置新旧两个令牌
+	Clients []Client
+	Mysql   struct {
 		DSN         string
 		MaxLifeTime int
 		MaxOpenConn int
@@ -29,9 +42,48 @@ type Config struct {
 	} `mapstructure:"log"`
 	ShortDomain     string
 	UserShortDomain string
+	// Alias 自定义别名规则，在内置的保留字与屏蔽词列表基础上追加
+	Alias struct {
+		Reserved []string // 保留字
+		Blocked  []string // 屏蔽词
+	}
+	// ShortKey 短链键规则，Secret 为空时短链键为 Base62(ID)，可被按顺序枚举
+	// 启用时将 LegacyLength 设为当前最大ID的 Base62 长度，已签发的短链键继续按 Base62(ID) 解析，
+	// 新短链键的长度至少为 LegacyLength+1；没有旧短链键时设为 -1，已有记录时为0无法启动
+	ShortKey struct {
+		Secret       string // Feistel 置换密钥，启用后不能修改
+		MinLength    int    `mapstructure:"minLength"`    // 新短链键的最小长度，默认6
+		LegacyLength int    `mapstructure:"legacyLength"` // 启用前签发的短链键的最大长度，-1 表示没有旧短链键
+	} `mapstructure:"shortKey"`
+	// IDGenerator 短链记录ID的生成方式
+	IDGenerator struct {
+		Type     string // segment（默认，号段预留自 MySQL）、redis（号段预留自 Redis）、snowflake
+		Step     int64  // 号段长度，默认1000
+		WorkerID int64  `mapstructure:"workerID"` // snowflake 的机器ID，0~1023，各实例不能重复
+	} `mapstructure:"idGenerator"`
+	// UrlNormalize 原始URL的规范化规则，规范化后的URL用于校验、去重与存储
+	UrlNormalize struct {
+		StripTracking  bool     `mapstructure:"stripTracking"`  // 去掉 utm_* 等跟踪参数
+		TrackingParams []string `mapstructure:"trackingParams"` // 追加的跟踪参数，以 * 结尾时按前缀匹配
+		StripFragment  bool     `mapstructure:"stripFragment"`  // 去掉 # 之后的片段，单页应用的路由依赖片段时不要开启
+	} `mapstructure:"urlNormalize"`
+}
+
+// Client 调用方配置
+type Client struct {
+	Name      string   // 调用方名称，记录在日志中
+	Tokens    []string // 静态令牌，可配置多个用于轮换
+	JwtSecret string   `mapstructure:"jwtSecret"` // HS256 签名密钥，JWT 的 sub 为调用方名称
+	Scopes    []string // 权限范围，如 url:create、url:resolve，* 表示全部
+	CertNames []string `mapstructure:"certNames"` // 启用 mTLS 时，客户端证书 CN 或 DNS SAN 在此列表中即认证为该调用方
 }
 
-var conf *Config
+var (
+	conf      atomic.Pointer[Config]
+	v         *viper.Viper
+	mu        sync.Mutex
+	listeners []func(*Config)
+)
 
 // InitConfig 初始化应用程序的配置。
 // 该函数通过读取指定路径的配置文件，并将其解析为Config结构体。
@@ -42,7 +94,7 @@ var conf *Config
 // 返回值：无
 // 如果配置文件读取或解析失败，函数将记录错误并终止程序。
 func InitConfig(filePath string, typ ...string) {
-	v := viper.New()
+	v = viper.New()
 	v.SetConfigFile(filePath)
 	if len(typ) > 0 {
 		v.SetConfigType(typ[0])
@@ -51,16 +103,42 @@ func InitConfig(filePath string, typ ...string) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	conf = &Config{}
-	err = v.Unmarshal(conf)
+	c := &Config{}
+	err = v.Unmarshal(c)
 	if err != nil {
 		log.Fatal(err)
 	}
+	conf.Store(c)
+}
+
+// OnChange 注册配置变更回调，首次注册时开始监听配置文件
+// 配置文件修改后重新解析，解析失败时保留原配置
+func OnChange(fn func(*Config)) {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(listeners) == 0 {
+		v.OnConfigChange(func(e fsnotify.Event) {
+			c := &Config{}
+			if err := v.Unmarshal(c); err != nil {
+				log.Println(err)
+				return
+			}
+			conf.Store(c)
+			mu.Lock()
+			fns := listeners
+			mu.Unlock()
+			for _, fn := range fns {
+				fn(c)
+			}
+		})
+		v.WatchConfig()
+	}
+	listeners = append(listeners, fn)
 }
 
 // GetConfig 返回当前应用程序的配置。
 // 返回值：
 //   - *Config: 当前应用程序的配置结构体指针。
 func GetConfig() *Config {
-	return conf
+	return conf.Load()
 }
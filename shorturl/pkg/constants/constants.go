@@ -2,3 +2,23 @@ package constants
 
 const TABLENAME_URL_MAP = "url_map"
 const TABLENAME_URL_MAP_USER = "url_map_user"
+const TABLENAME_AUDIT_LOG = "audit_log"
+const TABLENAME_ID_SEQUENCE = "id_sequence"
+
+// 角色权限相关表，由 mediahub 管理
+const (
+	TABLENAME_RBAC_ROLE_PERMISSION = "rbac_role_permission"
+	TABLENAME_RBAC_USER_ROLE       = "rbac_user_role"
+)
+
+// 短链状态
+const (
+	URL_STATUS_NORMAL   = 0 // 正常
+	URL_STATUS_DISABLED = 1 // 已禁用（管理员下架或媒体清除），访问时返回短链不存在，属主不能重新启用
+	URL_STATUS_PAUSED   = 2 // 属主停用，访问时返回短链不存在，属主可以重新启用
+	URL_STATUS_DELETED  = 3 // 属主删除，不能恢复，自定义别名释放后可被重新使用
+)
+
+// CACHE_INVALIDATE_CHANNEL 本地缓存失效通知的Redis频道（不含服务前缀）
+// 消息内容为需要失效的缓存键，各实例收到后删除自己的本地缓存
+const CACHE_INVALIDATE_CHANNEL = "cache_invalidate"
@@ -0,0 +1,107 @@
+package rbac
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// 权限标识，格式为 资源:操作，* 表示全部权限，资源:* 表示该资源的全部操作
+const (
+	PermAll           = "*"
+	PermRbacManage    = "rbac:manage"    // 管理角色与授权
+	PermStatsView     = "stats:view"     // 查看全局统计
+	PermShortUrlPurge = "shorturl:purge" // 下架任意短链
+	PermAuditView     = "audit:view"     // 查询与导出审计记录
+)
+
+const (
+	defaultTTL   = time.Minute // 用户权限的默认缓存时长
+	maxCacheSize = 10000       // 缓存的用户数上限，超过后清空重新缓存
+)
+
+// Store 用户权限的存储
+type Store interface {
+	// UserPermissions 查询用户通过角色获得的全部权限
+	UserPermissions(userID int64) ([]string, error)
+}
+
+// Match 判断已授予的权限是否包含 perm
+func Match(granted []string, perm string) bool {
+	resource, _, _ := strings.Cut(perm, ":")
+	for _, g := range granted {
+		if g == PermAll || g == perm || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+type cacheEntry struct {
+	perms  []string
+	expire time.Time
+}
+
+// Enforcer 校验用户权限，用户权限按 TTL 缓存在内存中
+// 授权变更后在本实例上调用 Invalidate 立即生效，其他实例在缓存过期后生效
+type Enforcer struct {
+	store Store
+	ttl   time.Duration
+
+	mu    sync.RWMutex
+	cache map[int64]cacheEntry
+}
+
+// NewEnforcer 创建权限校验器，ttl 不大于0时使用默认值
+func NewEnforcer(store Store, ttl time.Duration) *Enforcer {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Enforcer{store: store, ttl: ttl, cache: map[int64]cacheEntry{}}
+}
+
+// Permissions 返回用户的全部权限
+func (e *Enforcer) Permissions(userID int64) ([]string, error) {
+	now := time.Now()
+	e.mu.RLock()
+	entry, ok := e.cache[userID]
+	e.mu.RUnlock()
+	if ok && now.Before(entry.expire) {
+		return entry.perms, nil
+	}
+
+	perms, err := e.store.UserPermissions(userID)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	if len(e.cache) >= maxCacheSize {
+		e.cache = map[int64]cacheEntry{}
+	}
+	e.cache[userID] = cacheEntry{perms: perms, expire: now.Add(e.ttl)}
+	e.mu.Unlock()
+	return perms, nil
+}
+
+// Allow 判断用户是否拥有权限，匿名用户没有任何权限
+func (e *Enforcer) Allow(userID int64, perm string) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	perms, err := e.Permissions(userID)
+	if err != nil {
+		return false, err
+	}
+	return Match(perms, perm), nil
+}
+
+// Invalidate 删除用户的权限缓存，userID 为0时清空全部缓存
+func (e *Enforcer) Invalidate(userID int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if userID == 0 {
+		e.cache = map[int64]cacheEntry{}
+		return
+	}
+	delete(e.cache, userID)
+}
@@ -0,0 +1,198 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkInterval 两次检查证书文件是否变化的最小间隔
+const checkInterval = 10 * time.Second
+
+// 客户端证书校验方式
+const (
+	ClientAuthNone     = ""         // 不要求客户端证书，只做单向 TLS
+	ClientAuthOptional = "optional" // 客户端提供证书时校验，未提供时仍可使用令牌认证
+	ClientAuthRequire  = "require"  // 必须提供有效的客户端证书（mTLS）
+)
+
+// Reloader 从文件加载证书、私钥与 CA，文件更新后自动重新加载，轮换证书无需重启
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+	modTimes  [3]time.Time
+	lastCheck time.Time
+}
+
+// NewReloader 创建证书加载器并立即加载一次
+// 参数：
+//   - certFile、keyFile: PEM 格式的证书与私钥，客户端不使用证书时可以为空
+//   - caFile: 用于校验对端证书的 CA，为空时服务端不校验客户端证书，客户端使用系统根证书
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload 重新加载证书文件，加载失败时保留原证书
+func (r *Reloader) Reload() error {
+	var cert *tls.Certificate
+	if r.certFile != "" || r.keyFile != "" {
+		c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return err
+		}
+		cert = &c
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		bs, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bs) {
+			return errors.New("tlsutil: no certificate found in ca file")
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = cert
+	r.pool = pool
+	r.modTimes = r.fileModTimes()
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Reloader) fileModTimes() [3]time.Time {
+	var ts [3]time.Time
+	for i, f := range []string{r.certFile, r.keyFile, r.caFile} {
+		if f == "" {
+			continue
+		}
+		if fi, err := os.Stat(f); err == nil {
+			ts[i] = fi.ModTime()
+		}
+	}
+	return ts
+}
+
+// maybeReload 在握手时按间隔检查文件修改时间，有变化时重新加载
+func (r *Reloader) maybeReload() {
+	r.mu.RLock()
+	due := time.Since(r.lastCheck) >= checkInterval
+	r.mu.RUnlock()
+	if !due {
+		return
+	}
+	ts := r.fileModTimes()
+	r.mu.Lock()
+	changed := ts != r.modTimes
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+	if changed {
+		// 证书与私钥可能没有同时写完，加载失败时下次检查再试
+		if err := r.Reload(); err != nil {
+			r.mu.Lock()
+			r.modTimes = [3]time.Time{}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Certificate 当前使用的证书
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.maybeReload()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// CertPool 当前使用的 CA
+func (r *Reloader) CertPool() *x509.CertPool {
+	r.maybeReload()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+// ServerConfig 服务端 TLS 配置，每次握手使用最新的证书与 CA
+// clientAuth 取值见 ClientAuthNone、ClientAuthOptional、ClientAuthRequire
+func ServerConfig(r *Reloader, clientAuth string) (*tls.Config, error) {
+	var auth tls.ClientAuthType
+	switch clientAuth {
+	case ClientAuthNone:
+		auth = tls.NoClientCert
+	case ClientAuthOptional:
+		auth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		auth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, errors.New("tlsutil: invalid client auth " + clientAuth)
+	}
+	if auth != tls.NoClientCert && r.caFile == "" {
+		return nil, errors.New("tlsutil: ca file is required to verify client certificates")
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert := r.Certificate()
+			if cert == nil {
+				return nil, errors.New("tlsutil: server certificate is not configured")
+			}
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    r.CertPool(),
+				ClientAuth:   auth,
+			}, nil
+		},
+	}, nil
+}
+
+// ClientConfig 客户端 TLS 配置，serverName 为空时使用连接地址中的主机名
+// 配置了 CA 时用最新的 CA 校验服务端证书，使 CA 轮换同样无需重启
+func ClientConfig(r *Reloader, serverName string) *tls.Config {
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.Certificate(); cert != nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+	if r.caFile == "" {
+		return c
+	}
+	// tls.Config 的 RootCAs 不能动态替换，改为在 VerifyConnection 中自行校验证书链与主机名
+	c.InsecureSkipVerify = true
+	c.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tlsutil: no server certificate")
+		}
+		opts := x509.VerifyOptions{
+			Roots:         r.CertPool(),
+			DNSName:       cs.ServerName,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+	return c
+}
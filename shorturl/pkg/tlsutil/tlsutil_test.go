@@ -0,0 +1,199 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newCert 生成证书，parent 为nil时生成自签名 CA
+func newCert(t *testing.T, cn string, serial int64, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{cn},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tpl, key
+	if parent == nil {
+		tpl.IsCA = true
+		tpl.BasicConstraintsValid = true
+		tpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// write 写入证书与私钥文件，返回文件路径
+func (c *testCert) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+// serve 启动 TLS 服务，返回地址；每个连接完成握手后把客户端证书 CN 写回
+func serve(t *testing.T, cnf *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				tc := conn.(*tls.Conn)
+				if err := tc.Handshake(); err != nil {
+					return
+				}
+				cn := "-"
+				if certs := tc.ConnectionState().PeerCertificates; len(certs) > 0 {
+					cn = certs[0].Subject.CommonName
+				}
+				tc.Write([]byte(cn + "\n"))
+			}(conn)
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// dial 连接服务端，返回服务端证书序列号与服务端看到的客户端 CN
+func dial(addr string, cnf *tls.Config) (int64, string, error) {
+	conn, err := tls.Dial("tcp", addr, cnf)
+	if err != nil {
+		return 0, "", err
+	}
+	defer conn.Close()
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return 0, "", err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), string(buf[:n-1]), nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "test-ca", 1, nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCert, serverKey := newCert(t, "shorturl", 2, ca).write(t, dir, "server")
+	clientCert, clientKey := newCert(t, "proxy", 3, ca).write(t, dir, "client")
+
+	sr, err := NewReloader(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scnf, err := ServerConfig(sr, ClientAuthRequire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := serve(t, scnf)
+
+	cr, err := NewReloader(clientCert, clientKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, cn, err := dial(addr, ClientConfig(cr, "shorturl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial != 2 || cn != "proxy" {
+		t.Errorf("unexpected serial %d, client cn %s", serial, cn)
+	}
+
+	// 服务端要求客户端证书，未提供证书的客户端握手失败
+	anon, err := NewReloader("", "", caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = dial(addr, ClientConfig(anon, "shorturl")); err == nil {
+		t.Error("client without certificate accepted")
+	}
+	// 服务端证书与期望的主机名不一致
+	if _, _, err = dial(addr, ClientConfig(cr, "other")); err == nil {
+		t.Error("wrong server name accepted")
+	}
+
+	// 轮换服务端证书后新连接使用新证书
+	newCert(t, "shorturl", 4, ca).write(t, dir, "server")
+	if err = sr.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if serial, _, err = dial(addr, ClientConfig(cr, "shorturl")); err != nil || serial != 4 {
+		t.Errorf("after reload: serial %d, err %v", serial, err)
+	}
+
+	// 其他 CA 签发的客户端证书不能通过校验
+	otherCa := newCert(t, "other-ca", 5, nil)
+	otherCert, otherKey := newCert(t, "proxy", 6, otherCa).write(t, dir, "other")
+	or, err := NewReloader(otherCert, otherKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = dial(addr, ClientConfig(or, "shorturl")); err == nil {
+		t.Error("certificate from unknown ca accepted")
+	}
+}
+
+func TestServerConfigRequiresCa(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "test-ca", 1, nil)
+	certFile, keyFile := newCert(t, "shorturl", 2, ca).write(t, dir, "server")
+	r, err := NewReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ServerConfig(r, ClientAuthRequire); err == nil {
+		t.Error("mtls without ca accepted")
+	}
+	if _, err = ServerConfig(r, ClientAuthNone); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// 自定义别名的长度限制，上限与 short_key 字段长度一致
+const (
+	AliasMinLen = 4
+	AliasMaxLen = 45
+)
+
+// maxGeneratedKeyLen 生成短链键的最大长度，int64 的 Base62 编码最多11位
+const maxGeneratedKeyLen = 11
+
+var (
+	ErrAliasLength   = errors.New("别名长度需在4到45个字符之间")
+	ErrAliasCharset  = errors.New("别名只能包含小写字母、数字、- 和 _，且必须以字母或数字开头和结尾")
+	ErrAliasFormat   = errors.New("别名需包含 - 或 _，或长度不少于12个字符")
+	ErrAliasReserved = errors.New("别名为系统保留字")
+	ErrAliasBlocked  = errors.New("别名包含不允许使用的词语")
+)
+
+// defaultReservedAliases 系统保留字，别名整体或第一个词为保留字时不允许使用，
+// 避免冒充官方入口，如 admin-login、api_v2
+var defaultReservedAliases = []string{
+	"admin", "api", "app", "assets", "auth", "expired", "health", "healthz", "help",
+	"index", "login", "logout", "metrics", "not-found", "null", "official", "root",
+	"static", "status", "support", "system", "undefined", "www",
+}
+
+// defaultBlockedWords 不允许出现在别名中的词语，去掉 - 和 _ 后按子串匹配
+var defaultBlockedWords = []string{
+	"fuck", "shit", "bitch", "cunt", "dick", "porn", "sex", "nazi", "whore", "slut",
+}
+
+// IsAlias 判断短链键是否为自定义别名
+// 生成的短链键是不超过11位的 Base62 字符串，不包含 - 和 _；
+// 别名必须包含 - 或 _，或长度超过11位，因此两者不会冲突
+func IsAlias(key string) bool {
+	return len(key) > maxGeneratedKeyLen || strings.ContainsAny(key, "-_")
+}
+
+// AliasPolicy 自定义别名的校验规则，保留字与屏蔽词在内置列表基础上追加
+type AliasPolicy struct {
+	reserved map[string]struct{}
+	blocked  map[string]struct{}
+}
+
+// NewAliasPolicy 创建别名校验规则
+// 参数:
+//
+//	reserved: 追加的保留字
+//	blocked: 追加的屏蔽词
+func NewAliasPolicy(reserved, blocked []string) *AliasPolicy {
+	p := &AliasPolicy{
+		reserved: make(map[string]struct{}),
+		blocked:  make(map[string]struct{}),
+	}
+	for _, w := range append(defaultReservedAliases, reserved...) {
+		p.reserved[strings.ToLower(w)] = struct{}{}
+	}
+	for _, w := range append(defaultBlockedWords, blocked...) {
+		p.blocked[strings.ToLower(w)] = struct{}{}
+	}
+	return p
+}
+
+// Normalize 校验别名并返回规范形式（小写）
+// 参数:
+//
+//	alias: 用户提交的别名
+//
+// 返回值:
+//
+//	string: 规范化后的别名
+//	error: 不符合规则时返回对应的 ErrAlias* 错误
+func (p *AliasPolicy) Normalize(alias string) (string, error) {
+	alias = strings.ToLower(strings.TrimSpace(alias))
+	if len(alias) < AliasMinLen || len(alias) > AliasMaxLen {
+		return "", ErrAliasLength
+	}
+	for i := 0; i < len(alias); i++ {
+		c := alias[i]
+		alnum := (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+		if !alnum && c != '-' && c != '_' {
+			return "", ErrAliasCharset
+		}
+		if !alnum && (i == 0 || i == len(alias)-1) {
+			return "", ErrAliasCharset
+		}
+	}
+	words := strings.FieldsFunc(alias, func(r rune) bool { return r == '-' || r == '_' })
+	// 去掉分隔符后按子串匹配，屏蔽词与其他字母连写（如 xfuck）或被分隔符拆开时同样拒绝
+	joined := strings.Join(words, "")
+	for w := range p.blocked {
+		if strings.Contains(joined, w) {
+			return "", ErrAliasBlocked
+		}
+	}
+	if !IsAlias(alias) {
+		return "", ErrAliasFormat
+	}
+	for _, w := range []string{alias, words[0]} {
+		if _, ok := p.reserved[w]; ok {
+			return "", ErrAliasReserved
+		}
+	}
+	return alias, nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAliasPolicy(t *testing.T) {
+	p := NewAliasPolicy([]string{"promo"}, []string{"spam"})
+	cases := []struct {
+		alias string
+		want  string
+		err   error
+	}{
+		{"Spring-Sale", "spring-sale", nil},
+		{"summer_2024", "summer_2024", nil},
+		{"longcampaignname", "longcampaignname", nil},
+		{"abc", "", ErrAliasLength},
+		{"spring sale", "", ErrAliasCharset},
+		{"-spring", "", ErrAliasCharset},
+		{"spring-", "", ErrAliasCharset},
+		{"春季-sale", "", ErrAliasCharset},
+		{"spring", "", ErrAliasFormat},
+		{"admin-login", "", ErrAliasReserved},
+		{"not-found", "", ErrAliasReserved},
+		{"promo-2024", "", ErrAliasReserved},
+		{"big-porn-sale", "", ErrAliasBlocked},
+		{"sp-am", "", ErrAliasBlocked},
+		// 屏蔽词与其他字母连写时同样拒绝
+		{"fuckoff", "", ErrAliasBlocked},
+		{"xfuck", "", ErrAliasBlocked},
+		{"FuckYou", "", ErrAliasBlocked},
+		{"big-spamsale", "", ErrAliasBlocked},
+	}
+	for _, c := range cases {
+		got, err := p.Normalize(c.alias)
+		if err != c.err || got != c.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q, %v", c.alias, got, err, c.want, c.err)
+		}
+	}
+}
+
+func TestIsAlias(t *testing.T) {
+	// 生成的短链键不会被识别为别名
+	for i := 0; i < 1000; i++ {
+		if key := ToBase62(rand.Int63n(math.MaxInt64)); IsAlias(key) {
+			t.Fatalf("generated key %q treated as alias", key)
+		}
+	}
+	if IsAlias(ToBase62(math.MaxInt64)) {
+		t.Error("max generated key treated as alias")
+	}
+	if !IsAlias("spring-sale") || !IsAlias("longcampaignname") {
+		t.Error("alias not recognized")
+	}
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "crypto/sha256"
+
+// UrlHashLen 原始URL哈希的长度（字节）
+const UrlHashLen = 16
+
+// UrlHash 返回原始URL的 SHA-256 前16字节，用于短链去重
+func UrlHash(url string) []byte {
+	sum := sha256.Sum256([]byte(url))
+	return sum[:UrlHashLen]
+}
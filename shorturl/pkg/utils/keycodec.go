@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// DefaultKeyMinLen 启用 Feistel 置换后短链键的默认最小长度，6位可容纳约568亿条记录
+const DefaultKeyMinLen = 6
+
+// feistelRounds Feistel 置换的轮数，小定义域上需要更多轮数才能充分混淆
+const feistelRounds = 8
+
+// KeyCodec 短链键与记录ID之间的可逆转换
+type KeyCodec interface {
+	// Encode 将记录ID转换为短链键
+	Encode(id int64) string
+	// Decode 将短链键转换回记录ID，短链键非法时返回0
+	Decode(key string) int64
+}
+
+// base62Codec 直接使用 Base62(ID) 作为短链键，连续的ID对应连续的短链键
+type base62Codec struct{}
+
+// NewBase62Codec 创建 Base62(ID) 短链键转换器，与早期签发的短链键一致
+func NewBase62Codec() KeyCodec {
+	return base62Codec{}
+}
+
+func (base62Codec) Encode(id int64) string {
+	return ToBase62(id)
+}
+
+func (base62Codec) Decode(key string) int64 {
+	return ToBase10(key)
+}
+
+// pow62[n] 为 62 的 n 次方，n 不超过10；11位短链键的定义域为整个 uint64
+var pow62 = func() [maxGeneratedKeyLen]uint64 {
+	var p [maxGeneratedKeyLen]uint64
+	p[0] = 1
+	for i := 1; i < maxGeneratedKeyLen; i++ {
+		p[i] = p[i-1] * 62
+	}
+	return p
+}()
+
+// feistelCodec 使用带密钥的 Feistel 置换打乱ID后再进行 Base62 编码
+// 长度为 n 的短链键对应定义域 [0, 62^n)，在覆盖该定义域的偶数位宽上做平衡 Feistel 置换，
+// 结果超出定义域时继续置换（cycle walking），因此置换结果恰好是 n 位以内的 Base62 数，
+// 不足 n 位时左侧补齐；ID 小于 62^minLen 时使用 minLen 位，更大的ID按需加长
+type feistelCodec struct {
+	secret    []byte
+	minLen    int
+	legacyLen int
+}
+
+// NewKeyCodec 创建短链键转换器
+// secret 为空时沿用 Base62(ID)；否则使用 Feistel 置换，短链键不能从相邻的ID推算。
+// 切换前签发的短链键仍按 Base62(ID) 解析：长度不超过 legacyLen 的短链键视为旧短链键，
+// 新短链键的长度至少为 legacyLen+1，两者不会重复
+// 参数:
+//
+//	secret: 置换密钥，启用后不能修改，否则已签发的短链键无法解析
+//	minLen: 新短链键的最小长度，不大于0时使用 DefaultKeyMinLen
+//	legacyLen: 切换前签发的短链键的最大长度，不大于0表示没有旧短链键
+func NewKeyCodec(secret string, minLen, legacyLen int) KeyCodec {
+	if secret == "" {
+		return NewBase62Codec()
+	}
+	if legacyLen < 0 {
+		legacyLen = 0
+	}
+	if minLen <= 0 {
+		minLen = DefaultKeyMinLen
+	}
+	if minLen <= legacyLen {
+		minLen = legacyLen + 1
+	}
+	if minLen > maxGeneratedKeyLen {
+		minLen = maxGeneratedKeyLen
+	}
+	return &feistelCodec{
+		secret:    []byte(secret),
+		minLen:    minLen,
+		legacyLen: legacyLen,
+	}
+}
+
+// CheckLegacyLength 启动时检查启用 Feistel 置换的配置
+// 已有记录时 legacyLen 为0通常是忘记配置：旧短链键会按置换后的键解析，全部失效或指向其他记录。
+// 没有旧短链键时需显式配置为负数
+// 参数:
+//
+//	secret: 置换密钥，为空时不检查
+//	legacyLen: 配置的旧短链键最大长度
+//	maxID: 启动时表中的最大记录ID
+func CheckLegacyLength(secret string, legacyLen int, maxID int64) error {
+	if secret == "" || legacyLen != 0 || maxID <= 0 {
+		return nil
+	}
+	return fmt.Errorf("已有短链记录（最大ID %d）时启用短链键置换必须配置 shortKey.legacyLength：启用前签发过短链时设为 %d，否则设为 -1", maxID, len(ToBase62(maxID)))
+}
+
+// Encode 将记录ID转换为不少于最小长度的短链键
+func (c *feistelCodec) Encode(id int64) string {
+	if id <= 0 {
+		return ""
+	}
+	n := c.keyLen(uint64(id))
+	// 11位短链键的置换结果可能超过 int64，按无符号数编码
+	key := toBase62Uint(c.walk(uint64(id), n, false))
+	return strings.Repeat(chars[:1], n-len(key)) + key
+}
+
+// Decode 将短链键转换回记录ID，不是由 Encode 生成的短链键返回0
+func (c *feistelCodec) Decode(key string) int64 {
+	if len(key) <= c.legacyLen {
+		return ToBase10(key)
+	}
+	n := len(key)
+	if n < c.minLen || n > maxGeneratedKeyLen {
+		return 0
+	}
+	var x uint64
+	for i := 0; i < n; i++ {
+		index := strings.IndexByte(chars, key[i])
+		if index < 0 {
+			return 0
+		}
+		hi, lo := bits.Mul64(x, 62)
+		lo, carry := bits.Add64(lo, uint64(index), 0)
+		if hi != 0 || carry != 0 {
+			return 0
+		}
+		x = lo
+	}
+	id := c.walk(x, n, true)
+	// 每个ID只有一种编码，长度与ID不匹配的短链键不是由 Encode 生成的
+	if id == 0 || id > math.MaxInt64 || c.keyLen(id) != n {
+		return 0
+	}
+	return int64(id)
+}
+
+// keyLen 返回ID对应的短链键长度
+func (c *feistelCodec) keyLen(id uint64) int {
+	n := c.minLen
+	for n < maxGeneratedKeyLen && id >= pow62[n] {
+		n++
+	}
+	return n
+}
+
+// walk 在长度为 n 的短链键的定义域内置换，结果超出定义域时继续置换
+func (c *feistelCodec) walk(x uint64, n int, inverse bool) uint64 {
+	width := 64
+	if n < maxGeneratedKeyLen {
+		width = bits.Len64(pow62[n] - 1)
+		width += width % 2
+	}
+	for {
+		x = c.permute(x, width, inverse)
+		if n == maxGeneratedKeyLen || x < pow62[n] {
+			return x
+		}
+	}
+}
+
+// permute 在 width 位上做平衡 Feistel 置换，inverse 为 true 时做逆置换
+func (c *feistelCodec) permute(x uint64, width int, inverse bool) uint64 {
+	half := width / 2
+	mask := uint64(1)<<half - 1
+	l, r := x>>half, x&mask
+	if inverse {
+		for i := feistelRounds - 1; i >= 0; i-- {
+			l, r = r^(c.round(i, width, l)&mask), l
+		}
+	} else {
+		for i := 0; i < feistelRounds; i++ {
+			l, r = r, l^(c.round(i, width, r)&mask)
+		}
+	}
+	return l<<half | r
+}
+
+// round Feistel 轮函数，使用 HMAC-SHA256，位宽参与计算使不同长度的定义域相互独立
+func (c *feistelCodec) round(i, width int, v uint64) uint64 {
+	var buf [10]byte
+	buf[0] = byte(i)
+	buf[1] = byte(width)
+	binary.BigEndian.PutUint64(buf[2:], v)
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(buf[:])
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
+
+// toBase62Uint 将无符号整数转换为Base62编码的字符串
+func toBase62Uint(num uint64) string {
+	rs := ""
+	for num > 0 {
+		rs = string(chars[num%62]) + rs
+		num /= 62
+	}
+	return rs
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestKeyCodec(t *testing.T) {
+	c := NewKeyCodec("secret", 6, 0)
+	seen := make(map[string]bool)
+	for id := int64(1); id <= 10000; id++ {
+		key := c.Encode(id)
+		if len(key) != 6 {
+			t.Fatalf("Encode(%d) = %q, want length 6", id, key)
+		}
+		if seen[key] {
+			t.Fatalf("Encode(%d) = %q duplicated", id, key)
+		}
+		seen[key] = true
+		if got := c.Decode(key); got != id {
+			t.Fatalf("Decode(%q) = %d, want %d", key, got, id)
+		}
+	}
+	// 大ID按需加长，仍可解析
+	for i := 0; i < 1000; i++ {
+		id := rand.Int63n(math.MaxInt64) + 1
+		key := c.Encode(id)
+		if len(key) < 6 || IsAlias(key) {
+			t.Fatalf("Encode(%d) = %q", id, key)
+		}
+		if got := c.Decode(key); got != id {
+			t.Fatalf("Decode(%q) = %d, want %d", key, got, id)
+		}
+	}
+	if got := c.Decode(c.Encode(math.MaxInt64)); got != math.MaxInt64 {
+		t.Errorf("max id not decoded: %d", got)
+	}
+	// 不同密钥得到不同的短链键
+	if NewKeyCodec("other", 6, 0).Encode(1) == c.Encode(1) {
+		t.Error("key does not depend on secret")
+	}
+	// 过短或包含非法字符的短链键
+	for _, key := range []string{"", "abc", "abc-de", "zzzzzzzzzzzz"} {
+		if got := c.Decode(key); got != 0 {
+			t.Errorf("Decode(%q) = %d, want 0", key, got)
+		}
+	}
+}
+
+func TestKeyCodecLegacy(t *testing.T) {
+	c := NewKeyCodec("secret", 4, 5)
+	// 旧短链键按 Base62(ID) 解析
+	if got := c.Decode(ToBase62(123456)); got != 123456 {
+		t.Errorf("legacy key decoded to %d", got)
+	}
+	// 新短链键比旧短链键长，两者不会重复
+	if key := c.Encode(1); len(key) != 6 || c.Decode(key) != 1 {
+		t.Errorf("Encode(1) = %q", key)
+	}
+	if NewKeyCodec("", 6, 0).Encode(123456) != ToBase62(123456) {
+		t.Error("empty secret should keep Base62 keys")
+	}
+}
+
+func TestCheckLegacyLength(t *testing.T) {
+	cases := []struct {
+		secret    string
+		legacyLen int
+		maxID     int64
+		wantErr   bool
+	}{
+		{"", 0, 1000, false},
+		{"secret", 0, 0, false},
+		{"secret", 0, 1000, true},
+		{"secret", 2, 1000, false},
+		{"secret", -1, 1000, false},
+	}
+	for _, c := range cases {
+		if err := CheckLegacyLength(c.secret, c.legacyLen, c.maxID); (err != nil) != c.wantErr {
+			t.Errorf("CheckLegacyLength(%q, %d, %d) = %v", c.secret, c.legacyLen, c.maxID, err)
+		}
+	}
+	// 负数与0一样表示没有旧短链键
+	if NewKeyCodec("secret", 6, -1).Decode(ToBase62(1000)) == 1000 {
+		t.Error("negative legacy length should not decode Base62 keys")
+	}
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"errors"
+	"golang.org/x/net/idna"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxUrlLen 原始URL的最大长度（字节），original_url 为 TEXT 字段，限制长度避免超长请求占用存储与缓存
+const MaxUrlLen = 8192
+
+var (
+	ErrUrlInvalid  = errors.New("URL格式无效")
+	ErrUrlLength   = errors.New("URL长度不能超过8192字节")
+	ErrUrlScheme   = errors.New("只支持 http 和 https 协议的URL")
+	ErrUrlUserinfo = errors.New("URL不能包含用户名和密码")
+	ErrUrlHost     = errors.New("URL的主机名无效")
+)
+
+// defaultTrackingParams 内置的跟踪参数，以 * 结尾时按前缀匹配
+var defaultTrackingParams = []string{
+	"utm_*", "gclid", "gclsrc", "dclid", "fbclid", "msclkid", "yclid", "igshid", "mc_cid", "mc_eid", "_ga", "_gl",
+}
+
+// defaultPorts 各协议的默认端口，规范化时去掉
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// hostProfile 主机名转换为 punycode 的规则，允许浏览器同样接受的下划线
+var hostProfile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.StrictDomainName(false))
+
+// UrlNormalizer 原始URL的校验与规范化规则，跟踪参数在内置列表基础上追加
+type UrlNormalizer struct {
+	stripTracking bool
+	stripFragment bool
+	params        map[string]struct{}
+	prefixes      []string
+}
+
+// NewUrlNormalizer 创建URL规范化规则
+// 参数:
+//
+//	stripTracking: 是否去掉跟踪参数
+//	trackingParams: 追加的跟踪参数，以 * 结尾时按前缀匹配
+//	stripFragment: 是否去掉片段，单页应用的路由依赖片段时不要开启
+func NewUrlNormalizer(stripTracking bool, trackingParams []string, stripFragment bool) *UrlNormalizer {
+	n := &UrlNormalizer{
+		stripTracking: stripTracking,
+		stripFragment: stripFragment,
+		params:        make(map[string]struct{}),
+	}
+	for _, p := range append(defaultTrackingParams, trackingParams...) {
+		p = strings.ToLower(p)
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			n.prefixes = append(n.prefixes, prefix)
+			continue
+		}
+		n.params[p] = struct{}{}
+	}
+	return n
+}
+
+// Normalize 校验URL并返回规范形式，同一地址的不同写法规范化后相同：
+//   - 协议与主机名转为小写，国际化域名转为 punycode，去掉默认端口
+//   - 路径为空时补 /，百分号编码统一为大写，不需要编码的字符解码
+//   - 查询参数按参数名排序，同名参数保持原有顺序；开启时去掉跟踪参数
+//   - 片段保留，开启时去掉；空的查询与片段总是去掉
+//
+// 参数:
+//
+//	raw: 用户提交的URL
+//
+// 返回值:
+//
+//	string: 规范化后的URL
+//	error: 不符合规则时返回对应的 ErrUrl* 错误
+func (n *UrlNormalizer) Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", ErrUrlInvalid
+	}
+	if len(raw) > MaxUrlLen {
+		return "", ErrUrlLength
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Opaque != "" {
+		return "", ErrUrlInvalid
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[scheme]; !ok {
+		return "", ErrUrlScheme
+	}
+	if u.User != nil {
+		return "", ErrUrlUserinfo
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil || p < 1 || p > 65535 {
+			return "", ErrUrlInvalid
+		}
+		// 去掉端口号的前导0与默认端口
+		if port = strconv.Itoa(p); port == defaultPorts[scheme] {
+			port = ""
+		}
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	var b strings.Builder
+	b.WriteString(scheme)
+	b.WriteString("://")
+	b.WriteString(host)
+	path := normalizeEscapes(u.EscapedPath(), "/:@")
+	if path == "" {
+		path = "/"
+	}
+	b.WriteString(path)
+	if query := n.normalizeQuery(u.RawQuery); query != "" {
+		b.WriteString("?")
+		b.WriteString(query)
+	}
+	if !n.stripFragment && u.Fragment != "" {
+		b.WriteString("#")
+		b.WriteString(normalizeEscapes(u.EscapedFragment(), "/:@?"))
+	}
+	if b.Len() > MaxUrlLen {
+		return "", ErrUrlLength
+	}
+	return b.String(), nil
+}
+
+// normalizeQuery 规范化查询字符串，参数按参数名稳定排序，开启时去掉跟踪参数
+func (n *UrlNormalizer) normalizeQuery(rawQuery string) string {
+	type param struct {
+		key  string
+		pair string
+	}
+	var params []param
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		pair = normalizeEscapes(pair, "/:@?=")
+		key, _, _ := strings.Cut(pair, "=")
+		if n.stripTracking && n.isTracking(key) {
+			continue
+		}
+		params = append(params, param{key, pair})
+	}
+	sort.SliceStable(params, func(i, j int) bool { return params[i].key < params[j].key })
+
+	pairs := make([]string, 0, len(params))
+	for _, p := range params {
+		pairs = append(pairs, p.pair)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// isTracking 判断参数名是否为跟踪参数，不区分大小写
+func (n *UrlNormalizer) isTracking(key string) bool {
+	if k, err := url.QueryUnescape(key); err == nil {
+		key = k
+	}
+	key = strings.ToLower(key)
+	if _, ok := n.params[key]; ok {
+		return true
+	}
+	for _, prefix := range n.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHost 校验主机名并返回规范形式，IP 地址转为标准写法，域名转为小写的 punycode
+// 不接受 localhost 等单标签主机名，以及顶级域为纯数字、可能被解析为 IPv4 简写的主机名
+func normalizeHost(host string) (string, error) {
+	if host == "" {
+		return "", ErrUrlHost
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	if strings.Contains(host, ":") {
+		return "", ErrUrlHost
+	}
+	ascii, err := hostProfile.ToASCII(strings.TrimSuffix(host, "."))
+	if err != nil || len(ascii) > 253 {
+		return "", ErrUrlHost
+	}
+	ascii = strings.ToLower(ascii)
+	labels := strings.Split(ascii, ".")
+	if len(labels) < 2 {
+		return "", ErrUrlHost
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", ErrUrlHost
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' {
+				return "", ErrUrlHost
+			}
+		}
+	}
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return "", ErrUrlHost
+	}
+	return ascii, nil
+}
+
+// normalizeEscapes 统一百分号编码：编码字母大写，不需要编码的字符（字母、数字与 -._~）解码，
+// 不允许直接出现的字符编码；extra 为该部分允许直接出现的其他字符，子分隔符 !$&'()*+,;= 总是允许
+func normalizeEscapes(s, extra string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			v := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(v) {
+				b.WriteByte(v)
+			} else {
+				b.WriteByte('%')
+				b.WriteByte(hex[v>>4])
+				b.WriteByte(hex[v&15])
+			}
+			i += 2
+			continue
+		}
+		if isUnreserved(c) || strings.IndexByte("!$&'()*+,;=", c) >= 0 || strings.IndexByte(extra, c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
+
+// isUnreserved 判断字符是否为 RFC 3986 中不需要编码的字符
+func isUnreserved(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	}
+	return c - 'A' + 10
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"shorturl/pkg/config"
+	"shorturl/pkg/db/mysql"
+	"shorturl/pkg/log"
+	"shorturl/pkg/utils"
+	"shorturl/shorturl-server/data"
+	"time"
+)
+
+var (
+	configFile = flag.String("config", "dev.config.yaml", "")
+	batchSize  = flag.Int("batch", 500, "每批处理的记录数")
+)
+
+// main 为升级前创建的可复用短链回填原始URL哈希，与 shorturl-server 使用同一份配置
+// shorturl-server 按规范化后的原始URL计算哈希并比较原始URL，直接对数据库中的原始URL计算哈希时，
+// 主机名大小写、默认端口、查询参数顺序不同的旧记录永远不会被复用；
+// 回填时先用 utils.UrlNormalizer 改写原始URL，再写入哈希。可以重复执行，已回填的记录不会再处理
+func main() {
+	flag.Parse()
+
+	// 加载并解析指定的配置文件，规范化规则必须与 shorturl-server 一致
+	config.InitConfig(*configFile)
+	cnf := config.GetConfig()
+
+	logger := log.NewLogger()
+	logger.SetLevel(cnf.Log.Level)
+
+	// 初始化MySQL数据库连接池
+	mysql.InitMysql(cnf)
+
+	normalizer := utils.NewUrlNormalizer(cnf.UrlNormalize.StripTracking, cnf.UrlNormalize.TrackingParams, cnf.UrlNormalize.StripFragment)
+	for _, isPublic := range []bool{true, false} {
+		d := data.NewUrlHashBackfillData(logger, mysql.GetDB(), isPublic)
+		hashed, skipped, err := backfill(logger, d, normalizer, *batchSize)
+		if err != nil {
+			log.FatalF("回填失败 public=%v 已回填=%d: %v", isPublic, hashed, err)
+		}
+		log.InfoF("回填完成 public=%v 已回填=%d 跳过=%d", isPublic, hashed, skipped)
+	}
+}
+
+// backfill 按ID升序逐条回填一张表
+// 原始URL不符合当前规则的记录跳过，不再参与复用；同一范围内规范化后相同的记录只有ID最小的一条写入哈希
+// 返回：
+//   - 回填的条数
+//   - 跳过的条数
+//   - 错误信息（数据库操作失败时）
+func backfill(logger log.ILogger, d data.IUrlHashBackfillData, normalizer *utils.UrlNormalizer, limit int) (int, int, error) {
+	hashed, skipped := 0, 0
+	var afterID int64
+	for {
+		list, err := d.ListUnhashed(afterID, limit)
+		if err != nil {
+			return hashed, skipped, err
+		}
+		if len(list) == 0 {
+			return hashed, skipped, nil
+		}
+		for _, e := range list {
+			afterID = e.ID
+			url, err := normalizer.Normalize(e.OriginalUrl)
+			if err != nil {
+				logger.WarningF("跳过原始URL无效的记录 id=%d: %v", e.ID, err)
+				skipped++
+				continue
+			}
+			err = d.SetCanonicalUrl(e.ID, url, utils.UrlHash(url), time.Now().Unix())
+			if errors.Is(err, data.ErrUrlHashExists) {
+				skipped++
+				continue
+			}
+			if err != nil {
+				return hashed, skipped, err
+			}
+			hashed++
+		}
+	}
+}
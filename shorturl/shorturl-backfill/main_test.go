@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"shorturl/pkg/log"
+	"shorturl/pkg/utils"
+	"shorturl/shorturl-server/data"
+	"testing"
+)
+
+// fakeBackfillData 内存中的回填数据，哈希按写入顺序占用
+type fakeBackfillData struct {
+	rows   []data.UrlMapEntity
+	hashes map[string]int64
+	urls   map[int64]string
+}
+
+func (f *fakeBackfillData) ListUnhashed(afterID int64, limit int) ([]data.UrlMapEntity, error) {
+	var list []data.UrlMapEntity
+	for _, e := range f.rows {
+		if _, ok := f.urls[e.ID]; !ok && e.ID > afterID && len(list) < limit {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeBackfillData) SetCanonicalUrl(id int64, originalUrl string, urlHash []byte, now int64) error {
+	if _, ok := f.hashes[string(urlHash)]; ok {
+		return data.ErrUrlHashExists
+	}
+	f.hashes[string(urlHash)] = id
+	f.urls[id] = originalUrl
+	return nil
+}
+
+func TestBackfill(t *testing.T) {
+	f := &fakeBackfillData{
+		rows: []data.UrlMapEntity{
+			{ID: 1, OriginalUrl: "HTTPS://Example.com:443/a?b=2&a=1"},
+			{ID: 2, OriginalUrl: "https://example.com/a?a=1&b=2"},
+			{ID: 3, OriginalUrl: "http://localhost/x"},
+			{ID: 4, OriginalUrl: "http://example.org"},
+		},
+		hashes: make(map[string]int64),
+		urls:   make(map[int64]string),
+	}
+	logger := log.NewLogger()
+	logger.SetOutput(&bytes.Buffer{})
+	hashed, skipped, err := backfill(logger, f, utils.NewUrlNormalizer(false, nil, false), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashed != 2 || skipped != 2 {
+		t.Errorf("hashed=%d skipped=%d, want 2 2", hashed, skipped)
+	}
+	// 规范化后相同的记录只有ID最小的一条写入，并且能按服务端的规范化结果查到
+	want := "https://example.com/a?a=1&b=2"
+	if f.urls[1] != want || f.hashes[string(utils.UrlHash(want))] != 1 {
+		t.Errorf("record 1 = %q", f.urls[1])
+	}
+	if f.urls[4] != "http://example.org/" {
+		t.Errorf("record 4 = %q", f.urls[4])
+	}
+}
@@ -12,6 +12,8 @@ import (
 type BloomFilter interface {
 	// Add 添加元素到布隆过滤器
 	Add(key string, value string) error
+	// AddMulti 批量添加元素到布隆过滤器，只写入一次
+	AddMulti(key string, values []string) error
 	// Exists 检查元素是否可能存在于布隆过滤器中
 	Exists(key string, value string) (bool, error)
 }
@@ -53,6 +55,22 @@ func (bf *RedisBloomFilter) Add(key string, value string) error {
 	return bf.redisClient.Set(context.Background(), bf.key, bits, 0).Err()
 }
 
+// AddMulti 批量添加元素到布隆过滤器，全部加入内存中的过滤器后只序列化并写入Redis一次
+func (bf *RedisBloomFilter) AddMulti(key string, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	for _, v := range values {
+		bf.filter.AddString(v)
+	}
+
+	bits, err := bf.filter.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return bf.redisClient.Set(context.Background(), bf.key, bits, 0).Err()
+}
+
 // Exists 检查元素是否可能存在于布隆过滤器中
 func (bf *RedisBloomFilter) Exists(key string, value string) (bool, error) {
 	// 从Redis获取布隆过滤器的位数组
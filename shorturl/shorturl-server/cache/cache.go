@@ -1,9 +1,43 @@
 package cache
 
+import "math/rand"
+
 const DefaultTTL = 30 * 86400
 
+// Entry 批量写入缓存的一项
+type Entry struct {
+	Key   string
+	Value string
+	TTL   int // 有效期（秒）
+}
+
 type KVCache interface {
 	Get(key string) (string, error)
 	Set(key, value string, ttl int) error
+	// SetMulti 批量写入缓存，分布式缓存使用一次管道提交
+	SetMulti(entries []Entry) error
 	Destroy()
 }
+
+// ttlReader 可查询键剩余有效期的缓存，二级缓存回填本地缓存时使用
+type ttlReader interface {
+	// TTL 返回键的剩余有效期（秒），键不存在或永不过期时返回0
+	TTL(key string) (int, error)
+}
+
+// RandomTTL 返回短链缓存的有效期（秒），在默认有效期基础上随机浮动，避免缓存雪崩
+// expireAt 不为0时不超过短链的剩余有效期
+// 参数:
+//
+//	expireAt: 短链过期时间戳，0表示永不过期
+//	now: 当前时间戳
+func RandomTTL(expireAt, now int64) int {
+	ttl := DefaultTTL*80/100 + rand.Intn(DefaultTTL*40/100)
+	if expireAt > 0 && expireAt-now < int64(ttl) {
+		ttl = int(expireAt - now)
+		if ttl < 1 {
+			ttl = 1
+		}
+	}
+	return ttl
+}
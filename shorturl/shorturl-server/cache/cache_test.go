@@ -0,0 +1,24 @@
+package cache
+
+import "testing"
+
+func TestRandomTTL(t *testing.T) {
+	now := int64(1700000000)
+	for i := 0; i < 100; i++ {
+		if ttl := RandomTTL(0, now); ttl < DefaultTTL*80/100 || ttl >= DefaultTTL*120/100 {
+			t.Fatalf("ttl out of range: %d", ttl)
+		}
+	}
+	// 有期限的短链不超过剩余有效期
+	if ttl := RandomTTL(now+60, now); ttl != 60 {
+		t.Errorf("expected 60, got %d", ttl)
+	}
+	// 已到期时仍返回正数，避免 SetEx 报错
+	if ttl := RandomTTL(now, now); ttl != 1 {
+		t.Errorf("expected 1, got %d", ttl)
+	}
+	// 剩余有效期长于默认有效期时不受影响
+	if ttl := RandomTTL(now+10*DefaultTTL, now); ttl > DefaultTTL*120/100 {
+		t.Errorf("ttl not randomized: %d", ttl)
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"shorturl/pkg/constants"
+	pkgredis "shorturl/pkg/db/redis"
+	"shorturl/pkg/log"
+)
+
+// CacheInvalidator 缓存失效器
+// 本地缓存只存在于单个实例中，修改或禁用短链后需要通知所有实例删除各自的本地缓存
+type CacheInvalidator interface {
+	// Invalidate 删除分布式缓存中的键，并广播通知所有实例删除本地缓存
+	Invalidate(keys ...string) error
+	// Subscribe 订阅失效通知，收到通知后删除本地缓存，ctx 取消时退出
+	Subscribe(ctx context.Context)
+}
+
+// RedisCacheInvalidator 基于Redis发布订阅的缓存失效器
+type RedisCacheInvalidator struct {
+	logger     log.ILogger
+	redisPool  pkgredis.RedisPool
+	localCache LocalCache
+	channel    string
+}
+
+// NewRedisCacheInvalidator 创建一个新的Redis缓存失效器
+func NewRedisCacheInvalidator(logger log.ILogger, redisPool pkgredis.RedisPool, localCache LocalCache) CacheInvalidator {
+	return &RedisCacheInvalidator{
+		logger:     logger,
+		redisPool:  redisPool,
+		localCache: localCache,
+		channel:    pkgredis.GetKey(constants.CACHE_INVALIDATE_CHANNEL),
+	}
+}
+
+// Invalidate 删除分布式缓存中的键，并广播通知所有实例删除本地缓存
+func (i *RedisCacheInvalidator) Invalidate(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	client := i.redisPool.Get()
+	defer i.redisPool.Put(client)
+
+	redisKeys := make([]string, len(keys))
+	for idx, key := range keys {
+		redisKeys[idx] = getKey(key)
+		// 当前实例直接删除，不依赖订阅回环
+		i.localCache.Delete(key)
+	}
+	if err := client.Del(context.Background(), redisKeys...).Err(); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := client.Publish(context.Background(), i.channel, key).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe 订阅失效通知
+// 订阅连接长期占用，因此不从连接池借用客户端，而是单独持有一个
+func (i *RedisCacheInvalidator) Subscribe(ctx context.Context) {
+	client := i.redisPool.Get()
+	pubsub := client.Subscribe(ctx, i.channel)
+	go func() {
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				i.localCache.Delete(msg.Payload)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	i.logger.Info("已订阅本地缓存失效通知: " + i.channel)
+}
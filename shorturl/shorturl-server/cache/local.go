@@ -174,8 +174,13 @@ func (c *TwoLevelCache) Get(key string) (string, error) {
 
 	// 如果分布式缓存中有值，则更新本地缓存
 	if value != "" {
-		// 使用随机过期时间，避免缓存雪崩
+		// 使用随机过期时间，避免缓存雪崩；不超过分布式缓存的剩余有效期，有期限的短链不会在本地缓存中存活更久
 		ttl := time.Duration(DefaultTTL*80/100+rand.Intn(DefaultTTL*40/100)) * time.Second
+		if r, ok := c.distributed.(ttlReader); ok {
+			if remain, err := r.TTL(key); err == nil && remain > 0 && time.Duration(remain)*time.Second < ttl {
+				ttl = time.Duration(remain) * time.Second
+			}
+		}
 		c.localCache.Set(key, value, ttl)
 	}
 
@@ -191,13 +196,26 @@ func (c *TwoLevelCache) Set(key, value string, ttl int) error {
 	}
 
 	// 再存储到本地缓存
-	// 使用随机过期时间，避免缓存雪崩
-	localTTL := time.Duration(ttl*80/100+rand.Intn(ttl*40/100)) * time.Second
+	// 使用随机过期时间，避免缓存雪崩，不超过分布式缓存的有效期
+	localTTL := time.Duration(ttl*80/100+rand.Intn(ttl*20/100+1)) * time.Second
 	c.localCache.Set(key, value, localTTL)
 
 	return nil
 }
 
+// SetMulti 批量存储到两级缓存中，分布式缓存写入成功后再写入本地缓存
+func (c *TwoLevelCache) SetMulti(entries []Entry) error {
+	err := c.distributed.SetMulti(entries)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		localTTL := time.Duration(e.TTL*80/100+rand.Intn(e.TTL*20/100+1)) * time.Second
+		c.localCache.Set(e.Key, e.Value, localTTL)
+	}
+	return nil
+}
+
 // Destroy 释放资源
 func (c *TwoLevelCache) Destroy() {
 	c.distributed.Destroy()
@@ -79,6 +79,46 @@ func (c *redisKVCache) Set(key, value string, ttl int) error {
 	return c.redisClient.SetEx(context.Background(), key, value, time.Second*time.Duration(ttl)).Err()
 }
 
+// SetMulti 使用管道批量存储键值对，只需一次网络往返。
+//
+// 参数:
+//
+//	entries []Entry - 要存储的键值对及各自的生存时间（秒）。
+//
+// 返回值:
+//
+//	error - 存储操作的错误信息，若成功则为nil。
+func (c *redisKVCache) SetMulti(entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	pipe := c.redisClient.Pipeline()
+	for _, e := range entries {
+		pipe.SetEx(context.Background(), getKey(e.Key), e.Value, time.Second*time.Duration(e.TTL))
+	}
+	_, err := pipe.Exec(context.Background())
+	return err
+}
+
+// TTL 返回键的剩余有效期（秒）。
+//
+// 参数:
+//
+//	key string - 要查询的键名。
+//
+// 返回值:
+//
+//	int - 剩余有效期，键不存在或未设置过期时间时为0。
+//	error - 查询操作的错误信息，若成功则为nil。
+func (c *redisKVCache) TTL(key string) (int, error) {
+	key = getKey(key)
+	d, err := c.redisClient.TTL(context.Background(), key).Result()
+	if err != nil || d < 0 {
+		return 0, err
+	}
+	return int(d / time.Second), nil
+}
+
 // Destroy 释放与缓存相关的资源。
 //
 // 调用时会执行destroy函数（如将Redis客户端放回连接池）。
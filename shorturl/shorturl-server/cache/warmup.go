@@ -2,7 +2,6 @@ package cache
 
 import (
 	"context"
-	"math/rand"
 	"shorturl/pkg/log"
 	"shorturl/shorturl-server/data"
 	"strconv"
@@ -65,15 +64,16 @@ func (w *ShortUrlCacheWarmer) warmupPublicUrls(ctx context.Context) error {
 	d := w.urlDataFactory.NewUrlMapData(true)
 
 	// 获取访问次数最多的前100个短链接
-	urls, err := d.GetTopUrls(100)
+	now := time.Now().Unix()
+	urls, err := d.GetTopUrls(100, now)
 	if err != nil {
 		return err
 	}
 
 	// 将短链接数据预热到缓存
 	for _, url := range urls {
-		// 使用随机过期时间，避免缓存雪崩
-		ttl := DefaultTTL*80/100 + rand.Intn(DefaultTTL*40/100)
+		// 使用随机过期时间，避免缓存雪崩，有期限的短链不超过剩余有效期
+		ttl := RandomTTL(url.ExpireAt, now)
 
 		// 缓存原始URL
 		key := url.ShortKey
@@ -101,15 +101,16 @@ func (w *ShortUrlCacheWarmer) warmupUserUrls(ctx context.Context) error {
 	d := w.urlDataFactory.NewUrlMapData(false)
 
 	// 获取访问次数最多的前50个用户短链接
-	urls, err := d.GetTopUrls(50)
+	now := time.Now().Unix()
+	urls, err := d.GetTopUrls(50, now)
 	if err != nil {
 		return err
 	}
 
 	// 将短链接数据预热到缓存
 	for _, url := range urls {
-		// 使用随机过期时间，避免缓存雪崩
-		ttl := DefaultTTL*80/100 + rand.Intn(DefaultTTL*40/100)
+		// 使用随机过期时间，避免缓存雪崩，有期限的短链不超过剩余有效期
+		ttl := RandomTTL(url.ExpireAt, now)
 
 		// 缓存原始URL
 		key := "user_" + url.ShortKey
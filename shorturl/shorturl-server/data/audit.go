@@ -0,0 +1,53 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"shorturl/pkg/audit"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/log"
+	"shorturl/pkg/zerror"
+	"strings"
+)
+
+// IAuditData 定义审计记录写入的接口规范，审计记录的查询与导出由 mediahub 提供
+type IAuditData interface {
+	// Append 批量写入审计记录
+	Append(records []*audit.Record) error
+}
+
+type auditData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewAuditData 创建审计记录数据操作对象
+func NewAuditData(log log.ILogger, db *sql.DB) IAuditData {
+	return &auditData{
+		log:       log,
+		db:        db,
+		tableName: constants.TABLENAME_AUDIT_LOG,
+	}
+}
+
+// Append 使用一条多行 insert 写入审计记录
+func (d *auditData) Append(records []*audit.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	placeholders := make([]string, 0, len(records))
+	args := make([]any, 0, len(records)*13)
+	for _, r := range records {
+		placeholders = append(placeholders, "(?,?,?,?,?,?,?,?,?,?,?,?,?)")
+		args = append(args, r.Service, r.ActorType, r.ActorID, r.ActorName, r.Client, r.Action,
+			r.TargetType, r.TargetID, r.IP, r.UserAgent, r.Outcome, r.Detail, r.CreateAt)
+	}
+	sqlStr := fmt.Sprintf("insert into %s (service,actor_type,actor_id,actor_name,client,action,target_type,target_id,ip,user_agent,outcome,detail,create_at)values%s",
+		d.tableName, strings.Join(placeholders, ","))
+	if _, err := d.db.Exec(sqlStr, args...); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/log"
+	"shorturl/pkg/zerror"
+	"time"
+)
+
+// IIdSequenceData 定义ID号段预留的接口规范
+type IIdSequenceData interface {
+	// NextRange 预留 step 个连续ID，返回号段的最大ID，号段为 (max-step, max]
+	NextRange(name string, step int64) (int64, error)
+}
+
+type idSequenceData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewIdSequenceData 创建ID号段数据操作对象
+func NewIdSequenceData(log log.ILogger, db *sql.DB) IIdSequenceData {
+	return &idSequenceData{
+		log:       log,
+		db:        db,
+		tableName: constants.TABLENAME_ID_SEQUENCE,
+	}
+}
+
+// NextRange 预留一个号段
+// 使用 last_insert_id(expr) 在一条 update 中增加并取回最大ID，并发预留时由行锁保证号段不重叠
+// 序列不存在时以对应短链表的最大ID初始化
+// 参数：
+//   - name: 序列名称，使用短链表名
+//   - step: 号段长度
+//
+// 返回：
+//   - 号段的最大ID
+//   - 错误信息（数据库操作失败时）
+func (d *idSequenceData) NextRange(name string, step int64) (int64, error) {
+	sqlStr := fmt.Sprintf("update %s set max_id = last_insert_id(max_id + ?), update_at = ? where name = ?", d.tableName)
+	for i := 0; i < 2; i++ {
+		res, err := d.db.Exec(sqlStr, step, time.Now().Unix(), name)
+		if err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return 0, err
+		}
+		if n > 0 {
+			return res.LastInsertId()
+		}
+		// 多个实例同时初始化时只有一条生效
+		initStr := fmt.Sprintf("insert ignore into %s (name, max_id, update_at) select ?, ifnull(max(id), 0), ? from %s", d.tableName, name)
+		if _, err = d.db.Exec(initStr, name, time.Now().Unix()); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return 0, err
+		}
+	}
+	err := zerror.NewByMsg("ID序列初始化失败 name=" + name)
+	d.log.Error(err)
+	return 0, err
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/log"
+	"shorturl/pkg/zerror"
+)
+
+// IRbacData 定义用户权限查询的接口规范，角色与授权由 mediahub 管理
+type IRbacData interface {
+	// UserPermissions 查询用户通过角色获得的全部权限
+	UserPermissions(userID int64) ([]string, error)
+}
+
+type rbacData struct {
+	log log.ILogger // 日志记录器
+	db  *sql.DB     // 数据库连接
+}
+
+// NewRbacData 创建用户权限数据操作对象
+func NewRbacData(log log.ILogger, db *sql.DB) IRbacData {
+	return &rbacData{
+		log: log,
+		db:  db,
+	}
+}
+
+// UserPermissions 查询用户通过角色获得的全部权限
+func (d *rbacData) UserPermissions(userID int64) ([]string, error) {
+	sqlStr := fmt.Sprintf("select distinct p.permission from %s u join %s p on p.role_id=u.role_id where u.user_id=?", constants.TABLENAME_RBAC_USER_ROLE, constants.TABLENAME_RBAC_ROLE_PERMISSION)
+	rows, err := d.db.Query(sqlStr, userID)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]string, 0)
+	for rows.Next() {
+		var p string
+		if err = rows.Scan(&p); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		results = append(results, p)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/log"
+	"shorturl/pkg/zerror"
+)
+
+// IUrlHashBackfillData 为升级前创建的可复用短链回填原始URL哈希
+type IUrlHashBackfillData interface {
+	// ListUnhashed 按ID升序查询 afterID 之后未回填哈希的可复用短链
+	ListUnhashed(afterID int64, limit int) ([]UrlMapEntity, error)
+
+	// SetCanonicalUrl 将原始URL改写为规范形式并写入哈希
+	SetCanonicalUrl(id int64, originalUrl string, urlHash []byte, now int64) error
+}
+
+type urlHashBackfillData struct {
+	log       log.ILogger // 日志记录器
+	db        *sql.DB     // 数据库连接
+	tableName string      // 表名
+}
+
+// NewUrlHashBackfillData 创建哈希回填的数据操作对象
+// 参数：
+//
+//	log：日志接口实例
+//	db：数据库连接对象
+//	isPublic：true 表示公共表 url_map，false 表示用户表 url_map_user
+func NewUrlHashBackfillData(log log.ILogger, db *sql.DB, isPublic bool) IUrlHashBackfillData {
+	tableName := constants.TABLENAME_URL_MAP
+	if !isPublic {
+		tableName = constants.TABLENAME_URL_MAP_USER
+	}
+	return &urlHashBackfillData{
+		log:       log,
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// ListUnhashed 查询未回填哈希的可复用短链，复用条件与 GetByOriginals 相同
+// 参数：
+//   - afterID: 只查询ID大于该值的记录
+//   - limit: 最多返回的条数
+//
+// 返回：
+//   - 只包含 ID 与 OriginalUrl 的实体对象
+//   - 错误信息（数据库操作失败时）
+func (d *urlHashBackfillData) ListUnhashed(afterID int64, limit int) ([]UrlMapEntity, error) {
+	sqlStr := fmt.Sprintf("select id, original_url from %s where id > ? and url_hash is null and status = ? and alias is null and expire_at = 0 and max_clicks = 0 and short_key <> '' order by id limit ?",
+		d.tableName)
+	rows, err := d.db.Query(sqlStr, afterID, constants.URL_STATUS_NORMAL, limit)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]UrlMapEntity, 0, limit)
+	for rows.Next() {
+		var entity UrlMapEntity
+		if err = rows.Scan(&entity.ID, &entity.OriginalUrl); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		results = append(results, entity)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
+
+// SetCanonicalUrl 改写原始URL并写入哈希，查询复用时比较的是规范化后的原始URL，两者必须一起改写
+// 参数：
+//   - id: 记录ID
+//   - originalUrl: 规范化后的原始URL
+//   - urlHash: 规范化后原始URL的哈希
+//   - now: 当前时间戳
+//
+// 返回：
+//   - 错误信息（同一范围内已有相同哈希的记录时返回 ErrUrlHashExists）
+func (d *urlHashBackfillData) SetCanonicalUrl(id int64, originalUrl string, urlHash []byte, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set original_url = ?, url_hash = ?, update_at = ? where id = ? and url_hash is null", d.tableName)
+	_, err := d.db.Exec(sqlStr, originalUrl, urlHash, now, id)
+	if err != nil {
+		if dupErr := duplicateError(err); dupErr != nil {
+			return dupErr
+		}
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
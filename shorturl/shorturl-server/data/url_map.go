@@ -3,9 +3,13 @@ package data
 import (
 	"database/sql"
 	"fmt"
+	"github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
+	"shorturl/pkg/constants"
 	"shorturl/pkg/log"
+	"shorturl/pkg/utils"
 	"shorturl/pkg/zerror"
+	"strings"
 )
 
 // UrlMapEntity 表示URL映射的实体对象，包含原始URL及其短链接映射关系
@@ -15,29 +19,120 @@ type UrlMapEntity struct {
 	ShortKey    string `json:"short_key"`    // 短链接键
 	OriginalUrl string `json:"original_url"` // 原始URL
 	Times       int    `json:"times"`        // 访问次数
+	Status      int    `json:"status"`       // 状态，见 constants.URL_STATUS_*
+	ExpireAt    int64  `json:"expire_at"`    // 过期时间戳，0表示永不过期
+	MaxClicks   int64  `json:"max_clicks"`   // 最大访问次数，0表示不限制
 	CreateAt    int64  `json:"create_at"`    // 创建时间戳
 	UpdateAt    int64  `json:"update_at"`    // 最后更新时间戳
+
+	UrlHash        []byte `json:"-"` // 原始URL的哈希，只在创建可复用的短链时写入，见 utils.UrlHash
+	IdempotencyKey string `json:"-"` // 调用方提供的幂等键，只在创建时写入
+}
+
+// Expired 判断短链是否已过期或已达到访问次数上限
+func (e *UrlMapEntity) Expired(now int64) bool {
+	return (e.ExpireAt > 0 && e.ExpireAt <= now) || (e.MaxClicks > 0 && int64(e.Times) >= e.MaxClicks)
+}
+
+// 违反唯一索引时返回的错误
+var (
+	ErrAliasExists          = errors.New("别名已被占用")        // 自定义别名已被占用
+	ErrUrlHashExists        = errors.New("原始URL已有可复用的短链") // 同一范围内已有相同原始URL哈希的记录
+	ErrIdempotencyKeyExists = errors.New("幂等键已被使用")       // 同一范围内已有相同幂等键的记录
+)
+
+// duplicateErrors 唯一索引名与对应的错误
+var duplicateErrors = map[string]error{
+	"unique_alias":       ErrAliasExists,
+	"unique_url_hash":    ErrUrlHashExists,
+	"unique_idempotency": ErrIdempotencyKeyExists,
 }
 
+// mysqlDuplicateEntry 违反唯一索引时的 MySQL 错误码
+const mysqlDuplicateEntry = 1062
+
 // IUrlMapData 定义URL映射数据操作的接口规范
 type IUrlMapData interface {
-	// GenerateID 生成新的URL映射记录并返回自增ID
-	GenerateID(userID, now int64) (int64, error)
-
-	// Update 更新指定URL映射记录
-	Update(e UrlMapEntity) error
+	// Create 创建URL映射记录，ID与短链键由调用方生成
+	Create(e UrlMapEntity) error
 
 	// GetByID 通过ID查询URL映射记录
 	GetByID(id int64) (*UrlMapEntity, error)
 
-	// GetByOriginal 通过原始URL查询映射记录
-	GetByOriginal(originalUrl string) (UrlMapEntity, error)
+	// GetByOriginal 通过原始URL查询用户可复用的映射记录
+	GetByOriginal(userID int64, originalUrl string) (UrlMapEntity, error)
+
+	// GetByOriginals 通过原始URL批量查询用户可复用的映射记录，按原始URL索引
+	GetByOriginals(userID int64, originalUrls []string) (map[string]UrlMapEntity, error)
+
+	// GetByUrlHash 通过原始URL哈希查询用户的映射记录
+	GetByUrlHash(userID int64, urlHash []byte) (*UrlMapEntity, error)
+
+	// GetByIdempotencyKey 通过幂等键查询用户的映射记录
+	GetByIdempotencyKey(userID int64, key string) (*UrlMapEntity, error)
+
+	// ReleaseUrlHash 清除记录的原始URL哈希，该记录不再参与去重
+	ReleaseUrlHash(id int64, now int64) error
+
+	// BatchCreate 批量创建URL映射记录，ID与短链键由调用方生成
+	BatchCreate(entities []UrlMapEntity) error
+
+	// CreateAlias 使用自定义别名创建URL映射记录，ID由调用方生成
+	CreateAlias(e UrlMapEntity) error
 
-	// IncrementTimes 增加指定记录的访问次数
-	IncrementTimes(id int64, incrementTimes int, now int64) error
-	
-	// GetTopUrls 获取访问次数最多的前N个URL映射记录
-	GetTopUrls(limit int) ([]UrlMapEntity, error)
+	// GetByAlias 通过自定义别名查询URL映射记录
+	GetByAlias(alias string) (*UrlMapEntity, error)
+
+	// IncrementTimesByAlias 增加自定义别名的访问次数，短链已过期时返回false
+	IncrementTimesByAlias(alias string, incrementTimes int, now int64) (bool, error)
+
+	// IncrementTimes 增加指定记录的访问次数，短链已过期时返回false
+	IncrementTimes(id int64, incrementTimes int, now int64) (bool, error)
+
+	// GetTopUrls 获取访问次数最多的前N个有效URL映射记录
+	GetTopUrls(limit int, now int64) ([]UrlMapEntity, error)
+
+	// UpdateOriginalUrl 修改短链指向的原始URL
+	UpdateOriginalUrl(id int64, originalUrl string, now int64) error
+
+	// SetStatus 修改短链状态
+	SetStatus(id int64, status int, now int64) error
+
+	// Delete 标记删除短链并释放自定义别名
+	Delete(id int64, now int64) error
+
+	// List 分页查询用户的短链，返回当前页记录与总数
+	List(q *UrlMapQuery) ([]UrlMapEntity, int64, error)
+
+	// MaxID 查询表中的最大ID
+	MaxID() (int64, error)
+}
+
+// 短链列表的状态过滤条件
+const (
+	ListStatusActive   = "active"   // 正常且未过期
+	ListStatusExpired  = "expired"  // 已过期或已达到访问次数上限
+	ListStatusPaused   = "paused"   // 属主已停用
+	ListStatusDisabled = "disabled" // 管理员已下架
+)
+
+// 短链列表的排序方式
+const (
+	ListSortRecent = "recent" // 按创建时间倒序
+	ListSortClicks = "clicks" // 按访问次数倒序
+)
+
+// UrlMapQuery 用户短链列表的查询条件，零值字段不参与过滤，已删除的短链不返回
+type UrlMapQuery struct {
+	UserID     int64
+	CreateFrom int64  // 创建时间起（含）
+	CreateTo   int64  // 创建时间止（不含）
+	Domain     string // 目标域名，只匹配该域名本身
+	Status     string // 见 ListStatus* 常量
+	Sort       string // 见 ListSort* 常量，默认按创建时间倒序
+	Now        int64  // 当前时间戳，用于判断是否过期
+	Offset     int
+	Limit      int
 }
 
 type urlMapData struct {
@@ -55,97 +150,300 @@ func newUrlMapData(log log.ILogger, db *sql.DB, tableName string) IUrlMapData {
 	}
 }
 
-// GenerateID 创建新的URL映射记录
+// Create 使用一条 insert 创建URL映射记录，去重与幂等由唯一索引保证
 // 参数：
-//   - userID: 用户ID（0表示公共链接）
-//   - now: 当前时间戳
+//   - e: 需要创建的实体对象，ID 与 ShortKey 由调用方生成
+//
+// 返回：
+//   - 错误信息（原始URL已有可复用的短链时返回 ErrUrlHashExists，幂等键已被使用时返回 ErrIdempotencyKeyExists）
+func (d *urlMapData) Create(e UrlMapEntity) error {
+	return d.insert([]UrlMapEntity{e}, false)
+}
+
+// GetByID 通过ID查询URL映射记录
+// 参数：
+//   - id: 记录ID
+//
+// 返回：
+//   - 查询到的实体对象（未找到时各字段为零值）
+//   - 错误信息（数据库操作失败时，未找到时为 sql.ErrNoRows）
+func (d *urlMapData) GetByID(id int64) (*UrlMapEntity, error) {
+	sqlStr := fmt.Sprintf("select %s from %s where id = ?", d.entityColumns(), d.tableName)
+	entity := UrlMapEntity{}
+	err := d.db.QueryRow(sqlStr, id).Scan(d.entityDest(&entity)...)
+	// 未找到时返回 sql.ErrNoRows，已归档的过期短链经常走到这里，不记录错误日志
+	if errors.Is(err, sql.ErrNoRows) {
+		return &entity, err
+	}
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return &entity, err
+	}
+	return &entity, nil
+}
+
+// entityColumns 返回查询完整记录的字段，只有用户表有 user_id 字段
+func (d *urlMapData) entityColumns() string {
+	columns := "id, short_key, original_url, status, times, expire_at, max_clicks, create_at, update_at"
+	if d.tableName == constants.TABLENAME_URL_MAP_USER {
+		columns += ", user_id"
+	}
+	return columns
+}
+
+// entityDest 返回与 entityColumns 顺序一致的扫描目标
+func (d *urlMapData) entityDest(e *UrlMapEntity) []any {
+	dest := []any{&e.ID, &e.ShortKey, &e.OriginalUrl, &e.Status, &e.Times, &e.ExpireAt, &e.MaxClicks, &e.CreateAt, &e.UpdateAt}
+	if d.tableName == constants.TABLENAME_URL_MAP_USER {
+		dest = append(dest, &e.UserID)
+	}
+	return dest
+}
+
+// GetByOriginal 通过原始URL查询可复用的映射记录，用户短链只复用同一用户的记录
+// 参数：
+//   - userID: 用户ID，公共短链表忽略
+//   - originalUrl: 原始URL
+//
+// 返回：
+//   - 查询到的实体对象（未找到时各字段为零值）
+//   - 错误信息（数据库操作失败时）
+func (d *urlMapData) GetByOriginal(userID int64, originalUrl string) (UrlMapEntity, error) {
+	results, err := d.GetByOriginals(userID, []string{originalUrl})
+	if err != nil {
+		return UrlMapEntity{}, err
+	}
+	return results[originalUrl], nil
+}
+
+// GetByOriginals 通过原始URL批量查询可复用的映射记录，复用条件与 GetByOriginal 相同
+// 按原始URL哈希查询，并比较原始URL排除哈希碰撞
+// 参数：
+//   - userID: 用户ID，公共短链表忽略
+//   - originalUrls: 原始URL列表
 //
 // 返回：
-//   - 新生成的记录ID
+//   - 以原始URL为键的实体对象，未找到的URL不在结果中
 //   - 错误信息（数据库操作失败时）
-func (d *urlMapData) GenerateID(userID, now int64) (int64, error) {
-	if userID != 0 {
-		sqlStr := fmt.Sprintf("insert into %s (user_id,create_at,update_at)values(?,?,?)", d.tableName)
-		res, err := d.db.Exec(sqlStr, userID, now, now)
-		if err != nil {
+func (d *urlMapData) GetByOriginals(userID int64, originalUrls []string) (map[string]UrlMapEntity, error) {
+	results := make(map[string]UrlMapEntity, len(originalUrls))
+	if len(originalUrls) == 0 {
+		return results, nil
+	}
+	// 已禁用的短链不再复用，重新生成；自定义别名只能显式指定，有期限的短链各自独立，均不参与复用
+	scope, args := d.userScope(userID)
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(originalUrls)), ",")
+	sqlStr := fmt.Sprintf("select id, short_key, original_url from %s where %surl_hash in (%s) and status = ? and alias is null and expire_at = 0 and max_clicks = 0 and short_key <> ''",
+		d.tableName, scope, placeholders)
+	wanted := make(map[string]struct{}, len(originalUrls))
+	for _, u := range originalUrls {
+		wanted[u] = struct{}{}
+		args = append(args, utils.UrlHash(u))
+	}
+	args = append(args, constants.URL_STATUS_NORMAL)
+	rows, err := d.db.Query(sqlStr, args...)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entity UrlMapEntity
+		if err = rows.Scan(&entity.ID, &entity.ShortKey, &entity.OriginalUrl); err != nil {
 			d.log.Error(zerror.NewByErr(err))
-			return 0, err
+			return nil, err
 		}
-		return res.LastInsertId()
-	} else {
-		sqlStr := fmt.Sprintf("insert into %s (create_at,update_at)values(?,?)", d.tableName)
-		res, err := d.db.Exec(sqlStr, now, now)
-		if err != nil {
-			d.log.Error(zerror.NewByErr(err))
-			return 0, err
+		if _, ok := wanted[entity.OriginalUrl]; ok {
+			results[entity.OriginalUrl] = entity
 		}
-		return res.LastInsertId()
 	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return results, nil
+}
 
+// GetByUrlHash 通过原始URL哈希查询映射记录，包括已禁用的记录
+// 哈希只写入可复用的短链，调用方需要比较原始URL以排除哈希碰撞
+// 参数：
+//   - userID: 用户ID，公共短链表忽略
+//   - urlHash: 原始URL哈希
+//
+// 返回：
+//   - 查询到的实体对象（未找到时为nil）
+//   - 错误信息（数据库操作失败时）
+func (d *urlMapData) GetByUrlHash(userID int64, urlHash []byte) (*UrlMapEntity, error) {
+	return d.getBy("url_hash = ?", userID, urlHash)
 }
 
-// Update 更新URL映射记录
+// GetByIdempotencyKey 通过幂等键查询映射记录，包括已禁用和已删除的记录
 // 参数：
-//   - e: 需要更新的实体对象
+//   - userID: 用户ID，公共短链表忽略
+//   - key: 幂等键
 //
 // 返回：
+//   - 查询到的实体对象（未找到时为nil）
 //   - 错误信息（数据库操作失败时）
-func (d *urlMapData) Update(e UrlMapEntity) error {
-	sqlStr := fmt.Sprintf("update %s set short_key=?,original_url=?,update_at=? where id=?", d.tableName)
-	_, err := d.db.Exec(sqlStr, e.ShortKey, e.OriginalUrl, e.UpdateAt, e.ID)
+func (d *urlMapData) GetByIdempotencyKey(userID int64, key string) (*UrlMapEntity, error) {
+	return d.getBy("idempotency_key = ?", userID, key)
+}
+
+// getBy 在用户范围内按唯一索引查询一条记录，未找到时返回nil
+func (d *urlMapData) getBy(cond string, userID int64, arg any) (*UrlMapEntity, error) {
+	scope, args := d.userScope(userID)
+	sqlStr := fmt.Sprintf("select %s from %s where %s%s", d.entityColumns(), d.tableName, scope, cond)
+	entity := UrlMapEntity{}
+	err := d.db.QueryRow(sqlStr, append(args, arg)...).Scan(d.entityDest(&entity)...)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		d.log.Error(zerror.NewByErr(err))
-		return err
+		return nil, err
 	}
-	return nil
+	return &entity, nil
 }
 
-// GetByID 通过ID查询URL映射记录
+// userScope 返回限定用户范围的查询条件与参数，只有用户短链表有 user_id 字段
+func (d *urlMapData) userScope(userID int64) (string, []any) {
+	if d.tableName == constants.TABLENAME_URL_MAP_USER {
+		return "user_id = ? and ", []any{userID}
+	}
+	return "", nil
+}
+
+// ReleaseUrlHash 清除记录的原始URL哈希，已停用或已下架的短链被释放后，相同原始URL可以创建新的短链
 // 参数：
 //   - id: 记录ID
+//   - now: 当前时间戳
 //
 // 返回：
-//   - 查询到的实体对象（未找到时各字段为零值）
 //   - 错误信息（数据库操作失败时）
-func (d *urlMapData) GetByID(id int64) (*UrlMapEntity, error) {
-	sqlStr := fmt.Sprintf("select original_url from %s where id = ?", d.tableName)
-	row := d.db.QueryRow(sqlStr, id)
-	entity := UrlMapEntity{}
-	var originalUrl sql.NullString
-	err := row.Scan(&originalUrl)
-	// 特殊处理未找到的情况
-	if err != nil && errors.Is(err, sql.ErrNoRows) {
+func (d *urlMapData) ReleaseUrlHash(id int64, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set url_hash=null, update_at=? where id=?", d.tableName)
+	_, err := d.db.Exec(sqlStr, now, id)
+	if err != nil {
 		d.log.Error(zerror.NewByErr(err))
-		return &entity, err
+		return err
 	}
-	if originalUrl.Valid {
-		entity.OriginalUrl = originalUrl.String
+	return nil
+}
+
+// BatchCreate 使用一条多行 insert 批量创建URL映射记录
+// 参数：
+//   - entities: 需要创建的实体对象，ID 与 ShortKey 由调用方生成
+//
+// 返回：
+//   - 错误信息（数据库操作失败时，整批均未创建；原始URL已有可复用的短链时返回 ErrUrlHashExists）
+func (d *urlMapData) BatchCreate(entities []UrlMapEntity) error {
+	if len(entities) == 0 {
+		return nil
 	}
-	return &entity, nil
+	return d.insert(entities, false)
 }
 
-// GetByOriginal 通过原始URL查询映射记录
+// CreateAlias 使用自定义别名创建URL映射记录，别名同时作为短链键
+// 别名在表内唯一，公共短链与用户短链分别在各自的表内校验
 // 参数：
-//   - originalUrl: 原始URL
+//   - e: 需要创建的实体对象，ID 由调用方生成，ShortKey 为规范化后的别名
 //
 // 返回：
-//   - 查询到的实体对象（未找到时各字段为零值）
+//   - 错误信息（别名已被占用时返回 ErrAliasExists）
+func (d *urlMapData) CreateAlias(e UrlMapEntity) error {
+	return d.insert([]UrlMapEntity{e}, true)
+}
+
+// insert 写入URL映射记录，多条记录使用一条多行 insert
+// alias 为 true 时短链键同时写入 alias 字段；违反唯一索引时返回 duplicateErrors 中对应的错误，不记录日志
+func (d *urlMapData) insert(entities []UrlMapEntity, alias bool) error {
+	columns := "id,short_key,original_url,url_hash,idempotency_key,expire_at,max_clicks,create_at,update_at"
+	row := "?,?,?,?,?,?,?,?,?"
+	isUser := d.tableName == constants.TABLENAME_URL_MAP_USER
+	if isUser {
+		columns += ",user_id"
+		row += ",?"
+	}
+	if alias {
+		columns += ",alias"
+		row += ",?"
+	}
+	rows := make([]string, 0, len(entities))
+	args := make([]any, 0, len(entities)*11)
+	for _, e := range entities {
+		rows = append(rows, "("+row+")")
+		// 空值写入NULL，不参与唯一索引
+		var idempotencyKey any
+		if e.IdempotencyKey != "" {
+			idempotencyKey = e.IdempotencyKey
+		}
+		args = append(args, e.ID, e.ShortKey, e.OriginalUrl, e.UrlHash, idempotencyKey, e.ExpireAt, e.MaxClicks, e.CreateAt, e.UpdateAt)
+		if isUser {
+			args = append(args, e.UserID)
+		}
+		if alias {
+			args = append(args, e.ShortKey)
+		}
+	}
+	sqlStr := fmt.Sprintf("insert into %s (%s)values%s", d.tableName, columns, strings.Join(rows, ","))
+	_, err := d.db.Exec(sqlStr, args...)
+	if err != nil {
+		if dupErr := duplicateError(err); dupErr != nil {
+			return dupErr
+		}
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// duplicateError 违反唯一索引时返回 duplicateErrors 中对应的错误，其他错误返回nil
+func duplicateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		// 错误信息形如 Duplicate entry '...' for key 'url_map.unique_alias'
+		for index, dupErr := range duplicateErrors {
+			if strings.Contains(mysqlErr.Message, index+"'") {
+				return dupErr
+			}
+		}
+	}
+	return nil
+}
+
+// GetByAlias 通过自定义别名查询URL映射记录，包括已禁用的记录
+// 参数：
+//   - alias: 规范化后的别名
+//
+// 返回：
+//   - 查询到的实体对象（未找到时为nil）
 //   - 错误信息（数据库操作失败时）
-func (d *urlMapData) GetByOriginal(originalUrl string) (UrlMapEntity, error) {
-	sqlStr := fmt.Sprintf("select id, short_key from %s where original_url = ?", d.tableName)
-	row := d.db.QueryRow(sqlStr, originalUrl)
+func (d *urlMapData) GetByAlias(alias string) (*UrlMapEntity, error) {
+	sqlStr := fmt.Sprintf("select %s from %s where alias = ?", d.entityColumns(), d.tableName)
 	entity := UrlMapEntity{}
-	var shortKey sql.NullString
-	err := row.Scan(&entity.ID, &shortKey)
-	// 处理非预期错误
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	err := d.db.QueryRow(sqlStr, alias).Scan(d.entityDest(&entity)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		d.log.Error(zerror.NewByErr(err))
-		return entity, err
-	}
-	if shortKey.Valid {
-		entity.ShortKey = shortKey.String
+		return nil, err
 	}
-	return entity, nil
+	return &entity, nil
+}
+
+// IncrementTimesByAlias 增加自定义别名的访问次数
+// 参数：
+//   - alias: 规范化后的别名
+//   - incrementTimes: 需要增加的次数
+//   - now: 当前时间戳
+//
+// 返回：
+//   - 是否增加成功，短链已过期、已达到访问次数上限或不存在时为false
+//   - 错误信息（数据库操作失败时）
+func (d *urlMapData) IncrementTimesByAlias(alias string, incrementTimes int, now int64) (bool, error) {
+	return d.incrementTimes("alias = ?", alias, incrementTimes, now)
 }
 
 // IncrementTimes 增加指定记录的访问次数
@@ -155,27 +453,40 @@ func (d *urlMapData) GetByOriginal(originalUrl string) (UrlMapEntity, error) {
 //   - now: 当前时间戳
 //
 // 返回：
+//   - 是否增加成功，短链已过期、已达到访问次数上限或不存在时为false
 //   - 错误信息（数据库操作失败时）
-func (d *urlMapData) IncrementTimes(id int64, incrementTimes int, now int64) error {
-	sqlStr := fmt.Sprintf("update %s set times = times + ?, update_at=? where id = ?", d.tableName)
-	_, err := d.db.Exec(sqlStr, incrementTimes, now, id)
+func (d *urlMapData) IncrementTimes(id int64, incrementTimes int, now int64) (bool, error) {
+	return d.incrementTimes("id = ?", id, incrementTimes, now)
+}
+
+// incrementTimes 在同一条 update 中检查有效期与访问次数上限，并发访问时不会超过上限
+func (d *urlMapData) incrementTimes(cond string, arg any, incrementTimes int, now int64) (bool, error) {
+	sqlStr := fmt.Sprintf("update %s set times = times + ?, update_at=? where %s and (expire_at = 0 or expire_at > ?) and (max_clicks = 0 or times < max_clicks)",
+		d.tableName, cond)
+	res, err := d.db.Exec(sqlStr, incrementTimes, now, arg, now)
 	if err != nil {
 		d.log.Error(zerror.NewByErr(err))
-		return err
+		return false, err
 	}
-	return nil
+	n, err := res.RowsAffected()
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return false, err
+	}
+	return n > 0, nil
 }
 
 // GetTopUrls 获取访问次数最多的前N个URL映射记录
 // 参数：
 //   - limit: 需要获取的记录数量
+//   - now: 当前时间戳，已禁用和已过期的记录不返回
 //
 // 返回：
 //   - 访问次数最多的URL映射记录列表
 //   - 错误信息（数据库操作失败时）
-func (d *urlMapData) GetTopUrls(limit int) ([]UrlMapEntity, error) {
-	sqlStr := fmt.Sprintf("SELECT id, short_key, original_url, times, create_at, update_at FROM %s ORDER BY times DESC LIMIT ?", d.tableName)
-	rows, err := d.db.Query(sqlStr, limit)
+func (d *urlMapData) GetTopUrls(limit int, now int64) ([]UrlMapEntity, error) {
+	sqlStr := fmt.Sprintf("SELECT id, short_key, original_url, times, expire_at, max_clicks, create_at, update_at FROM %s WHERE status = ? AND (expire_at = 0 OR expire_at > ?) AND (max_clicks = 0 OR times < max_clicks) ORDER BY times DESC LIMIT ?", d.tableName)
+	rows, err := d.db.Query(sqlStr, constants.URL_STATUS_NORMAL, now, limit)
 	if err != nil {
 		d.log.Error(zerror.NewByErr(err))
 		return nil, err
@@ -185,7 +496,7 @@ func (d *urlMapData) GetTopUrls(limit int) ([]UrlMapEntity, error) {
 	var results []UrlMapEntity
 	for rows.Next() {
 		var entity UrlMapEntity
-		err := rows.Scan(&entity.ID, &entity.ShortKey, &entity.OriginalUrl, &entity.Times, &entity.CreateAt, &entity.UpdateAt)
+		err := rows.Scan(&entity.ID, &entity.ShortKey, &entity.OriginalUrl, &entity.Times, &entity.ExpireAt, &entity.MaxClicks, &entity.CreateAt, &entity.UpdateAt)
 		if err != nil {
 			d.log.Error(zerror.NewByErr(err))
 			return nil, err
@@ -200,3 +511,157 @@ func (d *urlMapData) GetTopUrls(limit int) ([]UrlMapEntity, error) {
 
 	return results, nil
 }
+
+// UpdateOriginalUrl 修改短链指向的原始URL
+// 参数：
+//   - id: 记录ID
+//   - originalUrl: 新的原始URL
+//   - now: 当前时间戳
+//
+// 返回：
+//   - 错误信息（数据库操作失败时）
+func (d *urlMapData) UpdateOriginalUrl(id int64, originalUrl string, now int64) error {
+	// 修改后的短链不再代表原来的原始URL，清除哈希使其不参与去重
+	sqlStr := fmt.Sprintf("update %s set original_url=?, url_hash=null, update_at=? where id=?", d.tableName)
+	_, err := d.db.Exec(sqlStr, originalUrl, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// SetStatus 修改短链状态
+// 参数：
+//   - id: 记录ID
+//   - status: 新状态，见 constants.URL_STATUS_*
+//   - now: 当前时间戳
+//
+// 返回：
+//   - 错误信息（数据库操作失败时）
+func (d *urlMapData) SetStatus(id int64, status int, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set status=?, update_at=? where id=?", d.tableName)
+	_, err := d.db.Exec(sqlStr, status, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// Delete 标记删除短链，保留记录用于统计与审计
+// 自定义别名字段置空，别名可被重新使用，short_key 保留原值
+// 参数：
+//   - id: 记录ID
+//   - now: 当前时间戳
+//
+// 返回：
+//   - 错误信息（数据库操作失败时）
+func (d *urlMapData) Delete(id int64, now int64) error {
+	sqlStr := fmt.Sprintf("update %s set status=?, alias=null, update_at=? where id=?", d.tableName)
+	_, err := d.db.Exec(sqlStr, constants.URL_STATUS_DELETED, now, id)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return err
+	}
+	return nil
+}
+
+// List 分页查询用户的短链，只用于用户短链表
+// 参数：
+//   - q: 查询条件
+//
+// 返回：
+//   - 当前页的记录
+//   - 符合条件的记录总数
+//   - 错误信息（数据库操作失败时）
+func (d *urlMapData) List(q *UrlMapQuery) ([]UrlMapEntity, int64, error) {
+	where, args := listWhere(q)
+
+	var total int64
+	sqlStr := fmt.Sprintf("select count(*) from %s where %s", d.tableName, where)
+	if err := d.db.QueryRow(sqlStr, args...).Scan(&total); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, 0, err
+	}
+
+	order := "id desc"
+	if q.Sort == ListSortClicks {
+		order = "times desc, id desc"
+	}
+	sqlStr = fmt.Sprintf("select %s from %s where %s order by %s limit ? offset ?", d.entityColumns(), d.tableName, where, order)
+	rows, err := d.db.Query(sqlStr, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := make([]UrlMapEntity, 0)
+	for rows.Next() {
+		var entity UrlMapEntity
+		if err = rows.Scan(d.entityDest(&entity)...); err != nil {
+			d.log.Error(zerror.NewByErr(err))
+			return nil, 0, err
+		}
+		results = append(results, entity)
+	}
+	if err = rows.Err(); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// listWhere 返回短链列表的查询条件与参数，已删除的短链不返回
+func listWhere(q *UrlMapQuery) (string, []any) {
+	conds := []string{"user_id = ?", "status <> ?"}
+	args := []any{q.UserID, constants.URL_STATUS_DELETED}
+	if q.CreateFrom > 0 {
+		conds = append(conds, "create_at >= ?")
+		args = append(args, q.CreateFrom)
+	}
+	if q.CreateTo > 0 {
+		conds = append(conds, "create_at < ?")
+		args = append(args, q.CreateTo)
+	}
+	if q.Domain != "" {
+		// 匹配 scheme://domain 及其后跟路径、端口的地址，不匹配子域名和同前缀的其他域名
+		conds = append(conds, "(original_url in (?, ?) or original_url like ? or original_url like ? or original_url like ? or original_url like ?)")
+		domain := escapeLike(q.Domain)
+		args = append(args, "http://"+q.Domain, "https://"+q.Domain,
+			"http://"+domain+"/%", "https://"+domain+"/%", "http://"+domain+":%", "https://"+domain+":%")
+	}
+	expired := "((expire_at > 0 and expire_at <= ?) or (max_clicks > 0 and times >= max_clicks))"
+	switch q.Status {
+	case ListStatusActive:
+		conds = append(conds, "status = ?", "not "+expired)
+		args = append(args, constants.URL_STATUS_NORMAL, q.Now)
+	case ListStatusExpired:
+		conds = append(conds, "status = ?", expired)
+		args = append(args, constants.URL_STATUS_NORMAL, q.Now)
+	case ListStatusPaused:
+		conds = append(conds, "status = ?")
+		args = append(args, constants.URL_STATUS_PAUSED)
+	case ListStatusDisabled:
+		conds = append(conds, "status = ?")
+		args = append(args, constants.URL_STATUS_DISABLED)
+	}
+	return strings.Join(conds, " and "), args
+}
+
+// escapeLike 转义 like 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// MaxID 查询表中的最大ID，表为空时返回0
+func (d *urlMapData) MaxID() (int64, error) {
+	sqlStr := fmt.Sprintf("select ifnull(max(id), 0) from %s", d.tableName)
+	var id int64
+	if err := d.db.QueryRow(sqlStr).Scan(&id); err != nil {
+		d.log.Error(zerror.NewByErr(err))
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,105 @@
+package data
+
+import (
+	"reflect"
+	"regexp"
+	"shorturl/pkg/constants"
+	"strings"
+	"testing"
+)
+
+func TestListWhereStatus(t *testing.T) {
+	const now = 1700000000
+	expired := "((expire_at > 0 and expire_at <= ?) or (max_clicks > 0 and times >= max_clicks))"
+	cases := []struct {
+		status string
+		where  string
+		args   []any
+	}{
+		{"", "user_id = ? and status <> ?", []any{int64(7), constants.URL_STATUS_DELETED}},
+		// 正常与过期都只包含未停用、未下架的短链，按有效期与访问次数区分
+		{ListStatusActive, "user_id = ? and status <> ? and status = ? and not " + expired,
+			[]any{int64(7), constants.URL_STATUS_DELETED, constants.URL_STATUS_NORMAL, int64(now)}},
+		{ListStatusExpired, "user_id = ? and status <> ? and status = ? and " + expired,
+			[]any{int64(7), constants.URL_STATUS_DELETED, constants.URL_STATUS_NORMAL, int64(now)}},
+		{ListStatusPaused, "user_id = ? and status <> ? and status = ?",
+			[]any{int64(7), constants.URL_STATUS_DELETED, constants.URL_STATUS_PAUSED}},
+		{ListStatusDisabled, "user_id = ? and status <> ? and status = ?",
+			[]any{int64(7), constants.URL_STATUS_DELETED, constants.URL_STATUS_DISABLED}},
+	}
+	for _, c := range cases {
+		where, args := listWhere(&UrlMapQuery{UserID: 7, Status: c.status, Now: now})
+		if where != c.where || !reflect.DeepEqual(args, c.args) {
+			t.Errorf("status %q: got %q %v, want %q %v", c.status, where, args, c.where, c.args)
+		}
+	}
+
+	where, args := listWhere(&UrlMapQuery{UserID: 7, CreateFrom: 10, CreateTo: 20})
+	if where != "user_id = ? and status <> ? and create_at >= ? and create_at < ?" ||
+		!reflect.DeepEqual(args, []any{int64(7), constants.URL_STATUS_DELETED, int64(10), int64(20)}) {
+		t.Errorf("create range: got %q %v", where, args)
+	}
+}
+
+func TestListWhereDomain(t *testing.T) {
+	cases := []struct {
+		domain string
+		url    string
+		match  bool
+	}{
+		{"example.com", "https://example.com", true},
+		{"example.com", "http://example.com/a?b=1", true},
+		{"example.com", "https://example.com:8443/a", true},
+		// 不匹配子域名、同前缀的其他域名与出现在路径中的域名
+		{"example.com", "https://www.example.com/", false},
+		{"example.com", "https://example.com.evil.net/", false},
+		{"example.com", "https://evil.net/example.com/", false},
+		// 域名中的 _ 和 % 按字面匹配
+		{"a_b.com", "https://a_b.com/", true},
+		{"a_b.com", "https://axb.com/", false},
+		{"a%b.com", "https://axyzb.com/", false},
+	}
+	for _, c := range cases {
+		where, args := listWhere(&UrlMapQuery{UserID: 7, Domain: c.domain})
+		if !strings.Contains(where, "original_url in (?, ?)") {
+			t.Fatalf("domain condition missing: %q", where)
+		}
+		if got := domainMatches(args[2:], c.url); got != c.match {
+			t.Errorf("domain %q url %q: match %v, want %v", c.domain, c.url, got, c.match)
+		}
+	}
+}
+
+// domainMatches 按 listWhere 的域名条件判断原始URL是否匹配：前两个参数精确匹配，其余参数按 like 匹配
+func domainMatches(args []any, url string) bool {
+	for i, a := range args {
+		if i < 2 && a == url {
+			return true
+		}
+		if i >= 2 && likePattern(a.(string)).MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// likePattern 将 MySQL like 模式转换为正则，\ 为转义字符
+func likePattern(p string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '\\' && i+1 < len(p):
+			i++
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		case c == '%':
+			b.WriteString("(?s:.*)")
+		case c == '_':
+			b.WriteString("(?s:.)")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
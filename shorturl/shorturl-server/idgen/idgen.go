@@ -0,0 +1,88 @@
+package idgen
+
+import (
+	"fmt"
+	"shorturl/pkg/config"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/db/redis"
+	"shorturl/pkg/log"
+	"shorturl/shorturl-server/data"
+)
+
+// ID生成方式
+const (
+	TypeSegment   = "segment"   // 号段预留自 MySQL 的 id_sequence 表
+	TypeRedis     = "redis"     // 号段预留自 Redis 的 INCRBY
+	TypeSnowflake = "snowflake" // 按时间戳、机器ID与序号生成，不访问存储
+)
+
+// defaultStep 默认号段长度
+const defaultStep = 1000
+
+// IDGenerator 短链记录ID生成器，生成的ID大于0且在同一张短链表内不重复
+type IDGenerator interface {
+	// NextID 生成一个新的ID
+	NextID() (int64, error)
+}
+
+// IDGeneratorFactory ID生成器工厂，每张短链表共用一个生成器
+type IDGeneratorFactory interface {
+	// Generator 返回短链表对应的ID生成器
+	Generator(isPublic bool) IDGenerator
+}
+
+type idGeneratorFactory struct {
+	public IDGenerator
+	user   IDGenerator
+}
+
+// NewIDGeneratorFactory 按配置创建ID生成器工厂
+// 参数:
+//
+//	cnf: 配置信息，见 Config.IDGenerator
+//	logger: 日志记录器
+//	sequenceData: MySQL 号段数据操作对象，segment 方式使用
+//	urlDataFactory: 短链数据工厂，redis 方式初始化序列时读取短链表的最大ID
+//	redisPool: Redis连接池，redis 方式使用
+//
+// 返回值:
+//
+//	IDGeneratorFactory: ID生成器工厂
+//	error: 配置非法时返回错误
+func NewIDGeneratorFactory(cnf *config.Config, logger log.ILogger, sequenceData data.IIdSequenceData, urlDataFactory data.IUrlMapDataFactory, redisPool redis.RedisPool) (IDGeneratorFactory, error) {
+	step := cnf.IDGenerator.Step
+	if step <= 0 {
+		step = defaultStep
+	}
+	switch cnf.IDGenerator.Type {
+	case "", TypeSegment:
+		return &idGeneratorFactory{
+			public: NewSegmentGenerator(logger, sequenceData, constants.TABLENAME_URL_MAP, step),
+			user:   NewSegmentGenerator(logger, sequenceData, constants.TABLENAME_URL_MAP_USER, step),
+		}, nil
+	case TypeRedis:
+		source := NewRedisRangeSource(redisPool, func(name string) (int64, error) {
+			return urlDataFactory.NewUrlMapData(name == constants.TABLENAME_URL_MAP).MaxID()
+		})
+		return &idGeneratorFactory{
+			public: NewSegmentGenerator(logger, source, constants.TABLENAME_URL_MAP, step),
+			user:   NewSegmentGenerator(logger, source, constants.TABLENAME_URL_MAP_USER, step),
+		}, nil
+	case TypeSnowflake:
+		// 两张表共用一个生成器，ID在两张表之间也不重复
+		g, err := NewSnowflakeGenerator(cnf.IDGenerator.WorkerID)
+		if err != nil {
+			return nil, err
+		}
+		return &idGeneratorFactory{public: g, user: g}, nil
+	}
+	return nil, fmt.Errorf("unknown id generator type: %s", cnf.IDGenerator.Type)
+}
+
+// Generator 返回短链表对应的ID生成器
+func (f *idGeneratorFactory) Generator(isPublic bool) IDGenerator {
+	if isPublic {
+		return f.public
+	}
+	return f.user
+}
@@ -0,0 +1,85 @@
+package idgen
+
+import (
+	"shorturl/pkg/log"
+	"sync"
+	"testing"
+)
+
+// memoryRangeSource 内存中的号段来源
+type memoryRangeSource struct {
+	mu    sync.Mutex
+	max   int64
+	calls int
+}
+
+func (s *memoryRangeSource) NextRange(name string, step int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.max += step
+	s.calls++
+	return s.max, nil
+}
+
+func TestSegmentGenerator(t *testing.T) {
+	source := &memoryRangeSource{max: 100}
+	g := NewSegmentGenerator(log.NewLogger(), source, "url_map", 10)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id, err := g.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if id <= 100 || seen[id] {
+					t.Errorf("unexpected id %d", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 800 {
+		t.Errorf("expected 800 ids, got %d", len(seen))
+	}
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	if _, err := NewSnowflakeGenerator(maxWorkerID + 1); err == nil {
+		t.Error("expected error for invalid worker id")
+	}
+	gen, _ := NewSnowflakeGenerator(3)
+	g := gen.(*snowflakeGenerator)
+	ms := int64(snowflakeEpoch + 1000)
+	g.now = func() int64 { return ms }
+
+	// 同一毫秒内的序号用完后等待下一毫秒
+	var last int64
+	for i := 0; i <= maxSequence; i++ {
+		id, err := g.NextID()
+		if err != nil || id <= last {
+			t.Fatalf("NextID() = %d, %v after %d", id, err, last)
+		}
+		last = id
+	}
+	g.now = func() int64 { ms++; return ms }
+	id, err := g.NextID()
+	if err != nil || id <= last || (id>>sequenceBits)&maxWorkerID != 3 {
+		t.Fatalf("NextID() = %d, %v", id, err)
+	}
+
+	// 时钟大幅回拨时返回错误
+	g.now = func() int64 { return g.lastMs - maxClockBackwards - 1 }
+	if _, err = g.NextID(); err != ErrClockBackwards {
+		t.Errorf("expected ErrClockBackwards, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package idgen
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	pkgredis "shorturl/pkg/db/redis"
+	"shorturl/pkg/log"
+	"sync"
+)
+
+// RangeSource 号段来源
+type RangeSource interface {
+	// NextRange 预留 step 个连续ID，返回号段的最大ID，号段为 (max-step, max]
+	NextRange(name string, step int64) (int64, error)
+}
+
+// segmentGenerator 号段ID生成器，每次从号段来源预留一段ID在内存中分配
+// 当前号段用掉80%后在后台预留下一个号段，当前号段用完时直接切换，分配ID时通常不访问存储；
+// 服务重启时未分配完的号段被丢弃，ID有空洞但不会重复
+type segmentGenerator struct {
+	log    log.ILogger
+	source RangeSource
+	name   string
+	step   int64
+
+	mu      sync.Mutex
+	next    int64 // 当前号段下一个可分配的ID
+	max     int64 // 当前号段的最大ID
+	bufNext int64 // 预留的下一个号段，bufMax 为0时表示没有
+	bufMax  int64
+	loading bool
+}
+
+// NewSegmentGenerator 创建号段ID生成器
+// 参数:
+//
+//	logger: 日志记录器
+//	source: 号段来源
+//	name: 序列名称，使用短链表名
+//	step: 号段长度
+func NewSegmentGenerator(logger log.ILogger, source RangeSource, name string, step int64) IDGenerator {
+	return &segmentGenerator{
+		log:    logger,
+		source: source,
+		name:   name,
+		step:   step,
+	}
+}
+
+// NextID 从当前号段分配一个ID，号段用完时切换到预留的号段或同步预留新号段
+func (g *segmentGenerator) NextID() (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.next == 0 || g.next > g.max {
+		if g.bufMax > 0 {
+			g.next, g.max = g.bufNext, g.bufMax
+			g.bufMax = 0
+		} else {
+			max, err := g.source.NextRange(g.name, g.step)
+			if err != nil {
+				return 0, err
+			}
+			g.next, g.max = max-g.step+1, max
+		}
+	}
+	id := g.next
+	g.next++
+
+	if !g.loading && g.bufMax == 0 && g.max-g.next < g.step/5 {
+		g.loading = true
+		go g.preload()
+	}
+	return id, nil
+}
+
+// preload 在后台预留下一个号段
+func (g *segmentGenerator) preload() {
+	max, err := g.source.NextRange(g.name, g.step)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.loading = false
+	if err != nil {
+		g.log.Warning("预留ID号段失败: " + err.Error())
+		return
+	}
+	g.bufNext, g.bufMax = max-g.step+1, max
+}
+
+// redisRangeSource 基于 Redis INCRBY 的号段来源
+// Redis 需要开启持久化，序列丢失后会从短链表的最大ID重新开始，其他实例尚未用完的号段可能被重复分配，
+// 此时写入会因主键冲突失败而不会覆盖已有记录
+type redisRangeSource struct {
+	redisPool pkgredis.RedisPool
+	initial   func(name string) (int64, error)
+	inited    sync.Map
+}
+
+// NewRedisRangeSource 创建基于 Redis 的号段来源
+// 参数:
+//
+//	redisPool: Redis连接池
+//	initial: 序列不存在时的初始值，使用短链表的最大ID
+func NewRedisRangeSource(redisPool pkgredis.RedisPool, initial func(name string) (int64, error)) RangeSource {
+	return &redisRangeSource{
+		redisPool: redisPool,
+		initial:   initial,
+	}
+}
+
+// NextRange 使用 INCRBY 预留号段，每个进程首次使用序列时用 SETNX 初始化
+func (s *redisRangeSource) NextRange(name string, step int64) (int64, error) {
+	client := s.redisPool.Get()
+	defer s.redisPool.Put(client)
+
+	key := pkgredis.GetKey("id_sequence", name)
+	if _, ok := s.inited.Load(name); !ok {
+		if err := s.init(client, key, name); err != nil {
+			return 0, err
+		}
+		s.inited.Store(name, struct{}{})
+	}
+	return client.IncrBy(context.Background(), key, step).Result()
+}
+
+// init 序列不存在时以短链表的最大ID初始化，已存在时不修改
+func (s *redisRangeSource) init(client *redis.Client, key, name string) error {
+	exists, err := client.Exists(context.Background(), key).Result()
+	if err != nil || exists > 0 {
+		return err
+	}
+	id, err := s.initial(name)
+	if err != nil {
+		return err
+	}
+	return client.SetNX(context.Background(), key, id, 0).Err()
+}
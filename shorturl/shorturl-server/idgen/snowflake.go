@@ -0,0 +1,78 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// snowflake ID 的组成：41位毫秒时间戳（相对 snowflakeEpoch）、10位机器ID、12位序号
+const (
+	snowflakeEpoch    = 1704067200000 // 2024-01-01 00:00:00 UTC，毫秒
+	workerIDBits      = 10
+	sequenceBits      = 12
+	maxWorkerID       = 1<<workerIDBits - 1
+	maxSequence       = 1<<sequenceBits - 1
+	maxClockBackwards = 10 // 可等待的时钟回拨毫秒数，超过时返回错误
+)
+
+// ErrClockBackwards 系统时钟回拨超过可等待的范围
+var ErrClockBackwards = errors.New("系统时钟回拨，暂停生成ID")
+
+// snowflakeGenerator Snowflake ID生成器，同一毫秒内最多生成4096个ID，超过时等待下一毫秒
+type snowflakeGenerator struct {
+	mu       sync.Mutex
+	workerID int64
+	lastMs   int64
+	sequence int64
+	now      func() int64 // 返回当前毫秒时间戳，测试时替换
+}
+
+// NewSnowflakeGenerator 创建 Snowflake ID生成器
+// 参数:
+//
+//	workerID: 机器ID，0~1023，各实例不能重复
+func NewSnowflakeGenerator(workerID int64) (IDGenerator, error) {
+	if workerID < 0 || workerID > maxWorkerID {
+		return nil, fmt.Errorf("snowflake worker id must be between 0 and %d", maxWorkerID)
+	}
+	return &snowflakeGenerator{
+		workerID: workerID,
+		now:      func() int64 { return time.Now().UnixMilli() },
+	}, nil
+}
+
+// NextID 生成一个新的ID
+func (g *snowflakeGenerator) NextID() (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if now < g.lastMs {
+		if g.lastMs-now > maxClockBackwards {
+			return 0, ErrClockBackwards
+		}
+		now = g.waitUntil(g.lastMs)
+	}
+	if now == g.lastMs {
+		g.sequence = (g.sequence + 1) & maxSequence
+		if g.sequence == 0 {
+			now = g.waitUntil(g.lastMs + 1)
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMs = now
+	return (now-snowflakeEpoch)<<(workerIDBits+sequenceBits) | g.workerID<<sequenceBits | g.sequence, nil
+}
+
+// waitUntil 等待到指定的毫秒时间戳
+func (g *snowflakeGenerator) waitUntil(ms int64) int64 {
+	now := g.now()
+	for now < ms {
+		time.Sleep(time.Duration(ms-now) * time.Millisecond)
+		now = g.now()
+	}
+	return now
+}
@@ -0,0 +1,77 @@
+package interceptor
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"shorturl/pkg/audit"
+	"strconv"
+	"sync/atomic"
+)
+
+// 调用方转发的终端用户信息，用于审计记录
+const (
+	ClientIPMetadataKey        = "x-client-ip"
+	ClientUserAgentMetadataKey = "x-client-user-agent"
+)
+
+var recorder atomic.Pointer[audit.Recorder]
+
+// SetRecorder 设置审计记录器，未设置时不记录
+func SetRecorder(r *audit.Recorder) {
+	recorder.Store(r)
+}
+
+// Audit 写入审计记录
+func Audit(rec *audit.Record) {
+	recorder.Load().Record(rec)
+}
+
+// AuditRecord 按请求上下文创建审计记录
+// 操作者优先取调用方转发的操作用户，其次取 userID，都没有时为调用方本身；
+// 客户端IP与 User-Agent 优先取调用方转发的终端用户信息，其次取连接的对端地址与 gRPC User-Agent
+func AuditRecord(ctx context.Context, action, outcome string, userID int64) *audit.Record {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	rec := &audit.Record{
+		ActorType: audit.ActorAnonymous,
+		Action:    action,
+		IP:        first(ClientIPMetadataKey),
+		UserAgent: first(ClientUserAgentMetadataKey),
+		Outcome:   outcome,
+	}
+	if c, ok := ClientFromContext(ctx); ok {
+		rec.Client = c.Name
+		rec.ActorType = audit.ActorClient
+		rec.ActorName = c.Name
+	}
+	if v := first(UserIDMetadataKey); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil && id != 0 {
+			userID = id
+		}
+	}
+	if userID != 0 {
+		rec.ActorType = audit.ActorUser
+		rec.ActorID = userID
+		rec.ActorName = ""
+	}
+	if rec.IP == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			rec.IP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(rec.IP); err == nil {
+				rec.IP = host
+			}
+		}
+	}
+	if rec.UserAgent == "" {
+		rec.UserAgent = first("user-agent")
+	}
+	return rec
+}
@@ -0,0 +1,29 @@
+package interceptor
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"shorturl/pkg/audit"
+	"testing"
+)
+
+func TestAuditRecord(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 5000}})
+	ctx = withClient(ctx, &Client{Name: "mediahub"})
+
+	// 没有转发信息时操作者为调用方，IP取对端地址
+	rec := AuditRecord(ctx, audit.ActionLinkCreate, audit.OutcomeSuccess, 0)
+	if rec.ActorType != audit.ActorClient || rec.ActorName != "mediahub" || rec.IP != "10.0.0.2" {
+		t.Errorf("client record: %+v", rec)
+	}
+
+	// 转发的操作用户与终端用户信息优先
+	md := metadata.Pairs(UserIDMetadataKey, "42", ClientIPMetadataKey, "203.0.113.9", ClientUserAgentMetadataKey, "browser")
+	rec = AuditRecord(metadata.NewIncomingContext(ctx, md), audit.ActionLinkPurge, audit.OutcomeSuccess, 7)
+	if rec.ActorType != audit.ActorUser || rec.ActorID != 42 || rec.Client != "mediahub" ||
+		rec.IP != "203.0.113.9" || rec.UserAgent != "browser" {
+		t.Errorf("forwarded record: %+v", rec)
+	}
+}
@@ -2,52 +2,101 @@ package interceptor
 
 import (
 	"context"
-	"fmt"
+	"crypto/x509"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
-	"shorturl/pkg/config"
-	"shorturl/pkg/zerror"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/audit"
+	"shorturl/pkg/log"
 	"strings"
 )
 
+// healthCheckMethod 健康检查接口，不需要认证
+const healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+// UnaryAuthInterceptor 认证调用方并检查权限范围，调用方写入请求上下文
 func UnaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-	// todo 如果做了健康检查，要调过健康检查
-	fmt.Println(info.FullMethod)
-	if info.FullMethod != "/grpc.health.v1.Health/Check" {
-		err = oauth2Valid(ctx)
-		if err != nil {
-			return nil, err
-		}
+	if info.FullMethod == healthCheckMethod {
+		return handler(ctx, req)
+	}
+	client, err := oauth2Valid(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
 	}
 
-	return handler(ctx, req)
+	return handler(withClient(ctx, client), req)
 }
 
+// StreamAuthInterceptor 流式接口的认证，调用方通过包装后的 ServerStream 写入上下文
 func StreamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	err := oauth2Valid(ss.Context())
+	client, err := oauth2Valid(ss.Context(), info.FullMethod)
 	if err != nil {
 		return err
 	}
 
-	return handler(srv, ss)
+	return handler(srv, &clientStream{ServerStream: ss, ctx: withClient(ss.Context(), client)})
 }
 
-func oauth2Valid(ctx context.Context) error {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return zerror.NewByMsg("元数据获取失败，身份认证失败")
+// clientStream 携带调用方信息的 ServerStream
+type clientStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clientStream) Context() context.Context {
+	return s.ctx
+}
+
+// oauth2Valid 认证调用方，并检查调用方是否有权调用该接口
+// 请求携带 Authorization 时使用令牌认证，否则使用 mTLS 客户端证书认证
+func oauth2Valid(ctx context.Context, fullMethod string) (*Client, error) {
+	r := registry.Load()
+	if r == nil {
+		return nil, status.Error(codes.Unauthenticated, "身份认证失败")
 	}
+
+	var client *Client
+	var err error
+	md, _ := metadata.FromIncomingContext(ctx)
 	//authorization := md["Authorization"] 同
 	authorization := md.Get("Authorization")
-	if len(authorization) < 1 {
-		return zerror.NewByMsg("元数据获取失败，身份认证失败")
+	if len(authorization) > 0 {
+		token := strings.TrimPrefix(authorization[0], "Bearer ")
+		client, err = r.Authenticate(token)
+	} else if cert := peerCertificate(ctx); cert != nil {
+		client, err = r.AuthenticateCert(cert)
+	} else {
+		Audit(AuditRecord(ctx, audit.ActionAuthFailure, audit.OutcomeFailure, 0).Target("method", fullMethod).WithDetail("没有凭证"))
+		return nil, status.Error(codes.Unauthenticated, "元数据获取失败，身份认证失败")
+	}
+	if err != nil {
+		log.WithFields(map[string]any{"method": fullMethod}).Warning("身份认证失败：", err)
+		Audit(AuditRecord(ctx, audit.ActionAuthFailure, audit.OutcomeFailure, 0).Target("method", fullMethod).WithDetail(err.Error()))
+		return nil, status.Error(codes.Unauthenticated, "身份认证失败")
 	}
 
-	token := strings.TrimPrefix(authorization[0], "Bearer ")
-	cnf := config.GetConfig()
-	if cnf.Server.AccessToken != token {
-		return zerror.NewByMsg("身份认证失败")
+	logger := log.WithFields(map[string]any{"client": client.Name, "method": fullMethod})
+	if !client.Allow(fullMethod) {
+		logger.Warning("调用方无权调用该接口")
+		Audit(AuditRecord(withClient(ctx, client), audit.ActionAccessDenied, audit.OutcomeDenied, 0).Target("method", fullMethod).WithDetail("调用方权限范围不足"))
+		return nil, status.Error(codes.PermissionDenied, "无权调用该接口")
 	}
+	logger.Debug("grpc call")
+	return client, nil
+}
 
-	return nil
+// peerCertificate 返回 TLS 握手时已通过 CA 校验的客户端证书，未使用 mTLS 时返回nil
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
 }
@@ -0,0 +1,119 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/config"
+	"shorturl/proto"
+	"testing"
+	"time"
+)
+
+func testConfig() *config.Config {
+	cnf := &config.Config{}
+	cnf.Server.AccessToken = "legacy"
+	cnf.Clients = []config.Client{
+		{Name: "proxy", Tokens: []string{"proxy-old", "proxy-new"}, Scopes: []string{ScopeResolve}, CertNames: []string{"proxy.internal"}},
+		{Name: "mediahub", JwtSecret: "secret", Scopes: []string{ScopeCreate, ScopeResolve}},
+	}
+	return cnf
+}
+
+func call(token, method string) (*Client, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("Authorization", "Bearer "+token))
+	var got *Client
+	_, err := UnaryAuthInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+		got, _ = ClientFromContext(ctx)
+		return nil, nil
+	})
+	return got, err
+}
+
+func signJwt(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	LoadClients(testConfig())
+
+	// 轮换期间新旧令牌都可以使用
+	for _, token := range []string{"proxy-old", "proxy-new"} {
+		c, err := call(token, proto.ShortUrl_GetOriginalUrl_FullMethodName)
+		if err != nil || c == nil || c.Name != "proxy" {
+			t.Fatalf("token %s: client %v, err %v", token, c, err)
+		}
+	}
+	if _, err := call("proxy-new", proto.ShortUrl_GetShortUrl_FullMethodName); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("proxy create: expected PermissionDenied, got %v", err)
+	}
+	if _, err := call("wrong", proto.ShortUrl_GetOriginalUrl_FullMethodName); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("wrong token: expected Unauthenticated, got %v", err)
+	}
+	if c, err := call("legacy", proto.ShortUrl_UpdateTarget_FullMethodName); err != nil || c.Name != defaultClientName {
+		t.Errorf("legacy token: client %v, err %v", c, err)
+	}
+
+	token := signJwt(t, "secret", jwt.MapClaims{"sub": "mediahub", "exp": time.Now().Add(time.Minute).Unix()})
+	if c, err := call(token, proto.ShortUrl_GetShortUrl_FullMethodName); err != nil || c.Name != "mediahub" {
+		t.Errorf("jwt: client %v, err %v", c, err)
+	}
+	// 用其他调用方的名称冒充，签名校验不通过
+	token = signJwt(t, "secret", jwt.MapClaims{"sub": "proxy", "exp": time.Now().Add(time.Minute).Unix()})
+	if _, err := call(token, proto.ShortUrl_GetOriginalUrl_FullMethodName); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("jwt wrong sub: expected Unauthenticated, got %v", err)
+	}
+	token = signJwt(t, "secret", jwt.MapClaims{"sub": "mediahub"})
+	if _, err := call(token, proto.ShortUrl_GetShortUrl_FullMethodName); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("jwt without exp: expected Unauthenticated, got %v", err)
+	}
+
+	// 重新加载后旧令牌失效
+	cnf := testConfig()
+	cnf.Clients[0].Tokens = []string{"proxy-new"}
+	LoadClients(cnf)
+	if _, err := call("proxy-old", proto.ShortUrl_GetOriginalUrl_FullMethodName); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("revoked token: expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestPeerCertificateAuth(t *testing.T) {
+	LoadClients(testConfig())
+
+	withCert := func(cn string) context.Context {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		info := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		c, _ := ClientFromContext(ctx)
+		return c, nil
+	}
+
+	resp, err := UnaryAuthInterceptor(withCert("proxy.internal"), nil, &grpc.UnaryServerInfo{FullMethod: proto.ShortUrl_GetOriginalUrl_FullMethodName}, handler)
+	if err != nil || resp.(*Client).Name != "proxy" {
+		t.Fatalf("cert auth: client %v, err %v", resp, err)
+	}
+	if _, err = UnaryAuthInterceptor(withCert("proxy.internal"), nil, &grpc.UnaryServerInfo{FullMethod: proto.ShortUrl_GetShortUrl_FullMethodName}, handler); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("cert create: expected PermissionDenied, got %v", err)
+	}
+	if _, err = UnaryAuthInterceptor(withCert("unknown"), nil, &grpc.UnaryServerInfo{FullMethod: proto.ShortUrl_GetOriginalUrl_FullMethodName}, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unknown cert: expected Unauthenticated, got %v", err)
+	}
+	if _, err = UnaryAuthInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: proto.ShortUrl_GetOriginalUrl_FullMethodName}, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("no credentials: expected Unauthenticated, got %v", err)
+	}
+}
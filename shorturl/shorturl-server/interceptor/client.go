@@ -0,0 +1,181 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"shorturl/pkg/config"
+	"shorturl/proto"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 权限范围
+const (
+	ScopeAll     = "*"
+	ScopeCreate  = "url:create"  // 生成短链、修改跳转目标、停用/启用/删除用户短链
+	ScopeResolve = "url:resolve" // 解析短链
+	ScopeRead    = "url:read"    // 查询短链列表与详情
+	ScopeAdmin   = "url:admin"   // 调用管理接口，还需要操作用户拥有相应权限
+	ScopeRbac    = "rbac:read"   // 查询用户的管理权限
+)
+
+// methodScopes 接口所需的权限范围，未列出的接口只允许拥有 * 的调用方访问
+var methodScopes = map[string]string{
+	proto.ShortUrl_GetShortUrl_FullMethodName:         ScopeCreate,
+	proto.ShortUrl_BatchGetShortUrl_FullMethodName:    ScopeCreate,
+	proto.ShortUrl_UpdateTarget_FullMethodName:        ScopeCreate,
+	proto.ShortUrl_DisableShortUrl_FullMethodName:     ScopeCreate,
+	proto.ShortUrl_EnableShortUrl_FullMethodName:      ScopeCreate,
+	proto.ShortUrl_DeleteShortUrl_FullMethodName:      ScopeCreate,
+	proto.ShortUrl_GetOriginalUrl_FullMethodName:      ScopeResolve,
+	proto.ShortUrl_BatchGetOriginalUrl_FullMethodName: ScopeResolve,
+	proto.ShortUrl_ListShortUrls_FullMethodName:       ScopeRead,
+	proto.ShortUrl_GetShortUrlInfo_FullMethodName:     ScopeRead,
+	proto.ShortUrl_PurgeShortUrl_FullMethodName:       ScopeAdmin,
+	proto.ShortUrl_GetUserPermissions_FullMethodName:  ScopeRbac,
+}
+
+// defaultClientName 旧配置 Server.AccessToken 对应的调用方名称
+const defaultClientName = "default"
+
+var errUnknownClient = errors.New("unknown client")
+
+// Client 已认证的调用方
+type Client struct {
+	Name   string
+	Scopes []string
+}
+
+// Allow 判断调用方是否可以调用指定接口
+func (c *Client) Allow(fullMethod string) bool {
+	need, ok := methodScopes[fullMethod]
+	for _, s := range c.Scopes {
+		if s == ScopeAll || (ok && s == need) {
+			return true
+		}
+	}
+	return false
+}
+
+type clientKey struct{}
+
+// ClientFromContext 获取请求的调用方，未认证时返回 false
+func ClientFromContext(ctx context.Context) (*Client, bool) {
+	c, ok := ctx.Value(clientKey{}).(*Client)
+	return c, ok
+}
+
+func withClient(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// clientToken 调用方的一个静态令牌
+type clientToken struct {
+	token  []byte
+	client *Client
+}
+
+// ClientRegistry 调用方注册表，由配置构建，配置变更时整体替换
+type ClientRegistry struct {
+	tokens     []clientToken
+	jwtSecrets map[string][]byte  // 调用方名称 -> JWT 密钥
+	certNames  map[string]*Client // 客户端证书名称 -> 调用方
+	clients    map[string]*Client
+}
+
+// NewClientRegistry 根据配置创建调用方注册表
+func NewClientRegistry(cnf *config.Config) *ClientRegistry {
+	r := &ClientRegistry{
+		jwtSecrets: map[string][]byte{},
+		certNames:  map[string]*Client{},
+		clients:    map[string]*Client{},
+	}
+	if cnf.Server.AccessToken != "" {
+		c := &Client{Name: defaultClientName, Scopes: []string{ScopeAll}}
+		r.clients[c.Name] = c
+		r.tokens = append(r.tokens, clientToken{token: []byte(cnf.Server.AccessToken), client: c})
+	}
+	for _, cc := range cnf.Clients {
+		if cc.Name == "" {
+			continue
+		}
+		c := &Client{Name: cc.Name, Scopes: cc.Scopes}
+		r.clients[c.Name] = c
+		for _, t := range cc.Tokens {
+			if t != "" {
+				r.tokens = append(r.tokens, clientToken{token: []byte(t), client: c})
+			}
+		}
+		if cc.JwtSecret != "" {
+			r.jwtSecrets[c.Name] = []byte(cc.JwtSecret)
+		}
+		for _, n := range cc.CertNames {
+			if n != "" {
+				r.certNames[n] = c
+			}
+		}
+	}
+	return r
+}
+
+// Authenticate 校验令牌并返回调用方，令牌为 JWT 时按 sub 查找调用方的密钥校验签名
+func (r *ClientRegistry) Authenticate(token string) (*Client, error) {
+	if strings.Count(token, ".") == 2 {
+		return r.authenticateJwt(token)
+	}
+	// 逐个比较全部令牌，耗时与令牌内容无关
+	var found *Client
+	for _, t := range r.tokens {
+		if subtle.ConstantTimeCompare(t.token, []byte(token)) == 1 {
+			found = t.client
+		}
+	}
+	if found == nil {
+		return nil, errUnknownClient
+	}
+	return found, nil
+}
+
+func (r *ClientRegistry) authenticateJwt(token string) (*Client, error) {
+	var client *Client
+	_, err := jwt.Parse(token, func(t *jwt.Token) (any, error) {
+		sub, err := t.Claims.GetSubject()
+		if err != nil {
+			return nil, err
+		}
+		secret, ok := r.jwtSecrets[sub]
+		if !ok {
+			return nil, errUnknownClient
+		}
+		client = r.clients[sub]
+		return secret, nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired(), jwt.WithLeeway(30*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// AuthenticateCert 根据已校验的客户端证书查找调用方，依次匹配 CN 与 DNS SAN
+func (r *ClientRegistry) AuthenticateCert(cert *x509.Certificate) (*Client, error) {
+	if c, ok := r.certNames[cert.Subject.CommonName]; ok {
+		return c, nil
+	}
+	for _, n := range cert.DNSNames {
+		if c, ok := r.certNames[n]; ok {
+			return c, nil
+		}
+	}
+	return nil, errUnknownClient
+}
+
+var registry atomic.Pointer[ClientRegistry]
+
+// LoadClients 加载调用方配置，替换当前使用的注册表
+func LoadClients(cnf *config.Config) {
+	registry.Store(NewClientRegistry(cnf))
+}
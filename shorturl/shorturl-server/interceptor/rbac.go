@@ -0,0 +1,62 @@
+package interceptor
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/audit"
+	"shorturl/pkg/log"
+	"shorturl/pkg/rbac"
+	"shorturl/proto"
+	"strconv"
+)
+
+// UserIDMetadataKey 调用方转发的操作用户ID，调用方已通过认证，服务端信任其转发的用户
+const UserIDMetadataKey = "x-user-id"
+
+// methodPermissions 管理接口需要操作用户拥有的权限
+var methodPermissions = map[string]string{
+	proto.ShortUrl_PurgeShortUrl_FullMethodName: rbac.PermShortUrlPurge,
+}
+
+type operatorKey struct{}
+
+// OperatorFromContext 获取管理接口的操作用户ID
+func OperatorFromContext(ctx context.Context) int64 {
+	id, _ := ctx.Value(operatorKey{}).(int64)
+	return id
+}
+
+// NewRbacInterceptor 创建管理接口的权限校验拦截器，需要放在认证拦截器之后
+func NewRbacInterceptor(enforcer *rbac.Enforcer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		perm, ok := methodPermissions[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		var userID int64
+		if v := md.Get(UserIDMetadataKey); len(v) > 0 {
+			userID, _ = strconv.ParseInt(v[0], 10, 64)
+		}
+		fields := map[string]any{"method": info.FullMethod, "user_id": userID}
+		if c, ok := ClientFromContext(ctx); ok {
+			fields["client"] = c.Name
+		}
+		allowed, err := enforcer.Allow(userID, perm)
+		if err != nil {
+			log.WithFields(fields).Error(err)
+			return nil, status.Error(codes.Unavailable, "权限查询失败")
+		}
+		if !allowed {
+			log.WithFields(fields).Warning("操作用户没有权限：", perm)
+			Audit(AuditRecord(ctx, audit.ActionAccessDenied, audit.OutcomeDenied, userID).Target("method", info.FullMethod).WithDetail("缺少权限 " + perm))
+			return nil, status.Error(codes.PermissionDenied, "没有权限")
+		}
+		log.WithFields(fields).Info("管理操作")
+		return handler(context.WithValue(ctx, operatorKey{}, userID), req)
+	}
+}
@@ -0,0 +1,50 @@
+package interceptor
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/rbac"
+	"shorturl/proto"
+	"testing"
+	"time"
+)
+
+type staticStore map[int64][]string
+
+func (s staticStore) UserPermissions(userID int64) ([]string, error) {
+	return s[userID], nil
+}
+
+func TestRbacInterceptor(t *testing.T) {
+	enforcer := rbac.NewEnforcer(staticStore{1: {"shorturl:*"}, 2: {rbac.PermStatsView}}, time.Minute)
+	intercept := NewRbacInterceptor(enforcer)
+	call := func(userID string, method string) (int64, error) {
+		ctx := context.Background()
+		if userID != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(UserIDMetadataKey, userID))
+		}
+		var operator int64
+		_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			operator = OperatorFromContext(ctx)
+			return nil, nil
+		})
+		return operator, err
+	}
+
+	if operator, err := call("1", proto.ShortUrl_PurgeShortUrl_FullMethodName); err != nil || operator != 1 {
+		t.Errorf("admin: operator %d, err %v", operator, err)
+	}
+	if _, err := call("2", proto.ShortUrl_PurgeShortUrl_FullMethodName); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("no permission: expected PermissionDenied, got %v", err)
+	}
+	if _, err := call("", proto.ShortUrl_PurgeShortUrl_FullMethodName); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("no operator: expected PermissionDenied, got %v", err)
+	}
+	// 非管理接口不检查操作用户
+	if _, err := call("", proto.ShortUrl_GetOriginalUrl_FullMethodName); err != nil {
+		t.Errorf("normal method: %v", err)
+	}
+}
@@ -1,21 +1,29 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"net"
+	"shorturl/pkg/audit"
 	"shorturl/pkg/config"
 	"shorturl/pkg/db/mysql"
 	"shorturl/pkg/db/redis"
 	"shorturl/pkg/log"
+	"shorturl/pkg/rbac"
+	"shorturl/pkg/tlsutil"
+	"shorturl/pkg/utils"
 	"shorturl/proto"
 	"shorturl/shorturl-server/cache"
 	"shorturl/shorturl-server/data"
+	"shorturl/shorturl-server/idgen"
 	"shorturl/shorturl-server/interceptor"
 	"shorturl/shorturl-server/server"
+	"time"
 )
 
 var (
@@ -45,6 +53,10 @@ func main() {
 	mysql.InitMysql(cnf)
 	// 创建基于MySQL的URL映射数据工厂
 	urlMapDataFactory := data.NewUrlMapDataFactory(logger, mysql.GetDB())
+	// 启用短链键置换但未配置旧短链键长度时拒绝启动，避免已签发的短链键失效
+	if err := checkShortKey(cnf, urlMapDataFactory); err != nil {
+		log.Fatal(err)
+	}
 
 	// 初始化Redis连接池
 	redis.InitRedisPool(cnf)
@@ -65,15 +77,50 @@ func main() {
 	// 创建两级缓存工厂
 	kvCacheFactory := cache.NewTwoLevelCacheFactory(localCache, redisCacheFactory, lockFactory)
 
+	// 订阅本地缓存失效通知，短链被禁用或修改后各实例同步删除本地缓存
+	cacheInvalidator := cache.NewRedisCacheInvalidator(logger, redisPool, localCache)
+	cacheInvalidator.Subscribe(context.Background())
+
 	// 绑定并监听指定的网络地址和端口
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cnf.Server.IP, cnf.Server.Port))
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// 审计记录异步批量写入 MySQL，与 mediahub 共用审计表
+	recorder := audit.NewRecorder("shorturl", data.NewAuditData(logger, mysql.GetDB()), func(err error) { log.Error(err) })
+	interceptor.SetRecorder(recorder)
+
+	// 加载调用方令牌，配置文件修改后自动重新加载，轮换令牌无需重启
+	interceptor.LoadClients(cnf)
+	config.OnChange(interceptor.LoadClients)
+
 	// 创建gRPC服务器实例并注册ShortUrl服务
-	s := grpc.NewServer(grpc.UnaryInterceptor(interceptor.UnaryAuthInterceptor), grpc.StreamInterceptor(interceptor.StreamAuthInterceptor))
-	service := server.NewService(cnf, logger, urlMapDataFactory, kvCacheFactory, lockFactory, bloomFactory)
+	// 管理接口的操作用户权限，角色与授权保存在 MySQL，按用户缓存一分钟
+	enforcer := rbac.NewEnforcer(data.NewRbacData(logger, mysql.GetDB()), time.Minute)
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptor.UnaryAuthInterceptor, interceptor.NewRbacInterceptor(enforcer)),
+		grpc.StreamInterceptor(interceptor.StreamAuthInterceptor),
+	}
+	if cnf.Server.TLS.Enable {
+		// 证书文件更新后在新的握手中自动生效
+		reloader, err := tlsutil.NewReloader(cnf.Server.TLS.CertFile, cnf.Server.TLS.KeyFile, cnf.Server.TLS.CaFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		tlsConfig, err := tlsutil.ServerConfig(reloader, cnf.Server.TLS.ClientAuth)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s := grpc.NewServer(opts...)
+	// 短链记录ID由ID生成器分配，创建短链只需一次写入
+	idGeneratorFactory, err := idgen.NewIDGeneratorFactory(cnf, logger, data.NewIdSequenceData(logger, mysql.GetDB()), urlMapDataFactory, redisPool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	service := server.NewService(cnf, logger, urlMapDataFactory, kvCacheFactory, lockFactory, bloomFactory, cacheInvalidator, enforcer, idGeneratorFactory)
 	proto.RegisterShortUrlServer(s, service)
 
 	// 多路复用健康检查
@@ -84,3 +131,21 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// checkShortKey 按两张表中的最大ID检查短链键置换配置
+func checkShortKey(cnf *config.Config, urlMapDataFactory data.IUrlMapDataFactory) error {
+	if cnf.ShortKey.Secret == "" {
+		return nil
+	}
+	var maxID int64
+	for _, isPublic := range []bool{true, false} {
+		id, err := urlMapDataFactory.NewUrlMapData(isPublic).MaxID()
+		if err != nil {
+			return err
+		}
+		if id > maxID {
+			maxID = id
+		}
+	}
+	return utils.CheckLegacyLength(cnf.ShortKey.Secret, cnf.ShortKey.LegacyLength, maxID)
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/audit"
+	"shorturl/pkg/utils"
+	"shorturl/pkg/zerror"
+	"shorturl/proto"
+	"shorturl/shorturl-server/cache"
+	"shorturl/shorturl-server/data"
+	"shorturl/shorturl-server/interceptor"
+	"strconv"
+	"time"
+)
+
+// maxBatchSize 批量接口每批最多处理的项数
+const maxBatchSize = 500
+
+// BatchGetShortUrl 批量生成或获取短链接，用于导入与批量上传
+// 公共短链与用户短链分别处理：没有期限的短链一次查询可复用的已有短链，
+// 其余短链用一次多行写入创建，缓存通过管道一次写入，布隆过滤器每类只更新一次；
+// 自定义别名可能与已有别名冲突，带幂等键的请求需要按幂等键查询，均逐项创建
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 生成短链的请求列表，每项的参数与 GetShortUrl 相同
+//
+// 返回:
+//
+//	*proto.BatchUrlResults: 与请求顺序一致的结果，单项失败时记录该项的状态码与错误信息
+//	error: 请求为空或超过每批上限时返回 InvalidArgument
+func (s *shortUrlService) BatchGetShortUrl(ctx context.Context, in *proto.BatchUrlRequest) (*proto.BatchUrlResults, error) {
+	if len(in.Urls) == 0 || len(in.Urls) > maxBatchSize {
+		err := zerror.NewByMsg("批量参数检查失败")
+		s.log.Error(err)
+		return nil, status.Error(codes.InvalidArgument, "每批需包含1到"+strconv.Itoa(maxBatchSize)+"项")
+	}
+
+	now := time.Now().Unix()
+	results := make([]*proto.BatchUrlResult, len(in.Urls))
+	groups := make(map[bool][]int)
+	for i, u := range in.Urls {
+		isPublic := u.IsPublic
+		if u.UserID != 0 {
+			isPublic = false
+		}
+		if err := s.checkUrl(u, now); err != nil {
+			results[i] = batchResult(nil, err)
+			continue
+		}
+		if u.Alias != "" {
+			results[i] = batchResult(s.createAlias(ctx, u, isPublic))
+			continue
+		}
+		if u.IdempotencyKey != "" {
+			results[i] = batchResult(s.GetShortUrl(ctx, u))
+			continue
+		}
+		groups[isPublic] = append(groups[isPublic], i)
+	}
+
+	for _, isPublic := range []bool{true, false} {
+		if len(groups[isPublic]) > 0 {
+			s.batchCreate(ctx, in.Urls, groups[isPublic], isPublic, now, results)
+		}
+	}
+	return &proto.BatchUrlResults{Results: results}, nil
+}
+
+// BatchGetOriginalUrl 批量解析短链，每项的处理与 GetOriginalUrl 相同，包括访问次数统计与有效期校验
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 解析短链的请求列表
+//
+// 返回:
+//
+//	*proto.BatchUrlResults: 与请求顺序一致的结果，单项失败时记录该项的状态码与错误信息
+//	error: 请求为空或超过每批上限时返回 InvalidArgument
+func (s *shortUrlService) BatchGetOriginalUrl(ctx context.Context, in *proto.BatchShortKeyRequest) (*proto.BatchUrlResults, error) {
+	if len(in.Keys) == 0 || len(in.Keys) > maxBatchSize {
+		err := zerror.NewByMsg("批量参数检查失败")
+		s.log.Error(err)
+		return nil, status.Error(codes.InvalidArgument, "每批需包含1到"+strconv.Itoa(maxBatchSize)+"项")
+	}
+
+	results := make([]*proto.BatchUrlResult, len(in.Keys))
+	for i, k := range in.Keys {
+		results[i] = batchResult(s.GetOriginalUrl(ctx, k))
+	}
+	return &proto.BatchUrlResults{Results: results}, nil
+}
+
+// batchCreate 创建同一类型的一组短链，结果写入 results 的对应位置
+// 参数:
+//
+//	ctx: 上下文对象
+//	urls: 全部请求
+//	idx: 本组请求在 urls 中的下标
+//	isPublic: 是否为公共链接
+//	now: 当前时间戳
+//	results: 批量处理的结果
+func (s *shortUrlService) batchCreate(ctx context.Context, urls []*proto.Url, idx []int, isPublic bool, now int64, results []*proto.BatchUrlResult) {
+	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
+
+	// 有期限的短链每次单独创建，不复用已有短链；用户短链只复用同一用户的记录，按用户分别查询
+	reusable := make(map[int64][]string)
+	for _, i := range idx {
+		if urls[i].ExpireAt == 0 && urls[i].MaxClicks == 0 {
+			reusable[urls[i].UserID] = append(reusable[urls[i].UserID], urls[i].Url)
+		}
+	}
+	existing := make(map[reuseKey]data.UrlMapEntity)
+	for userID, list := range reusable {
+		found, err := d.GetByOriginals(userID, list)
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			for _, i := range idx {
+				results[i] = batchResult(nil, err)
+			}
+			return
+		}
+		for u, e := range found {
+			existing[reuseKey{userID, u}] = e
+		}
+	}
+
+	// entities 为每项对应的记录，owner 为每项在 creating 中的下标，复用已有短链时为-1；
+	// 同一批内相同的可复用URL只创建一次
+	entities := make([]data.UrlMapEntity, len(idx))
+	owner := make([]int, len(idx))
+	var creating []data.UrlMapEntity
+	pending := make(map[reuseKey]int)
+	for j, i := range idx {
+		u := urls[i]
+		reuse := u.ExpireAt == 0 && u.MaxClicks == 0
+		rk := reuseKey{u.UserID, u.Url}
+		if e, ok := existing[rk]; ok && reuse {
+			entities[j] = e
+			owner[j] = -1
+			continue
+		}
+		if k, ok := pending[rk]; ok && reuse {
+			owner[j] = k
+			continue
+		}
+		var urlHash []byte
+		if reuse {
+			pending[rk] = len(creating)
+			urlHash = utils.UrlHash(u.Url)
+		}
+		owner[j] = len(creating)
+		creating = append(creating, data.UrlMapEntity{
+			UserID:      u.UserID,
+			OriginalUrl: u.Url,
+			ExpireAt:    u.ExpireAt,
+			MaxClicks:   u.MaxClicks,
+			CreateAt:    now,
+			UpdateAt:    now,
+			UrlHash:     urlHash,
+		})
+	}
+
+	var err error
+	generator := s.idGeneratorFactory.Generator(isPublic)
+	for k := range creating {
+		if creating[k].ID, err = generator.NextID(); err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			break
+		}
+		creating[k].ShortKey = s.keyCodec.Encode(creating[k].ID)
+	}
+	if err == nil {
+		err = d.BatchCreate(creating)
+	}
+	if errors.Is(err, data.ErrUrlHashExists) {
+		// 并发请求已创建了其中某个原始URL的短链，整批未写入，逐项创建以复用已有短链
+		for _, i := range idx {
+			results[i] = batchResult(s.GetShortUrl(ctx, urls[i]))
+		}
+		return
+	}
+	keyPrefix, domain := s.keyScope(isPublic)
+	var entries []cache.Entry
+	var members []string
+	for j, i := range idx {
+		if owner[j] >= 0 {
+			if err != nil {
+				results[i] = batchResult(nil, err)
+				continue
+			}
+			entities[j] = creating[owner[j]]
+		}
+		e := entities[j]
+		entries = append(entries, cache.Entry{
+			Key:   keyPrefix + e.ShortKey,
+			Value: urls[i].Url,
+			TTL:   cache.RandomTTL(urls[i].ExpireAt, now),
+		})
+		members = append(members, strconv.FormatInt(e.ID, 10))
+		results[i] = batchResult(&proto.Url{
+			Url:       domain + e.ShortKey,
+			UserID:    urls[i].UserID,
+			ExpireAt:  urls[i].ExpireAt,
+			MaxClicks: urls[i].MaxClicks,
+		}, nil)
+	}
+	if err == nil {
+		for _, e := range creating {
+			interceptor.Audit(interceptor.AuditRecord(ctx, audit.ActionLinkCreate, audit.OutcomeSuccess, e.UserID).
+				Target(auditTarget(isPublic), e.ShortKey).WithDetail(e.OriginalUrl))
+		}
+	}
+
+	// 记录已写入数据库，缓存写入失败时访问会回源加载，不影响本次结果
+	kvCache := s.kvCacheFactory.NewKVCache()
+	defer kvCache.Destroy()
+	if err := kvCache.SetMulti(entries); err != nil {
+		s.log.Warning("批量写入缓存失败: " + err.Error())
+	}
+	s.bloomAddMulti(isPublic, members)
+}
+
+// reuseKey 可复用短链的查找键，用户短链按用户区分
+type reuseKey struct {
+	userID int64
+	url    string
+}
+
+// batchResult 将单项的处理结果转换为批量结果，非 gRPC 状态的错误使用 Unknown 状态码
+func batchResult(url *proto.Url, err error) *proto.BatchUrlResult {
+	if err == nil {
+		return &proto.BatchUrlResult{Url: url}
+	}
+	if st, ok := status.FromError(err); ok {
+		return &proto.BatchUrlResult{Code: int32(st.Code()), Error: st.Message()}
+	}
+	msg := err.Error()
+	var zerr *zerror.ZError
+	if errors.As(err, &zerr) && zerr.ErrMsg != "" {
+		msg = zerr.ErrMsg
+	}
+	return &proto.BatchUrlResult{Code: int32(codes.Unknown), Error: msg}
+}
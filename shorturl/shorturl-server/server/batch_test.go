@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/utils"
+	"shorturl/proto"
+	"shorturl/shorturl-server/data"
+	"testing"
+	"time"
+)
+
+func TestBatchGetShortUrlItemErrors(t *testing.T) {
+	ts := newTestService()
+	ts.user.put(data.UrlMapEntity{ID: 1, UserID: 8, ShortKey: "spring-sale", OriginalUrl: "https://other.com/"})
+	ts.user.aliases[1] = true
+
+	rs, err := ts.BatchGetShortUrl(context.Background(), &proto.BatchUrlRequest{Urls: []*proto.Url{
+		{Url: "https://example.com/a", UserID: 7},
+		{Url: "ftp://example.com/a", UserID: 7},
+		{Url: "https://example.com/b", UserID: 7, ExpireAt: time.Now().Unix() - 1},
+		{Url: "https://example.com/c", UserID: 7, Alias: "spring-sale"},
+		{Url: "https://example.com/d", UserID: 7, Alias: "summer-sale"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 单项失败不影响其他项，结果与请求顺序一致
+	want := []codes.Code{codes.OK, codes.InvalidArgument, codes.InvalidArgument, codes.AlreadyExists, codes.OK}
+	for i, r := range rs.Results {
+		if codes.Code(r.Code) != want[i] {
+			t.Errorf("item %d: code %v %q, want %v", i, codes.Code(r.Code), r.Error, want[i])
+		}
+	}
+	// 自定义别名逐项创建，其余项一次写入
+	if rs.Results[4].Url.Url != "https://u.test/summer-sale" || ts.user.creates != 3 || len(ts.user.rows) != 3 {
+		t.Errorf("alias url %q, creates=%d rows=%d", rs.Results[4].Url.Url, ts.user.creates, len(ts.user.rows))
+	}
+}
+
+func TestBatchGetShortUrlDedupe(t *testing.T) {
+	ts := newTestService()
+	existing := ts.public.put(data.UrlMapEntity{ID: 50, ShortKey: utils.ToBase62(50), OriginalUrl: "https://example.com/old", UrlHash: utils.UrlHash("https://example.com/old")})
+
+	rs, err := ts.BatchGetShortUrl(context.Background(), &proto.BatchUrlRequest{Urls: []*proto.Url{
+		{Url: "https://example.com/new", IsPublic: true},
+		{Url: "https://EXAMPLE.com/new", IsPublic: true},
+		{Url: "https://example.com/old", IsPublic: true},
+		{Url: "https://example.com/new", IsPublic: true, MaxClicks: 5},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, len(rs.Results))
+	for i, r := range rs.Results {
+		if r.Code != 0 {
+			t.Fatalf("item %d: %s", i, r.Error)
+		}
+		got[i] = r.Url.Url
+	}
+	// 规范化后相同的可复用URL在同一批内只创建一次，已有的短链直接复用，有期限的短链单独创建
+	if got[0] != got[1] || got[2] != "https://s.test/"+existing.ShortKey || got[3] == got[0] {
+		t.Errorf("urls %v", got)
+	}
+	if ts.public.creates != 1 || len(ts.public.rows) != 3 {
+		t.Errorf("creates=%d rows=%d, want one write of two rows", ts.public.creates, len(ts.public.rows))
+	}
+	if ts.kvCache.values[utils.ToBase62(101)] != "https://example.com/new" {
+		t.Errorf("cache %v", ts.kvCache.values)
+	}
+}
+
+func TestBatchGetShortUrlHashConflict(t *testing.T) {
+	ts := newTestService()
+	// 批量写入前并发请求已创建了其中一个原始URL的短链，整批写入失败后逐项创建
+	concurrent := data.UrlMapEntity{ID: 60, UserID: 7, ShortKey: utils.ToBase62(60), OriginalUrl: "https://example.com/a", UrlHash: utils.UrlHash("https://example.com/a")}
+	ts.user.beforeCreate = func(f *fakeUrlMapData) {
+		f.beforeCreate = nil
+		f.put(concurrent)
+	}
+
+	rs, err := ts.BatchGetShortUrl(context.Background(), &proto.BatchUrlRequest{Urls: []*proto.Url{
+		{Url: "https://example.com/a", UserID: 7},
+		{Url: "https://example.com/b", UserID: 7},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range rs.Results {
+		if r.Code != 0 {
+			t.Fatalf("item %d: %s", i, r.Error)
+		}
+	}
+	if rs.Results[0].Url.Url != "https://u.test/"+concurrent.ShortKey {
+		t.Errorf("conflicting url not reused: %q", rs.Results[0].Url.Url)
+	}
+	if len(ts.user.rows) != 2 {
+		t.Errorf("rows=%d, want the concurrent record and one new record", len(ts.user.rows))
+	}
+}
+
+func TestBatchGetShortUrlIDFailure(t *testing.T) {
+	ts := newTestService()
+	ts.ids.err = errors.New("id sequence unavailable")
+	rs, err := ts.BatchGetShortUrl(context.Background(), &proto.BatchUrlRequest{Urls: []*proto.Url{
+		{Url: "https://example.com/a", UserID: 7},
+		{Url: "https://example.com/b", UserID: 7},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range rs.Results {
+		if codes.Code(r.Code) != codes.Unknown || r.Url != nil {
+			t.Errorf("item %d: %+v", i, r)
+		}
+	}
+	if ts.user.creates != 0 {
+		t.Errorf("wrote %d times without IDs", ts.user.creates)
+	}
+}
+
+func TestBatchSizeLimits(t *testing.T) {
+	ts := newTestService()
+	if _, err := ts.BatchGetShortUrl(context.Background(), &proto.BatchUrlRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty batch: %v", err)
+	}
+	urls := make([]*proto.Url, maxBatchSize+1)
+	for i := range urls {
+		urls[i] = &proto.Url{Url: "https://example.com/", IsPublic: true}
+	}
+	if _, err := ts.BatchGetShortUrl(context.Background(), &proto.BatchUrlRequest{Urls: urls}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("oversized batch: %v", err)
+	}
+	if _, err := ts.BatchGetOriginalUrl(context.Background(), &proto.BatchShortKeyRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty key batch: %v", err)
+	}
+}
+
+func TestBatchGetOriginalUrl(t *testing.T) {
+	ts := newTestService()
+	key := utils.ToBase62(100)
+	ts.kvCache.values["user_"+key] = "https://example.com/a"
+
+	rs, err := ts.BatchGetOriginalUrl(context.Background(), &proto.BatchShortKeyRequest{Keys: []*proto.ShortKey{
+		{Key: key, UserID: 7},
+		{UserID: 7},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.Results[0].Code != 0 || rs.Results[0].Url.Url != "https://example.com/a" {
+		t.Errorf("item 0: %+v", rs.Results[0])
+	}
+	if rs.Results[1].Code == 0 || rs.Results[1].Error != "参数检查失败" {
+		t.Errorf("item 1: %+v", rs.Results[1])
+	}
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/audit"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/zerror"
+	"shorturl/proto"
+	"shorturl/shorturl-server/data"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 20  // 短链列表默认每页条数
+	maxPageSize     = 100 // 短链列表每页最大条数
+)
+
+// ListShortUrls 分页查询用户的短链，已删除的短链不返回
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 查询条件，UserID 必填
+//
+// 返回:
+//
+//	*proto.ShortUrlList: 当前页的短链与符合条件的总数
+//	error: 参数非法返回 InvalidArgument，查询失败时返回错误
+func (s *shortUrlService) ListShortUrls(ctx context.Context, in *proto.ListShortUrlsRequest) (*proto.ShortUrlList, error) {
+	if in.UserID == 0 || in.CreateFrom < 0 || in.CreateTo < 0 || in.Page < 0 || in.PageSize < 0 {
+		err := zerror.NewByMsg("参数检查失败")
+		s.log.Error(err)
+		return nil, status.Error(codes.InvalidArgument, "参数检查失败")
+	}
+	switch in.Status {
+	case "", data.ListStatusActive, data.ListStatusExpired, data.ListStatusPaused, data.ListStatusDisabled:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "状态参数无效："+in.Status)
+	}
+	switch in.Sort {
+	case "", data.ListSortRecent, data.ListSortClicks:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "排序参数无效："+in.Sort)
+	}
+	domain := strings.ToLower(strings.TrimSpace(in.Domain))
+	if strings.ContainsAny(domain, "/:?#@ ") {
+		return nil, status.Error(codes.InvalidArgument, "域名参数无效："+in.Domain)
+	}
+
+	page := int(in.Page)
+	if page == 0 {
+		page = 1
+	}
+	pageSize := int(in.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	now := time.Now().Unix()
+	d := s.urlMapDataFactory.NewUrlMapData(false)
+	list, total, err := d.List(&data.UrlMapQuery{
+		UserID:     in.UserID,
+		CreateFrom: in.CreateFrom,
+		CreateTo:   in.CreateTo,
+		Domain:     domain,
+		Status:     in.Status,
+		Sort:       in.Sort,
+		Now:        now,
+		Offset:     (page - 1) * pageSize,
+		Limit:      pageSize,
+	})
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+
+	rs := &proto.ShortUrlList{
+		List:  make([]*proto.ShortUrlInfo, 0, len(list)),
+		Total: total,
+	}
+	for i := range list {
+		rs.List = append(rs.List, s.toInfo(&list[i], false, now))
+	}
+	return rs, nil
+}
+
+// GetShortUrlInfo 查询短链详情，包括访问次数、有效期与创建、更新时间
+// 用户短链只有属主可以查询，公共短链没有属主，拥有接口权限的调用方均可查询
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 包含短链键、用户ID和短链类型的请求对象
+//
+// 返回:
+//
+//	*proto.ShortUrlInfo: 短链详情
+//	error: 短链不存在返回 NotFound，非属主查询返回 PermissionDenied
+func (s *shortUrlService) GetShortUrlInfo(ctx context.Context, in *proto.ShortKey) (*proto.ShortUrlInfo, error) {
+	isPublic := in.IsPublic
+	if in.UserID != 0 {
+		isPublic = false
+	}
+	if in.Key == "" {
+		err := zerror.NewByMsg("参数检查失败")
+		s.log.Error(err)
+		return nil, status.Error(codes.InvalidArgument, "参数检查失败")
+	}
+
+	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
+	var entity *data.UrlMapEntity
+	var err error
+	if isPublic {
+		entity, err = s.findByKey(d, in.Key)
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		if entity == nil || entity.Status == constants.URL_STATUS_DELETED {
+			return nil, errNotFound
+		}
+	} else {
+		entity, err = s.ownedEntity(ctx, d, in.Key, in.UserID, false, audit.ActionAccessDenied, "查询短链详情")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.toInfo(entity, isPublic, time.Now().Unix()), nil
+}
+
+// toInfo 将短链记录转换为接口返回的详情
+func (s *shortUrlService) toInfo(e *data.UrlMapEntity, isPublic bool, now int64) *proto.ShortUrlInfo {
+	_, domain := s.keyScope(isPublic)
+	return &proto.ShortUrlInfo{
+		Id:          e.ID,
+		UserID:      e.UserID,
+		ShortKey:    e.ShortKey,
+		ShortUrl:    domain + e.ShortKey,
+		OriginalUrl: e.OriginalUrl,
+		Times:       int64(e.Times),
+		Status:      int32(e.Status),
+		Expired:     e.Expired(now),
+		ExpireAt:    e.ExpireAt,
+		MaxClicks:   e.MaxClicks,
+		CreateAt:    e.CreateAt,
+		UpdateAt:    e.UpdateAt,
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/utils"
+	"shorturl/proto"
+	"shorturl/shorturl-server/data"
+	"testing"
+)
+
+func TestListShortUrls(t *testing.T) {
+	cases := []struct {
+		name  string
+		in    *proto.ListShortUrlsRequest
+		code  codes.Code
+		query data.UrlMapQuery
+	}{
+		{"defaults", &proto.ListShortUrlsRequest{UserID: 7}, codes.OK,
+			data.UrlMapQuery{UserID: 7, Limit: defaultPageSize}},
+		{"filters", &proto.ListShortUrlsRequest{UserID: 7, Status: data.ListStatusPaused, Sort: data.ListSortClicks, CreateFrom: 10, CreateTo: 20, Page: 3, PageSize: 10}, codes.OK,
+			data.UrlMapQuery{UserID: 7, Status: data.ListStatusPaused, Sort: data.ListSortClicks, CreateFrom: 10, CreateTo: 20, Offset: 20, Limit: 10}},
+		// 域名不区分大小写，每页条数不超过上限
+		{"domain", &proto.ListShortUrlsRequest{UserID: 7, Domain: " Example.COM ", PageSize: 1000}, codes.OK,
+			data.UrlMapQuery{UserID: 7, Domain: "example.com", Limit: maxPageSize}},
+		{"missing user", &proto.ListShortUrlsRequest{}, codes.InvalidArgument, data.UrlMapQuery{}},
+		{"negative page", &proto.ListShortUrlsRequest{UserID: 7, Page: -1}, codes.InvalidArgument, data.UrlMapQuery{}},
+		{"unknown status", &proto.ListShortUrlsRequest{UserID: 7, Status: "deleted"}, codes.InvalidArgument, data.UrlMapQuery{}},
+		{"unknown sort", &proto.ListShortUrlsRequest{UserID: 7, Sort: "random"}, codes.InvalidArgument, data.UrlMapQuery{}},
+		// 域名只能是主机名，不能带协议、端口或路径
+		{"domain with scheme", &proto.ListShortUrlsRequest{UserID: 7, Domain: "https://example.com"}, codes.InvalidArgument, data.UrlMapQuery{}},
+		{"domain with port", &proto.ListShortUrlsRequest{UserID: 7, Domain: "example.com:8080"}, codes.InvalidArgument, data.UrlMapQuery{}},
+		{"domain with path", &proto.ListShortUrlsRequest{UserID: 7, Domain: "example.com/a"}, codes.InvalidArgument, data.UrlMapQuery{}},
+	}
+	for _, c := range cases {
+		ts := newTestService()
+		_, err := ts.ListShortUrls(context.Background(), c.in)
+		if status.Code(err) != c.code {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.code)
+			continue
+		}
+		if c.code != codes.OK {
+			if ts.user.query != nil {
+				t.Errorf("%s: queried on invalid request", c.name)
+			}
+			continue
+		}
+		q := *ts.user.query
+		if q.Now == 0 {
+			t.Errorf("%s: query without current time", c.name)
+		}
+		q.Now = 0
+		if q != c.query {
+			t.Errorf("%s: query %+v, want %+v", c.name, q, c.query)
+		}
+	}
+}
+
+func TestListShortUrlsResult(t *testing.T) {
+	ts := newTestService()
+	key := utils.ToBase62(100)
+	ts.user.put(data.UrlMapEntity{ID: 100, UserID: 7, ShortKey: key, OriginalUrl: "https://example.com/", Status: constants.URL_STATUS_PAUSED, Times: 3, MaxClicks: 3})
+
+	rs, err := ts.ListShortUrls(context.Background(), &proto.ListShortUrlsRequest{UserID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.Total != 1 || len(rs.List) != 1 {
+		t.Fatalf("got %d of %d", len(rs.List), rs.Total)
+	}
+	info := rs.List[0]
+	if info.ShortUrl != "https://u.test/"+key || info.Status != constants.URL_STATUS_PAUSED || !info.Expired {
+		t.Errorf("info %+v", info)
+	}
+}
+
+func TestGetShortUrlInfo(t *testing.T) {
+	key := utils.ToBase62(100)
+	cases := []struct {
+		name   string
+		public bool
+		status int
+		in     *proto.ShortKey
+		code   codes.Code
+	}{
+		{"owner", false, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, UserID: 7}, codes.OK},
+		{"owner paused", false, constants.URL_STATUS_PAUSED, &proto.ShortKey{Key: key, UserID: 7}, codes.OK},
+		{"not owner", false, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, UserID: 8}, codes.PermissionDenied},
+		{"deleted", false, constants.URL_STATUS_DELETED, &proto.ShortKey{Key: key, UserID: 7}, codes.NotFound},
+		// 公共短链没有属主，调用方均可查询
+		{"public", true, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, IsPublic: true}, codes.OK},
+		{"public deleted", true, constants.URL_STATUS_DELETED, &proto.ShortKey{Key: key, IsPublic: true}, codes.NotFound},
+	}
+	for _, c := range cases {
+		ts := newTestService()
+		d := ts.user
+		if c.public {
+			d = ts.public
+		}
+		d.put(data.UrlMapEntity{ID: 100, UserID: 7, ShortKey: key, OriginalUrl: "https://example.com/", Status: c.status})
+
+		info, err := ts.GetShortUrlInfo(context.Background(), c.in)
+		if status.Code(err) != c.code {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.code)
+			continue
+		}
+		if err == nil && info.Id != 100 {
+			t.Errorf("%s: info %+v", c.name, info)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/audit"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/zerror"
+	"shorturl/proto"
+	"shorturl/shorturl-server/data"
+	"shorturl/shorturl-server/interceptor"
+	"time"
+)
+
+var (
+	errNotFound  = status.Error(codes.NotFound, "短链不存在")
+	errNotOwner  = status.Error(codes.PermissionDenied, "无权操作该短链")
+	errPublicUrl = status.Error(codes.InvalidArgument, "公共短链没有属主，不支持该操作")
+	errTakenDown = status.Error(codes.FailedPrecondition, "短链已被管理员下架")
+)
+
+// DisableShortUrl 属主停用用户短链，停用后访问时返回短链不存在，可以重新启用
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 包含短链键和所属用户ID的请求对象
+//
+// 返回:
+//
+//	*proto.Url: 被停用的短链接地址
+//	error: 短链不存在、非属主操作、已被管理员下架或处理失败时返回错误
+func (s *shortUrlService) DisableShortUrl(ctx context.Context, in *proto.ShortKey) (*proto.Url, error) {
+	return s.changeStatus(ctx, in, audit.ActionLinkDisable, func(d data.IUrlMapData, e *data.UrlMapEntity, now int64) error {
+		switch e.Status {
+		case constants.URL_STATUS_PAUSED:
+			return nil
+		case constants.URL_STATUS_NORMAL:
+			return d.SetStatus(e.ID, constants.URL_STATUS_PAUSED, now)
+		}
+		return errTakenDown
+	})
+}
+
+// EnableShortUrl 属主重新启用已停用的用户短链，管理员下架的短链不能启用
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 包含短链键和所属用户ID的请求对象
+//
+// 返回:
+//
+//	*proto.Url: 被启用的短链接地址
+//	error: 短链不存在、非属主操作、已被管理员下架或处理失败时返回错误
+func (s *shortUrlService) EnableShortUrl(ctx context.Context, in *proto.ShortKey) (*proto.Url, error) {
+	return s.changeStatus(ctx, in, audit.ActionLinkEnable, func(d data.IUrlMapData, e *data.UrlMapEntity, now int64) error {
+		switch e.Status {
+		case constants.URL_STATUS_NORMAL:
+			return nil
+		case constants.URL_STATUS_PAUSED:
+			return d.SetStatus(e.ID, constants.URL_STATUS_NORMAL, now)
+		}
+		return errTakenDown
+	})
+}
+
+// DeleteShortUrl 属主删除用户短链，删除后不能恢复，自定义别名可被重新使用
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 包含短链键和所属用户ID的请求对象
+//
+// 返回:
+//
+//	*proto.Url: 被删除的短链接地址
+//	error: 短链不存在、非属主操作或处理失败时返回错误
+func (s *shortUrlService) DeleteShortUrl(ctx context.Context, in *proto.ShortKey) (*proto.Url, error) {
+	return s.changeStatus(ctx, in, audit.ActionLinkDelete, func(d data.IUrlMapData, e *data.UrlMapEntity, now int64) error {
+		return d.Delete(e.ID, now)
+	})
+}
+
+// changeStatus 校验属主后修改短链状态，并删除分布式缓存、各实例的本地缓存与空值缓存
+func (s *shortUrlService) changeStatus(ctx context.Context, in *proto.ShortKey, action string,
+	apply func(d data.IUrlMapData, e *data.UrlMapEntity, now int64) error) (*proto.Url, error) {
+	isPublic := in.IsPublic
+	if in.UserID != 0 {
+		isPublic = false
+	}
+	if in.Key == "" {
+		err := zerror.NewByMsg("参数检查失败")
+		s.log.Error(err)
+		return nil, status.Error(codes.InvalidArgument, "参数检查失败")
+	}
+
+	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
+	entity, err := s.ownedEntity(ctx, d, in.Key, in.UserID, isPublic, action, "")
+	if err != nil {
+		return nil, err
+	}
+	if err = apply(d, entity, time.Now().Unix()); err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+
+	// 缓存键在正常值与空值之间共用，删除后下次访问按最新状态从数据库加载
+	err = s.cacheInvalidator.Invalidate("user_" + entity.ShortKey)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	interceptor.Audit(interceptor.AuditRecord(ctx, action, audit.OutcomeSuccess, in.UserID).
+		Target(auditTarget(false), entity.ShortKey))
+
+	return &proto.Url{
+		Url:    s.config.UserShortDomain + entity.ShortKey,
+		UserID: in.UserID,
+	}, nil
+}
+
+// ownedEntity 查询用户短链并校验属主，已删除的短链按不存在处理
+// 公共短链没有属主，不支持属主操作；非属主操作时写入拒绝的审计记录
+// 参数:
+//
+//	ctx: 上下文对象
+//	d: 用户短链表的数据访问对象
+//	key: 短链键或自定义别名
+//	userID: 操作用户ID
+//	isPublic: 是否为公共短链
+//	action: 审计记录的操作
+//	detail: 审计记录的补充说明
+//
+// 返回值:
+//
+//	*data.UrlMapEntity: 短链记录
+//	error: 公共短链返回 InvalidArgument，不存在返回 NotFound，非属主返回 PermissionDenied
+func (s *shortUrlService) ownedEntity(ctx context.Context, d data.IUrlMapData, key string, userID int64, isPublic bool, action, detail string) (*data.UrlMapEntity, error) {
+	if isPublic {
+		s.log.Error(zerror.NewByMsg("公共短链不支持属主操作 key=" + key))
+		return nil, errPublicUrl
+	}
+	entity, err := s.findByKey(d, key)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	if entity == nil || entity.Status == constants.URL_STATUS_DELETED {
+		s.log.Error(zerror.NewByMsg("短链不存在 key=" + key))
+		return nil, errNotFound
+	}
+	if entity.UserID != userID {
+		s.log.Error(zerror.NewByMsg("无权操作该短链 key=" + key))
+		interceptor.Audit(interceptor.AuditRecord(ctx, action, audit.OutcomeDenied, userID).
+			Target(auditTarget(false), entity.ShortKey).WithDetail(detail))
+		return nil, errNotOwner
+	}
+	return entity, nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/utils"
+	"shorturl/proto"
+	"shorturl/shorturl-server/data"
+	"testing"
+)
+
+func TestOwnerStatusChanges(t *testing.T) {
+	const owner, other = 7, 8
+	key := utils.ToBase62(100)
+	type rpc func(s *shortUrlService, ctx context.Context, in *proto.ShortKey) (*proto.Url, error)
+	disable := (*shortUrlService).DisableShortUrl
+	enable := (*shortUrlService).EnableShortUrl
+	remove := (*shortUrlService).DeleteShortUrl
+
+	cases := []struct {
+		name       string
+		call       rpc
+		status     int
+		in         *proto.ShortKey
+		code       codes.Code
+		wantStatus int
+	}{
+		// 属主停用、启用正常与已停用的短链，重复操作不报错
+		{"disable normal", disable, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, UserID: owner}, codes.OK, constants.URL_STATUS_PAUSED},
+		{"disable paused", disable, constants.URL_STATUS_PAUSED, &proto.ShortKey{Key: key, UserID: owner}, codes.OK, constants.URL_STATUS_PAUSED},
+		{"enable paused", enable, constants.URL_STATUS_PAUSED, &proto.ShortKey{Key: key, UserID: owner}, codes.OK, constants.URL_STATUS_NORMAL},
+		{"enable normal", enable, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, UserID: owner}, codes.OK, constants.URL_STATUS_NORMAL},
+		// 管理员下架的短链属主不能停用或启用
+		{"disable taken down", disable, constants.URL_STATUS_DISABLED, &proto.ShortKey{Key: key, UserID: owner}, codes.FailedPrecondition, constants.URL_STATUS_DISABLED},
+		{"enable taken down", enable, constants.URL_STATUS_DISABLED, &proto.ShortKey{Key: key, UserID: owner}, codes.FailedPrecondition, constants.URL_STATUS_DISABLED},
+		// 属主可以删除任意状态的短链，已删除的短链按不存在处理
+		{"delete paused", remove, constants.URL_STATUS_PAUSED, &proto.ShortKey{Key: key, UserID: owner}, codes.OK, constants.URL_STATUS_DELETED},
+		{"delete taken down", remove, constants.URL_STATUS_DISABLED, &proto.ShortKey{Key: key, UserID: owner}, codes.OK, constants.URL_STATUS_DELETED},
+		{"enable deleted", enable, constants.URL_STATUS_DELETED, &proto.ShortKey{Key: key, UserID: owner}, codes.NotFound, constants.URL_STATUS_DELETED},
+		// 非属主、公共短链与不存在的短链
+		{"disable not owner", disable, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, UserID: other}, codes.PermissionDenied, constants.URL_STATUS_NORMAL},
+		{"delete not owner", remove, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, UserID: other}, codes.PermissionDenied, constants.URL_STATUS_NORMAL},
+		{"disable public", disable, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: key, IsPublic: true}, codes.InvalidArgument, constants.URL_STATUS_NORMAL},
+		{"disable missing", disable, constants.URL_STATUS_NORMAL, &proto.ShortKey{Key: utils.ToBase62(101), UserID: owner}, codes.NotFound, constants.URL_STATUS_NORMAL},
+		{"disable empty key", disable, constants.URL_STATUS_NORMAL, &proto.ShortKey{UserID: owner}, codes.InvalidArgument, constants.URL_STATUS_NORMAL},
+	}
+	for _, c := range cases {
+		ts := newTestService()
+		ts.user.put(data.UrlMapEntity{ID: 100, UserID: owner, ShortKey: key, OriginalUrl: "https://example.com/", Status: c.status})
+
+		rs, err := c.call(ts.shortUrlService, context.Background(), c.in)
+		if status.Code(err) != c.code {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.code)
+			continue
+		}
+		if got := ts.user.rows[100].Status; got != c.wantStatus {
+			t.Errorf("%s: status %d, want %d", c.name, got, c.wantStatus)
+		}
+		if c.code != codes.OK {
+			if len(ts.invalidator.keys) != 0 {
+				t.Errorf("%s: invalidated %v on failure", c.name, ts.invalidator.keys)
+			}
+			continue
+		}
+		if rs.Url != "https://u.test/"+key {
+			t.Errorf("%s: url %q", c.name, rs.Url)
+		}
+		// 修改状态后删除缓存，下次访问按最新状态加载
+		if len(ts.invalidator.keys) != 1 || ts.invalidator.keys[0] != "user_"+key {
+			t.Errorf("%s: invalidated %v", c.name, ts.invalidator.keys)
+		}
+	}
+}
+
+func TestOwnerAlias(t *testing.T) {
+	ts := newTestService()
+	ts.user.put(data.UrlMapEntity{ID: 100, UserID: 7, ShortKey: "spring-sale", OriginalUrl: "https://example.com/"})
+	ts.user.aliases[100] = true
+
+	// 自定义别名不区分大小写
+	if _, err := ts.DisableShortUrl(context.Background(), &proto.ShortKey{Key: "Spring-Sale", UserID: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if ts.user.rows[100].Status != constants.URL_STATUS_PAUSED {
+		t.Errorf("status %d", ts.user.rows[100].Status)
+	}
+	if _, err := ts.EnableShortUrl(context.Background(), &proto.ShortKey{Key: "spring-sale", UserID: 8}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("enable by other user: %v", err)
+	}
+}
@@ -2,36 +2,61 @@ package server
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"math/rand"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/audit"
 	"shorturl/pkg/config"
 	"shorturl/pkg/constants"
 	"shorturl/pkg/log"
+	"shorturl/pkg/rbac"
 	"shorturl/pkg/utils"
 	"shorturl/pkg/zerror"
 	"shorturl/proto"
 	"shorturl/shorturl-server/cache"
 	"shorturl/shorturl-server/data"
+	"shorturl/shorturl-server/idgen"
+	"shorturl/shorturl-server/interceptor"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// errExpired 短链已过期或已达到访问次数上限，使用单独的状态码便于调用方展示过期页面
+var errExpired = status.Error(codes.FailedPrecondition, "短链已过期")
+
+// errIdempotencyMismatch 幂等键已用于原始URL或有效期参数不同的请求
+var errIdempotencyMismatch = status.Error(codes.InvalidArgument, "幂等键已用于其他请求")
+
+const (
+	maxIdempotencyKeyLen = 64 // 幂等键最大长度，与 idempotency_key 字段长度一致
+	createRetries        = 2  // 创建短链时因并发写入冲突重试的次数
+)
+
 // shortUrlService 实现了 proto.ShortUrlServer 接口，提供短链接相关服务。
 type shortUrlService struct {
 	proto.UnimplementedShortUrlServer
-	config            *config.Config // 配置信息
-	log               log.ILogger    // 日志记录器
-	urlMapDataFactory data.IUrlMapDataFactory
-	kvCacheFactory    cache.CacheFactory
-	lockFactory       cache.DistributedLockFactory
-	bloomFactory      cache.BloomFilterFactory
-	bloomFilter       cache.BloomFilter
-	userBloomFilter   cache.BloomFilter
-	cacheWarmer       cache.CacheWarmer
+	config             *config.Config // 配置信息
+	log                log.ILogger    // 日志记录器
+	urlMapDataFactory  data.IUrlMapDataFactory
+	kvCacheFactory     cache.CacheFactory
+	lockFactory        cache.DistributedLockFactory
+	bloomFactory       cache.BloomFilterFactory
+	bloomFilter        cache.BloomFilter
+	userBloomFilter    cache.BloomFilter
+	cacheWarmer        cache.CacheWarmer
+	cacheInvalidator   cache.CacheInvalidator
+	enforcer           *rbac.Enforcer
+	aliasPolicy        *utils.AliasPolicy
+	urlNormalizer      *utils.UrlNormalizer
+	keyCodec           utils.KeyCodec
+	idGeneratorFactory idgen.IDGeneratorFactory
 }
 
 // NewService 创建一个新的短链接服务实例
-func NewService(cnf *config.Config, logger log.ILogger, urlDataFactory data.IUrlMapDataFactory, kvCacheFactory cache.CacheFactory, lockFactory cache.DistributedLockFactory, bloomFactory cache.BloomFilterFactory) proto.ShortUrlServer {
+func NewService(cnf *config.Config, logger log.ILogger, urlDataFactory data.IUrlMapDataFactory, kvCacheFactory cache.CacheFactory, lockFactory cache.DistributedLockFactory, bloomFactory cache.BloomFilterFactory, cacheInvalidator cache.CacheInvalidator, enforcer *rbac.Enforcer, idGeneratorFactory idgen.IDGeneratorFactory) proto.ShortUrlServer {
 	// 创建缓存预热器
 	kvCache := kvCacheFactory.NewKVCache()
 	bloomFilter := bloomFactory.NewBloomFilter("shorturl:bloom", 100000, 0.01)
@@ -42,15 +67,21 @@ func NewService(cnf *config.Config, logger log.ILogger, urlDataFactory data.IUrl
 
 	// 创建服务实例
 	service := &shortUrlService{
-		config:            cnf,
-		log:               logger,
-		urlMapDataFactory: urlDataFactory,
-		kvCacheFactory:    kvCacheFactory,
-		lockFactory:       lockFactory,
-		bloomFactory:      bloomFactory,
-		bloomFilter:       bloomFilter,
-		userBloomFilter:   userBloomFilter,
-		cacheWarmer:       cacheWarmer,
+		config:             cnf,
+		log:                logger,
+		urlMapDataFactory:  urlDataFactory,
+		kvCacheFactory:     kvCacheFactory,
+		lockFactory:        lockFactory,
+		bloomFactory:       bloomFactory,
+		bloomFilter:        bloomFilter,
+		userBloomFilter:    userBloomFilter,
+		cacheWarmer:        cacheWarmer,
+		cacheInvalidator:   cacheInvalidator,
+		enforcer:           enforcer,
+		aliasPolicy:        utils.NewAliasPolicy(cnf.Alias.Reserved, cnf.Alias.Blocked),
+		urlNormalizer:      utils.NewUrlNormalizer(cnf.UrlNormalize.StripTracking, cnf.UrlNormalize.TrackingParams, cnf.UrlNormalize.StripFragment),
+		keyCodec:           utils.NewKeyCodec(cnf.ShortKey.Secret, cnf.ShortKey.MinLength, cnf.ShortKey.LegacyLength),
+		idGeneratorFactory: idGeneratorFactory,
 	}
 
 	// 启动缓存预热
@@ -75,7 +106,8 @@ func (s *shortUrlService) startCacheWarmup() {
 // 参数:
 //
 //	ctx: 上下文对象，用于控制请求的生命周期和取消机制
-//	in: 包含原始URL和用户ID的请求对象，UserID为0时表示公共链接
+//	in: 包含原始URL和用户ID的请求对象，UserID为0时表示公共链接，Alias不为空时使用自定义别名，
+//	    ExpireAt、MaxClicks 不为0时限制短链的有效期与访问次数，IdempotencyKey 不为空时重复请求返回首次创建的短链
 //
 // 返回:
 //
@@ -88,42 +120,24 @@ func (s *shortUrlService) GetShortUrl(ctx context.Context, in *proto.Url) (*prot
 	}
 
 	// 参数有效性验证
-	if in.Url == "" {
-		err := zerror.NewByMsg("参数检查失败")
-		s.log.Error(err)
+	now := time.Now().Unix()
+	if err := s.checkUrl(in, now); err != nil {
 		return nil, err
 	}
 
-	if !utils.IsUrl(in.Url) {
-		err := zerror.NewByMsg("参数检查失败")
-		s.log.Error(err)
-		return nil, err
+	if in.Alias != "" {
+		return s.createAlias(ctx, in, isPublic)
 	}
 
 	// 根据是否为公共链接创建数据访问对象
 	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
-	entity, err := d.GetByOriginal(in.Url)
+	entity, created, err := s.createEntity(d, in, isPublic, now)
 	if err != nil {
-		s.log.Error(zerror.NewByErr(err))
 		return nil, err
 	}
-
-	// 生成新的短链接标识符（若未存在）
-	if entity.ShortKey == "" {
-		id, err := d.GenerateID(in.GetUserID(), time.Now().Unix())
-		if err != nil {
-			s.log.Error(zerror.NewByErr(err))
-			return nil, err
-		}
-		entity.ShortKey = utils.ToBase62(id)
-		entity.OriginalUrl = in.Url
-		entity.ID = id
-		entity.UpdateAt = time.Now().Unix()
-		err = d.Update(entity)
-		if err != nil {
-			s.log.Error(zerror.NewByErr(err))
-			return nil, err
-		}
+	if created {
+		interceptor.Audit(interceptor.AuditRecord(ctx, audit.ActionLinkCreate, audit.OutcomeSuccess, in.UserID).
+			Target(auditTarget(isPublic), entity.ShortKey).WithDetail(in.Url))
 	}
 
 	// 根据链接类型配置域名和缓存键前缀
@@ -139,28 +153,170 @@ func (s *shortUrlService) GetShortUrl(ctx context.Context, in *proto.Url) (*prot
 	defer kvCache.Destroy()
 	key := keyPrefix + entity.ShortKey
 
-	// 使用随机过期时间，避免缓存雪崩
-	ttl := cache.DefaultTTL*80/100 + rand.Intn(cache.DefaultTTL*40/100)
-	err = kvCache.Set(key, entity.OriginalUrl, ttl)
-	if err != nil {
-		s.log.Error(zerror.NewByErr(err))
-		return nil, err
+	// 使用随机过期时间，避免缓存雪崩，有期限的短链不超过剩余有效期；
+	// 幂等重放返回的短链可能已被停用，只缓存正常状态的短链
+	if entity.Status == constants.URL_STATUS_NORMAL {
+		err = kvCache.Set(key, entity.OriginalUrl, cache.RandomTTL(entity.ExpireAt, now))
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
 	}
 
 	// 将短链接ID添加到布隆过滤器
-	if s.bloomFilter != nil {
-		if !isPublic {
+	s.bloomAdd(isPublic, strconv.FormatInt(entity.ID, 10))
 
-			s.userBloomFilter.Add("", strconv.FormatInt(entity.ID, 10))
-		} else {
-			s.bloomFilter.Add("", strconv.FormatInt(entity.ID, 10))
+	return &proto.Url{
+		Url:       domain + entity.ShortKey,
+		UserID:    in.UserID,
+		ExpireAt:  entity.ExpireAt,
+		MaxClicks: entity.MaxClicks,
+	}, nil
+}
+
+// createEntity 查询可复用的短链或创建新的短链
+// 可复用的短链写入原始URL哈希，并发创建相同原始URL时由唯一索引保证只有一条写入成功，其余请求返回已写入的记录；
+// 带幂等键的请求先按幂等键查询，重复请求返回首次创建的记录
+// 参数:
+//
+//	d: 数据访问对象
+//	in: 生成短链的请求对象
+//	isPublic: 是否为公共链接
+//	now: 当前时间戳
+//
+// 返回:
+//
+//	*data.UrlMapEntity: 短链记录
+//	bool: 是否为本次新建
+//	error: 幂等键已用于参数不同的请求时返回 InvalidArgument，多次写入冲突后返回 Aborted
+func (s *shortUrlService) createEntity(d data.IUrlMapData, in *proto.Url, isPublic bool, now int64) (*data.UrlMapEntity, bool, error) {
+	if in.IdempotencyKey != "" {
+		e, err := d.GetByIdempotencyKey(in.UserID, in.IdempotencyKey)
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			return nil, false, err
+		}
+		if e != nil {
+			return s.idempotentReplay(e, in)
 		}
 	}
 
-	return &proto.Url{
-		Url:    domain + entity.ShortKey,
-		UserID: in.UserID,
-	}, nil
+	// 有期限的短链每次单独创建，不复用已有短链
+	var urlHash []byte
+	if in.ExpireAt == 0 && in.MaxClicks == 0 {
+		e, err := d.GetByOriginal(in.UserID, in.Url)
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			return nil, false, err
+		}
+		if e.ShortKey != "" {
+			e.OriginalUrl = in.Url
+			return &e, false, nil
+		}
+		urlHash = utils.UrlHash(in.Url)
+	}
+
+	generator := s.idGeneratorFactory.Generator(isPublic)
+	for i := 0; i <= createRetries; i++ {
+		id, err := generator.NextID()
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			return nil, false, err
+		}
+		entity := data.UrlMapEntity{
+			ID:             id,
+			UserID:         in.UserID,
+			ShortKey:       s.keyCodec.Encode(id),
+			OriginalUrl:    in.Url,
+			ExpireAt:       in.ExpireAt,
+			MaxClicks:      in.MaxClicks,
+			CreateAt:       now,
+			UpdateAt:       now,
+			UrlHash:        urlHash,
+			IdempotencyKey: in.IdempotencyKey,
+		}
+		err = d.Create(entity)
+		switch {
+		case err == nil:
+			return &entity, true, nil
+		case errors.Is(err, data.ErrIdempotencyKeyExists):
+			// 相同幂等键的请求并发写入，返回先写入的记录
+			e, err := d.GetByIdempotencyKey(in.UserID, in.IdempotencyKey)
+			if err != nil {
+				s.log.Error(zerror.NewByErr(err))
+				return nil, false, err
+			}
+			if e != nil {
+				return s.idempotentReplay(e, in)
+			}
+		case errors.Is(err, data.ErrUrlHashExists):
+			e, err := d.GetByUrlHash(in.UserID, urlHash)
+			if err != nil {
+				s.log.Error(zerror.NewByErr(err))
+				return nil, false, err
+			}
+			switch {
+			case e == nil:
+				// 冲突的记录已释放哈希，重试
+			case e.OriginalUrl != in.Url:
+				// 哈希碰撞，本条记录不参与去重
+				urlHash = nil
+			case e.Status == constants.URL_STATUS_NORMAL:
+				return e, false, nil
+			default:
+				// 已停用、下架或删除的短链不再复用，释放哈希后重新创建
+				if err = d.ReleaseUrlHash(e.ID, now); err != nil {
+					s.log.Error(zerror.NewByErr(err))
+					return nil, false, err
+				}
+			}
+		default:
+			s.log.Error(zerror.NewByErr(err))
+			return nil, false, err
+		}
+	}
+	s.log.Error(zerror.NewByMsg("创建短链冲突 url=" + in.Url))
+	return nil, false, status.Error(codes.Aborted, "创建短链冲突，请重试")
+}
+
+// idempotentReplay 返回幂等键首次创建的记录，原始URL与有效期参数不一致时拒绝
+func (s *shortUrlService) idempotentReplay(e *data.UrlMapEntity, in *proto.Url) (*data.UrlMapEntity, bool, error) {
+	if e.OriginalUrl != in.Url || e.ExpireAt != in.ExpireAt || e.MaxClicks != in.MaxClicks {
+		s.log.Error(zerror.NewByMsg("幂等键已用于其他请求 key=" + in.IdempotencyKey))
+		return nil, false, errIdempotencyMismatch
+	}
+	return e, false, nil
+}
+
+// checkUrl 校验生成短链的参数，校验通过后 in.Url 替换为规范化后的URL，用于去重与存储
+// 参数:
+//
+//	in: 生成短链的请求对象
+//	now: 当前时间戳
+//
+// 返回:
+//
+//	error: 原始URL为空或非法、过期时间已过、访问次数上限为负数、幂等键过长时返回 InvalidArgument
+func (s *shortUrlService) checkUrl(in *proto.Url, now int64) error {
+	url, err := s.urlNormalizer.Normalize(in.Url)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	in.Url = url
+
+	if (in.ExpireAt != 0 && in.ExpireAt <= now) || in.MaxClicks < 0 {
+		err := zerror.NewByMsg("有效期参数检查失败")
+		s.log.Error(err)
+		return status.Error(codes.InvalidArgument, "过期时间必须晚于当前时间，访问次数上限不能为负数")
+	}
+
+	if len(in.IdempotencyKey) > maxIdempotencyKeyLen {
+		err := zerror.NewByMsg("幂等键参数检查失败")
+		s.log.Error(err)
+		return status.Error(codes.InvalidArgument, "幂等键不能超过"+strconv.Itoa(maxIdempotencyKeyLen)+"个字符")
+	}
+	return nil
 }
 
 // GetOriginalUrl 根据短链接键获取原始URL
@@ -187,8 +343,13 @@ func (s *shortUrlService) GetOriginalUrl(ctx context.Context, in *proto.ShortKey
 		return nil, err
 	}
 
-	// 将短链接键转换为十进制ID
-	id := utils.ToBase10(in.Key)
+	// 自定义别名不经过ID转换，单独解析
+	if utils.IsAlias(in.Key) {
+		return s.getAliasUrl(in, isPublic)
+	}
+
+	// 将短链接键转换为记录ID
+	id := s.keyCodec.Decode(in.Key)
 	if id == 0 {
 		err := zerror.NewByMsg("参数检查失败")
 		s.log.Error(err)
@@ -211,6 +372,9 @@ func (s *shortUrlService) GetOriginalUrl(ctx context.Context, in *proto.ShortKey
 	// 根据是否为公共链接创建对应的数据访问对象
 	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
 
+	// 按ID查询并校验短链键，避免通过ID访问到自定义别名的记录
+	find := func() (*data.UrlMapEntity, error) { return s.findByKey(d, in.Key) }
+
 	// 从缓存中获取原始URL
 	originalUrl, err := kvCache.Get(key)
 	if err != nil {
@@ -221,29 +385,11 @@ func (s *shortUrlService) GetOriginalUrl(ctx context.Context, in *proto.ShortKey
 	// 如果缓存未命中，从数据库获取原始URL
 	if originalUrl == "" {
 		// 使用布隆过滤器检查短链接ID是否存在
-		if s.bloomFilter != nil {
-			if !isPublic {
-
-				exists, err := s.userBloomFilter.Exists("", strconv.FormatInt(id, 10))
-				if err != nil {
-					s.log.Warning("布隆过滤器检查失败: " + err.Error())
-				} else if !exists {
-					// 布隆过滤器判断短链接不存在，直接返回错误
-					err := zerror.NewByMsg("短链不存在")
-					s.log.Error(err)
-					return nil, err
-				}
-			} else {
-				exists, err := s.bloomFilter.Exists("", strconv.FormatInt(id, 10))
-				if err != nil {
-					s.log.Warning("布隆过滤器检查失败: " + err.Error())
-				} else if !exists {
-					// 布隆过滤器判断短链接不存在，直接返回错误
-					err := zerror.NewByMsg("短链不存在")
-					s.log.Error(err)
-					return nil, err
-				}
-			}
+		if !s.bloomExists(isPublic, strconv.FormatInt(id, 10)) {
+			// 布隆过滤器判断短链接不存在，直接返回错误
+			err := zerror.NewByMsg("短链不存在")
+			s.log.Error(err)
+			return nil, err
 		}
 
 		// 缓存穿透过滤
@@ -271,29 +417,9 @@ func (s *shortUrlService) GetOriginalUrl(ctx context.Context, in *proto.ShortKey
 
 			if originalUrl == "" {
 				// 从数据库获取原始URL
-				entity, err := d.GetByID(id)
+				originalUrl, err = s.loadOriginalUrl(find, key, kvCache)
 				if err != nil {
-					s.log.Error(err)
-					return nil, zerror.NewByErr(err)
-				}
-
-				if entity == nil {
-					// 数据库中也找不到，缓存空值防止缓存穿透
-					originalUrl = ""
-					// 使用较短的过期时间缓存空值
-					err = kvCache.Set(key, "", 60)
-					if err != nil {
-						s.log.Warning("缓存空值失败: " + err.Error())
-					}
-				} else {
-					originalUrl = entity.OriginalUrl
-					// 使用随机过期时间，避免缓存雪崩
-					ttl := cache.DefaultTTL*80/100 + rand.Intn(cache.DefaultTTL*40/100)
-					err = kvCache.Set(key, originalUrl, ttl)
-					if err != nil {
-						s.log.Error(err)
-						return nil, zerror.NewByErr(err)
-					}
+					return nil, err
 				}
 			}
 		} else {
@@ -307,39 +433,304 @@ func (s *shortUrlService) GetOriginalUrl(ctx context.Context, in *proto.ShortKey
 
 			if originalUrl == "" {
 				// 仍然未命中，从数据库获取
-				entity, err := d.GetByID(id)
+				originalUrl, err = s.loadOriginalUrl(find, key, kvCache)
 				if err != nil {
-					s.log.Error(err)
-					return nil, zerror.NewByErr(err)
+					return nil, err
 				}
+			}
+		}
+		if originalUrl == "" {
+			err := zerror.NewByMsg("短链不存在")
+			s.log.Error(err)
+			return nil, err
+		}
+	}
 
-				if entity == nil {
-					// 数据库中也找不到，缓存空值防止缓存穿透
-					originalUrl = ""
-					// 使用较短的过期时间缓存空值
-					err = kvCache.Set(key, "", 60)
-					if err != nil {
-						s.log.Warning("缓存空值失败: " + err.Error())
-					}
-				} else {
-					originalUrl = entity.OriginalUrl
-					// 使用随机过期时间，避免缓存雪崩
-					ttl := cache.DefaultTTL*80/100 + rand.Intn(cache.DefaultTTL*40/100)
-					err = kvCache.Set(key, originalUrl, ttl)
-					if err != nil {
-						s.log.Error(err)
-						return nil, zerror.NewByErr(err)
-					}
-				}
+	// 增加短链接访问次数，同时校验有效期与访问次数上限（数据库错误时仅记录日志不影响返回）
+	ok, err := d.IncrementTimes(id, 1, time.Now().Unix())
+	if err != nil {
+		s.log.Warning(err)
+	} else if !ok {
+		return nil, errExpired
+	}
+
+	return &proto.Url{
+		Url:    originalUrl,
+		UserID: in.UserID,
+	}, nil
+}
+
+// UpdateTarget 修改短链指向的原始URL，短链键保持不变
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 包含短链键、所属用户ID和新原始URL的请求对象
+//
+// 返回:
+//
+//	*proto.Url: 修改后的短链接地址
+//	error: 参数非法返回 InvalidArgument，短链不存在返回 NotFound，非属主操作返回 PermissionDenied
+func (s *shortUrlService) UpdateTarget(ctx context.Context, in *proto.UpdateTargetRequest) (*proto.Url, error) {
+	isPublic := in.IsPublic
+	if in.UserID != 0 {
+		isPublic = false
+	}
+
+	// 参数有效性验证
+	if in.Key == "" {
+		err := zerror.NewByMsg("参数检查失败")
+		s.log.Error(err)
+		return nil, status.Error(codes.InvalidArgument, "参数检查失败")
+	}
+	url, err := s.urlNormalizer.Normalize(in.Url)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	in.Url = url
+
+	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
+	entity, err := s.ownedEntity(ctx, d, in.Key, in.UserID, isPublic, audit.ActionLinkUpdate, in.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.UpdateOriginalUrl(entity.ID, in.Url, time.Now().Unix())
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+
+	// 删除分布式缓存并通知所有实例删除本地缓存，下次访问时从数据库加载新地址
+	err = s.cacheInvalidator.Invalidate("user_" + entity.ShortKey)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	interceptor.Audit(interceptor.AuditRecord(ctx, audit.ActionLinkUpdate, audit.OutcomeSuccess, in.UserID).
+		Target(auditTarget(false), entity.ShortKey).WithDetail(in.Url))
+
+	return &proto.Url{
+		Url:    s.config.UserShortDomain + entity.ShortKey,
+		UserID: in.UserID,
+	}, nil
+}
+
+// PurgeShortUrl 下架短链（管理操作），短链被禁用后访问时返回短链不存在
+// 操作用户的权限已由 RBAC 拦截器校验
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 包含短链键和短链类型的请求对象
+//
+// 返回:
+//
+//	*proto.Url: 被下架的短链接地址
+//	error: 参数非法、短链不存在或处理失败时返回错误
+func (s *shortUrlService) PurgeShortUrl(ctx context.Context, in *proto.PurgeRequest) (*proto.Url, error) {
+	if in.Key == "" {
+		err := zerror.NewByMsg("参数检查失败")
+		s.log.Error(err)
+		return nil, err
+	}
+
+	d := s.urlMapDataFactory.NewUrlMapData(in.IsPublic)
+	entity, err := s.findByKey(d, in.Key)
+	if err != nil || entity == nil {
+		err = zerror.NewByMsg("短链不存在")
+		s.log.Error(err)
+		return nil, err
+	}
+
+	// 属主已删除的短链保持删除状态
+	if entity.Status != constants.URL_STATUS_DISABLED && entity.Status != constants.URL_STATUS_DELETED {
+		err = d.SetStatus(entity.ID, constants.URL_STATUS_DISABLED, time.Now().Unix())
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+	}
+
+	keyPrefix := ""
+	domain := s.config.ShortDomain
+	if !in.IsPublic {
+		keyPrefix = "user_"
+		domain = s.config.UserShortDomain
+	}
+	err = s.cacheInvalidator.Invalidate(keyPrefix + entity.ShortKey)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	s.log.InfoF("短链已下架 key=%s public=%v operator=%d", entity.ShortKey, in.IsPublic, interceptor.OperatorFromContext(ctx))
+	interceptor.Audit(interceptor.AuditRecord(ctx, audit.ActionLinkPurge, audit.OutcomeSuccess, interceptor.OperatorFromContext(ctx)).
+		Target(auditTarget(in.IsPublic), entity.ShortKey).WithDetail(entity.OriginalUrl))
+
+	return &proto.Url{
+		Url:      domain + entity.ShortKey,
+		UserID:   entity.UserID,
+		IsPublic: in.IsPublic,
+	}, nil
+}
+
+// GetUserPermissions 查询用户的管理权限，结果有短时间缓存
+func (s *shortUrlService) GetUserPermissions(ctx context.Context, in *proto.PermissionRequest) (*proto.Permissions, error) {
+	if in.UserID == 0 {
+		return &proto.Permissions{}, nil
+	}
+	perms, err := s.enforcer.Permissions(in.UserID)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	return &proto.Permissions{Permissions: perms}, nil
+}
+
+// createAlias 使用自定义别名创建短链
+// 别名已被占用时返回 AlreadyExists，同一用户以相同URL重复提交时直接返回已有短链
+// 参数:
+//
+//	ctx: 上下文对象
+//	in: 包含原始URL、用户ID和别名的请求对象
+//	isPublic: 是否为公共链接
+//
+// 返回:
+//
+//	*proto.Url: 包含短链接地址和用户ID的响应对象
+//	error: 别名非法时返回 InvalidArgument，已被占用时返回 AlreadyExists
+func (s *shortUrlService) createAlias(ctx context.Context, in *proto.Url, isPublic bool) (*proto.Url, error) {
+	alias, err := s.aliasPolicy.Normalize(in.Alias)
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	id, err := s.idGeneratorFactory.Generator(isPublic).NextID()
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
+	now := time.Now().Unix()
+	err = d.CreateAlias(data.UrlMapEntity{
+		ID:          id,
+		UserID:      in.UserID,
+		ShortKey:    alias,
+		OriginalUrl: in.Url,
+		ExpireAt:    in.ExpireAt,
+		MaxClicks:   in.MaxClicks,
+		CreateAt:    now,
+		UpdateAt:    now,
+	})
+	if errors.Is(err, data.ErrAliasExists) {
+		existing, err := d.GetByAlias(alias)
+		if err != nil {
+			s.log.Error(zerror.NewByErr(err))
+			return nil, err
+		}
+		if existing == nil || existing.UserID != in.UserID || existing.OriginalUrl != in.Url ||
+			existing.ExpireAt != in.ExpireAt || existing.MaxClicks != in.MaxClicks ||
+			existing.Status != constants.URL_STATUS_NORMAL || existing.Expired(now) {
+			s.log.Error(zerror.NewByMsg("别名已被占用 alias=" + alias))
+			return nil, status.Error(codes.AlreadyExists, data.ErrAliasExists.Error())
+		}
+	} else if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	} else {
+		interceptor.Audit(interceptor.AuditRecord(ctx, audit.ActionLinkCreate, audit.OutcomeSuccess, in.UserID).
+			Target(auditTarget(isPublic), alias).WithDetail(in.Url))
+	}
+
+	keyPrefix, domain := s.keyScope(isPublic)
+	kvCache := s.kvCacheFactory.NewKVCache()
+	defer kvCache.Destroy()
+
+	// 使用随机过期时间，避免缓存雪崩，有期限的短链不超过剩余有效期
+	err = kvCache.Set(keyPrefix+alias, in.Url, cache.RandomTTL(in.ExpireAt, now))
+	if err != nil {
+		s.log.Error(zerror.NewByErr(err))
+		return nil, err
+	}
+	s.bloomAdd(isPublic, alias)
+
+	return &proto.Url{
+		Url:       domain + alias,
+		UserID:    in.UserID,
+		ExpireAt:  in.ExpireAt,
+		MaxClicks: in.MaxClicks,
+	}, nil
+}
+
+// getAliasUrl 根据自定义别名获取原始URL，别名不区分大小写
+// 参数:
+//
+//	in: 包含别名和用户ID的请求参数
+//	isPublic: 是否为公共链接
+//
+// 返回:
+//
+//	*proto.Url: 包含原始URL和用户ID的响应对象
+//	error: 别名不存在、已禁用或读取失败时返回错误
+func (s *shortUrlService) getAliasUrl(in *proto.ShortKey, isPublic bool) (*proto.Url, error) {
+	alias := strings.ToLower(in.Key)
+	keyPrefix, _ := s.keyScope(isPublic)
+	key := keyPrefix + alias
+
+	kvCache := s.kvCacheFactory.NewKVCache()
+	defer kvCache.Destroy()
+	d := s.urlMapDataFactory.NewUrlMapData(isPublic)
+
+	originalUrl, err := kvCache.Get(key)
+	if err != nil {
+		s.log.Error(err)
+		return nil, zerror.NewByErr(err)
+	}
+
+	if originalUrl == "" {
+		// 别名创建时加入布隆过滤器，不存在的别名不查询数据库
+		if !s.bloomExists(isPublic, alias) {
+			err := zerror.NewByMsg("短链不存在")
+			s.log.Error(err)
+			return nil, err
+		}
+
+		// 使用分布式锁防止缓存击穿，未获取到锁时稍后重查缓存
+		lockKey := "lock:" + key
+		lock := s.lockFactory.NewDistributedLock()
+		locked, err := lock.Lock(lockKey, 5*time.Second)
+		if err != nil {
+			s.log.Warning("获取分布式锁失败: " + err.Error())
+		} else if locked {
+			defer lock.Unlock(lockKey)
+		} else {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		originalUrl, err = kvCache.Get(key)
+		if err != nil {
+			s.log.Error(err)
+			return nil, zerror.NewByErr(err)
+		}
+		if originalUrl == "" {
+			originalUrl, err = s.loadOriginalUrl(func() (*data.UrlMapEntity, error) { return d.GetByAlias(alias) }, key, kvCache)
+			if err != nil {
+				return nil, err
 			}
 		}
+		if originalUrl == "" {
+			err := zerror.NewByMsg("短链不存在")
+			s.log.Error(err)
+			return nil, err
+		}
 	}
 
-	// 增加短链接访问次数（错误时仅记录日志不影响返回）
-	err = d.IncrementTimes(id, 1, time.Now().Unix())
+	// 增加短链接访问次数，同时校验有效期与访问次数上限（数据库错误时仅记录日志不影响返回）
+	ok, err := d.IncrementTimesByAlias(alias, 1, time.Now().Unix())
 	if err != nil {
 		s.log.Warning(err)
-		err = nil
+	} else if !ok {
+		return nil, errExpired
 	}
 
 	return &proto.Url{
@@ -348,6 +739,126 @@ func (s *shortUrlService) GetOriginalUrl(ctx context.Context, in *proto.ShortKey
 	}, nil
 }
 
+// findByKey 按短链键查询记录，支持生成的短链键与自定义别名，未找到时返回nil
+func (s *shortUrlService) findByKey(d data.IUrlMapData, key string) (*data.UrlMapEntity, error) {
+	if utils.IsAlias(key) {
+		return d.GetByAlias(strings.ToLower(key))
+	}
+	id := s.keyCodec.Decode(key)
+	if id == 0 {
+		return nil, nil
+	}
+	entity, err := d.GetByID(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && entity.ShortKey != key) {
+		return nil, nil
+	}
+	return entity, err
+}
+
+// keyScope 返回短链类型对应的缓存键前缀与短链域名
+func (s *shortUrlService) keyScope(isPublic bool) (string, string) {
+	if isPublic {
+		return "", s.config.ShortDomain
+	}
+	return "user_", s.config.UserShortDomain
+}
+
+// bloomAdd 将短链ID或别名加入对应类型的布隆过滤器
+func (s *shortUrlService) bloomAdd(isPublic bool, member string) {
+	if s.bloomFilter == nil {
+		return
+	}
+	if isPublic {
+		s.bloomFilter.Add("", member)
+	} else {
+		s.userBloomFilter.Add("", member)
+	}
+}
+
+// bloomAddMulti 将多个短链ID或别名一次加入对应类型的布隆过滤器
+func (s *shortUrlService) bloomAddMulti(isPublic bool, members []string) {
+	if s.bloomFilter == nil || len(members) == 0 {
+		return
+	}
+	filter := s.bloomFilter
+	if !isPublic {
+		filter = s.userBloomFilter
+	}
+	if err := filter.AddMulti("", members); err != nil {
+		s.log.Warning("布隆过滤器批量添加失败: " + err.Error())
+	}
+}
+
+// bloomExists 使用布隆过滤器检查短链ID或别名是否可能存在，检查失败时按存在处理
+func (s *shortUrlService) bloomExists(isPublic bool, member string) bool {
+	if s.bloomFilter == nil {
+		return true
+	}
+	filter := s.bloomFilter
+	if !isPublic {
+		filter = s.userBloomFilter
+	}
+	exists, err := filter.Exists("", member)
+	if err != nil {
+		s.log.Warning("布隆过滤器检查失败: " + err.Error())
+		return true
+	}
+	return exists
+}
+
+// auditTarget 返回审计记录的对象类型，使用短链所在的表名
+func auditTarget(isPublic bool) string {
+	if isPublic {
+		return constants.TABLENAME_URL_MAP
+	}
+	return constants.TABLENAME_URL_MAP_USER
+}
+
+// loadOriginalUrl 缓存未命中时从数据库加载原始URL并回填缓存
+// 参数:
+//
+//	find: 从数据库查询短链记录，未找到时返回nil
+//	key: 缓存键
+//	kvCache: 键值缓存实例
+//
+// 返回值:
+//
+//	string: 原始URL
+//	error: 短链不存在、已禁用、已过期或读写失败时返回错误
+func (s *shortUrlService) loadOriginalUrl(find func() (*data.UrlMapEntity, error), key string, kvCache cache.KVCache) (string, error) {
+	entity, err := find()
+	if err != nil {
+		s.log.Error(err)
+		return "", zerror.NewByErr(err)
+	}
+
+	if entity == nil || entity.Status != constants.URL_STATUS_NORMAL {
+		// 数据库中找不到、已禁用、已停用或已删除，缓存空值防止缓存穿透
+		// 使用较短的过期时间缓存空值
+		err = kvCache.Set(key, "", 60)
+		if err != nil {
+			s.log.Warning("缓存空值失败: " + err.Error())
+		}
+		if entity != nil {
+			return "", zerror.NewByMsg("短链已禁用")
+		}
+		return "", nil
+	}
+
+	now := time.Now().Unix()
+	if entity.Expired(now) {
+		return "", errExpired
+	}
+
+	// 使用随机过期时间，避免缓存雪崩，有期限的短链不超过剩余有效期
+	err = kvCache.Set(key, entity.OriginalUrl, cache.RandomTTL(entity.ExpireAt, now))
+	if err != nil {
+		s.log.Error(err)
+		return "", zerror.NewByErr(err)
+	}
+	return entity.OriginalUrl, nil
+}
+
 // idFilter 验证短链ID是否合法
 // 参数:
 //
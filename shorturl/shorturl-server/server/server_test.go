@@ -0,0 +1,432 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"shorturl/pkg/config"
+	"shorturl/pkg/constants"
+	"shorturl/pkg/log"
+	"shorturl/pkg/utils"
+	"shorturl/proto"
+	"shorturl/shorturl-server/cache"
+	"shorturl/shorturl-server/data"
+	"shorturl/shorturl-server/idgen"
+	"testing"
+)
+
+// fakeUrlMapData 内存中的短链表，按唯一索引（别名、原始URL哈希、幂等键）拒绝重复写入
+type fakeUrlMapData struct {
+	isPublic bool
+	rows     map[int64]*data.UrlMapEntity
+	aliases  map[int64]bool
+	// beforeCreate 每次写入前调用，用于模拟并发请求先写入的记录
+	beforeCreate func(f *fakeUrlMapData)
+	// createErrs 依次作为写入结果返回，用完后按唯一索引正常写入
+	createErrs []error
+	creates    int
+	released   []int64
+	query      *data.UrlMapQuery
+}
+
+func newFakeUrlMapData(isPublic bool) *fakeUrlMapData {
+	return &fakeUrlMapData{
+		isPublic: isPublic,
+		rows:     make(map[int64]*data.UrlMapEntity),
+		aliases:  make(map[int64]bool),
+	}
+}
+
+// put 直接写入一条记录，不校验唯一索引
+func (f *fakeUrlMapData) put(e data.UrlMapEntity) *data.UrlMapEntity {
+	f.rows[e.ID] = &e
+	return &e
+}
+
+// sameScope 用户短链表的唯一索引包含用户ID，公共短链表不区分用户
+func (f *fakeUrlMapData) sameScope(e *data.UrlMapEntity, userID int64) bool {
+	return f.isPublic || e.UserID == userID
+}
+
+func (f *fakeUrlMapData) duplicate(e data.UrlMapEntity, alias bool) error {
+	for id, r := range f.rows {
+		switch {
+		case alias && f.aliases[id] && r.ShortKey == e.ShortKey:
+			return data.ErrAliasExists
+		case e.UrlHash != nil && bytes.Equal(r.UrlHash, e.UrlHash) && f.sameScope(r, e.UserID):
+			return data.ErrUrlHashExists
+		case e.IdempotencyKey != "" && r.IdempotencyKey == e.IdempotencyKey && f.sameScope(r, e.UserID):
+			return data.ErrIdempotencyKeyExists
+		}
+	}
+	return nil
+}
+
+func (f *fakeUrlMapData) insert(entities []data.UrlMapEntity, alias bool) error {
+	f.creates++
+	if f.beforeCreate != nil {
+		f.beforeCreate(f)
+	}
+	if len(f.createErrs) > 0 {
+		err := f.createErrs[0]
+		f.createErrs = f.createErrs[1:]
+		return err
+	}
+	for _, e := range entities {
+		if err := f.duplicate(e, alias); err != nil {
+			return err
+		}
+	}
+	for _, e := range entities {
+		f.put(e)
+		f.aliases[e.ID] = alias
+	}
+	return nil
+}
+
+func (f *fakeUrlMapData) Create(e data.UrlMapEntity) error {
+	return f.insert([]data.UrlMapEntity{e}, false)
+}
+
+func (f *fakeUrlMapData) GetByID(id int64) (*data.UrlMapEntity, error) {
+	if e, ok := f.rows[id]; ok {
+		c := *e
+		return &c, nil
+	}
+	return &data.UrlMapEntity{}, sql.ErrNoRows
+}
+
+func (f *fakeUrlMapData) GetByOriginal(userID int64, originalUrl string) (data.UrlMapEntity, error) {
+	found, _ := f.GetByOriginals(userID, []string{originalUrl})
+	return found[originalUrl], nil
+}
+
+func (f *fakeUrlMapData) GetByOriginals(userID int64, originalUrls []string) (map[string]data.UrlMapEntity, error) {
+	found := make(map[string]data.UrlMapEntity)
+	for _, u := range originalUrls {
+		for id, e := range f.rows {
+			if f.sameScope(e, userID) && bytes.Equal(e.UrlHash, utils.UrlHash(u)) && e.OriginalUrl == u &&
+				e.Status == constants.URL_STATUS_NORMAL && !f.aliases[id] && e.ExpireAt == 0 && e.MaxClicks == 0 {
+				found[u] = *e
+			}
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeUrlMapData) GetByUrlHash(userID int64, urlHash []byte) (*data.UrlMapEntity, error) {
+	for _, e := range f.rows {
+		if f.sameScope(e, userID) && e.UrlHash != nil && bytes.Equal(e.UrlHash, urlHash) {
+			c := *e
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeUrlMapData) GetByIdempotencyKey(userID int64, key string) (*data.UrlMapEntity, error) {
+	for _, e := range f.rows {
+		if f.sameScope(e, userID) && e.IdempotencyKey == key {
+			c := *e
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeUrlMapData) ReleaseUrlHash(id int64, now int64) error {
+	f.rows[id].UrlHash = nil
+	f.released = append(f.released, id)
+	return nil
+}
+
+func (f *fakeUrlMapData) BatchCreate(entities []data.UrlMapEntity) error {
+	return f.insert(entities, false)
+}
+
+func (f *fakeUrlMapData) CreateAlias(e data.UrlMapEntity) error {
+	return f.insert([]data.UrlMapEntity{e}, true)
+}
+
+func (f *fakeUrlMapData) GetByAlias(alias string) (*data.UrlMapEntity, error) {
+	for id, e := range f.rows {
+		if f.aliases[id] && e.ShortKey == alias {
+			c := *e
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeUrlMapData) IncrementTimesByAlias(alias string, incrementTimes int, now int64) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeUrlMapData) IncrementTimes(id int64, incrementTimes int, now int64) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeUrlMapData) GetTopUrls(limit int, now int64) ([]data.UrlMapEntity, error) {
+	return nil, nil
+}
+
+func (f *fakeUrlMapData) UpdateOriginalUrl(id int64, originalUrl string, now int64) error {
+	f.rows[id].OriginalUrl = originalUrl
+	f.rows[id].UrlHash = nil
+	return nil
+}
+
+func (f *fakeUrlMapData) SetStatus(id int64, status int, now int64) error {
+	f.rows[id].Status = status
+	return nil
+}
+
+func (f *fakeUrlMapData) Delete(id int64, now int64) error {
+	f.rows[id].Status = constants.URL_STATUS_DELETED
+	return nil
+}
+
+func (f *fakeUrlMapData) List(q *data.UrlMapQuery) ([]data.UrlMapEntity, int64, error) {
+	f.query = q
+	var list []data.UrlMapEntity
+	for _, e := range f.rows {
+		list = append(list, *e)
+	}
+	return list, int64(len(list)), nil
+}
+
+func (f *fakeUrlMapData) MaxID() (int64, error) {
+	var max int64
+	for id := range f.rows {
+		if id > max {
+			max = id
+		}
+	}
+	return max, nil
+}
+
+// fakeUrlMapDataFactory 公共短链与用户短链各使用一张内存表
+type fakeUrlMapDataFactory struct {
+	public, user *fakeUrlMapData
+}
+
+func (f *fakeUrlMapDataFactory) NewUrlMapData(isPublic bool) data.IUrlMapData {
+	if isPublic {
+		return f.public
+	}
+	return f.user
+}
+
+// fakeIDGenerator 从 next 开始递增分配ID，err 不为空时分配失败
+type fakeIDGenerator struct {
+	next int64
+	err  error
+}
+
+func (g *fakeIDGenerator) NextID() (int64, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+	g.next++
+	return g.next, nil
+}
+
+type fakeIDGeneratorFactory struct {
+	generator *fakeIDGenerator
+}
+
+func (f *fakeIDGeneratorFactory) Generator(isPublic bool) idgen.IDGenerator {
+	return f.generator
+}
+
+// fakeKVCache 内存中的键值缓存
+type fakeKVCache struct {
+	values map[string]string
+}
+
+func (c *fakeKVCache) Get(key string) (string, error) { return c.values[key], nil }
+
+func (c *fakeKVCache) Set(key, value string, ttl int) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *fakeKVCache) SetMulti(entries []cache.Entry) error {
+	for _, e := range entries {
+		c.values[e.Key] = e.Value
+	}
+	return nil
+}
+
+func (c *fakeKVCache) Destroy() {}
+
+type fakeCacheFactory struct {
+	kvCache *fakeKVCache
+}
+
+func (f *fakeCacheFactory) NewKVCache() cache.KVCache { return f.kvCache }
+
+// fakeInvalidator 记录被删除的缓存键
+type fakeInvalidator struct {
+	keys []string
+}
+
+func (i *fakeInvalidator) Invalidate(keys ...string) error {
+	i.keys = append(i.keys, keys...)
+	return nil
+}
+
+func (i *fakeInvalidator) Subscribe(ctx context.Context) {}
+
+// testService 使用内存表与内存缓存的短链服务
+type testService struct {
+	*shortUrlService
+	public, user *fakeUrlMapData
+	ids          *fakeIDGenerator
+	kvCache      *fakeKVCache
+	invalidator  *fakeInvalidator
+}
+
+func newTestService() *testService {
+	logger := log.NewLogger()
+	logger.SetOutput(&bytes.Buffer{})
+	cnf := &config.Config{ShortDomain: "https://s.test/", UserShortDomain: "https://u.test/"}
+	ts := &testService{
+		public:      newFakeUrlMapData(true),
+		user:        newFakeUrlMapData(false),
+		ids:         &fakeIDGenerator{next: 100},
+		kvCache:     &fakeKVCache{values: make(map[string]string)},
+		invalidator: &fakeInvalidator{},
+	}
+	ts.shortUrlService = &shortUrlService{
+		config:             cnf,
+		log:                logger,
+		urlMapDataFactory:  &fakeUrlMapDataFactory{public: ts.public, user: ts.user},
+		kvCacheFactory:     &fakeCacheFactory{kvCache: ts.kvCache},
+		cacheInvalidator:   ts.invalidator,
+		aliasPolicy:        utils.NewAliasPolicy(nil, nil),
+		urlNormalizer:      utils.NewUrlNormalizer(false, nil, false),
+		keyCodec:           utils.NewBase62Codec(),
+		idGeneratorFactory: &fakeIDGeneratorFactory{generator: ts.ids},
+	}
+	return ts
+}
+
+func TestCreateEntity(t *testing.T) {
+	const url = "https://example.com/a"
+	const now = 1700000000
+	hash := utils.UrlHash(url)
+	errStorage := errors.New("storage unavailable")
+
+	cases := []struct {
+		name    string
+		in      *proto.Url
+		setup   func(f *fakeUrlMapData)
+		code    codes.Code
+		err     error
+		wantID  int64
+		created bool
+		// 新建记录是否写入原始URL哈希
+		hashed  bool
+		creates int
+	}{
+		{"new reusable", &proto.Url{Url: url, UserID: 7}, nil, codes.OK, nil, 101, true, true, 1},
+		{"reuse existing", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, UrlHash: hash})
+		}, codes.OK, nil, 50, false, true, 0},
+		// 其他用户的短链不复用
+		{"other user", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.put(data.UrlMapEntity{ID: 50, UserID: 8, ShortKey: utils.ToBase62(50), OriginalUrl: url, UrlHash: hash})
+		}, codes.OK, nil, 101, true, true, 1},
+		// 有期限的短链每次单独创建，不写入哈希
+		{"limited", &proto.Url{Url: url, UserID: 7, MaxClicks: 5}, func(f *fakeUrlMapData) {
+			f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, UrlHash: hash})
+		}, codes.OK, nil, 101, true, false, 1},
+		// 幂等键重放返回首次创建的记录，包括已停用的记录；参数不同时拒绝
+		{"idempotent replay", &proto.Url{Url: url, UserID: 7, MaxClicks: 5, IdempotencyKey: "k1"}, func(f *fakeUrlMapData) {
+			f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, MaxClicks: 5, IdempotencyKey: "k1", Status: constants.URL_STATUS_PAUSED})
+		}, codes.OK, nil, 50, false, false, 0},
+		{"idempotent mismatch", &proto.Url{Url: url, UserID: 7, IdempotencyKey: "k1"}, func(f *fakeUrlMapData) {
+			f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, MaxClicks: 5, IdempotencyKey: "k1"})
+		}, codes.InvalidArgument, nil, 0, false, false, 0},
+		{"idempotent concurrent", &proto.Url{Url: url, UserID: 7, MaxClicks: 5, IdempotencyKey: "k1"}, func(f *fakeUrlMapData) {
+			f.beforeCreate = func(f *fakeUrlMapData) {
+				f.beforeCreate = nil
+				f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, MaxClicks: 5, IdempotencyKey: "k1"})
+			}
+		}, codes.OK, nil, 50, false, false, 1},
+		// 并发请求先写入了相同原始URL的短链，返回先写入的记录
+		{"hash concurrent", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.beforeCreate = func(f *fakeUrlMapData) {
+				f.beforeCreate = nil
+				f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, UrlHash: hash})
+			}
+		}, codes.OK, nil, 50, false, true, 1},
+		// 哈希碰撞时新记录不参与去重
+		{"hash collision", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: "https://example.com/other", UrlHash: hash})
+		}, codes.OK, nil, 102, true, false, 2},
+		// 冲突的记录在查询前已释放哈希，重试后写入
+		{"hash released", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.createErrs = []error{data.ErrUrlHashExists}
+		}, codes.OK, nil, 102, true, true, 2},
+		// 已停用的短链不再复用，释放哈希后重新创建
+		{"paused holder", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, UrlHash: hash, Status: constants.URL_STATUS_PAUSED})
+		}, codes.OK, nil, 102, true, true, 2},
+		{"retries exhausted", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.createErrs = []error{data.ErrUrlHashExists, data.ErrUrlHashExists, data.ErrUrlHashExists}
+		}, codes.Aborted, nil, 0, false, false, createRetries + 1},
+		{"storage error", &proto.Url{Url: url, UserID: 7}, func(f *fakeUrlMapData) {
+			f.createErrs = []error{errStorage}
+		}, codes.Unknown, errStorage, 0, false, false, 1},
+	}
+	for _, c := range cases {
+		ts := newTestService()
+		if c.setup != nil {
+			c.setup(ts.user)
+		}
+		e, created, err := ts.createEntity(ts.user, c.in, false, now)
+		if status.Code(err) != c.code || (c.err != nil && !errors.Is(err, c.err)) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.code)
+			continue
+		}
+		if ts.user.creates != c.creates {
+			t.Errorf("%s: creates=%d, want %d", c.name, ts.user.creates, c.creates)
+		}
+		if err != nil {
+			continue
+		}
+		if e.ID != c.wantID || created != c.created {
+			t.Errorf("%s: got id=%d created=%v, want id=%d created=%v", c.name, e.ID, created, c.wantID, c.created)
+			continue
+		}
+		if !created {
+			continue
+		}
+		row := ts.user.rows[e.ID]
+		if row.ShortKey != utils.ToBase62(e.ID) || row.OriginalUrl != url || (row.UrlHash != nil) != c.hashed {
+			t.Errorf("%s: row %+v", c.name, row)
+		}
+	}
+}
+
+func TestCreateEntityReleasesPausedHash(t *testing.T) {
+	ts := newTestService()
+	const url = "https://example.com/a"
+	ts.user.put(data.UrlMapEntity{ID: 50, UserID: 7, ShortKey: utils.ToBase62(50), OriginalUrl: url, UrlHash: utils.UrlHash(url), Status: constants.URL_STATUS_PAUSED})
+
+	if _, _, err := ts.createEntity(ts.user, &proto.Url{Url: url, UserID: 7}, false, 1700000000); err != nil {
+		t.Fatal(err)
+	}
+	if len(ts.user.released) != 1 || ts.user.released[0] != 50 || ts.user.rows[50].UrlHash != nil {
+		t.Errorf("released %v", ts.user.released)
+	}
+	// 已停用的短链保持停用，不会被重新启用
+	if ts.user.rows[50].Status != constants.URL_STATUS_PAUSED {
+		t.Errorf("status %d", ts.user.rows[50].Status)
+	}
+}
@@ -8,13 +8,22 @@ use mediahub;
 CREATE TABLE `mediahub`.`url_map` (
                                       `id` BIGINT(20) NOT NULL AUTO_INCREMENT,  -- 主键，自增ID
                                       `short_key` VARCHAR(45) NOT NULL DEFAULT '',  -- 短链接的唯一标识
-                                      `original_url` VARCHAR(512) NOT NULL DEFAULT '',  -- 原始URL
+                                      `alias` VARCHAR(45) NULL DEFAULT NULL,  -- 自定义别名，与 `short_key` 相同；生成的短链为NULL
+                                      `original_url` TEXT NOT NULL,  -- 原始URL，最长8192字节，按 `url_hash` 查询
+                                      `url_hash` BINARY(16) NULL DEFAULT NULL,  -- 原始URL的 SHA-256 前16字节，只有可复用的短链有值，用于去重与按原始URL查询
+                                      `idempotency_key` VARCHAR(64) NULL DEFAULT NULL,  -- 调用方提供的幂等键
                                       `times` INT NOT NULL DEFAULT 0,  -- 该短链接被访问的次数
+                                      `status` TINYINT NOT NULL DEFAULT 0,  -- 状态：0正常，1禁用
+                                      `expire_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 过期时间戳，0表示永不过期
+                                      `max_clicks` INT NOT NULL DEFAULT 0,  -- 最大访问次数，0表示不限制
                                       `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
                                       `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录最后一次更新时间的时间戳
                                       PRIMARY KEY (`id`),  -- 设置 `id` 为主键
                                       INDEX `index_short_key` (`short_key` ASC) VISIBLE,  -- 在 `short_key` 字段上创建索引
-                                      INDEX `index_original_url` (`original_url` ASC) VISIBLE)  -- 在 `original_url` 字段上创建索引
+                                      UNIQUE INDEX `unique_alias` (`alias` ASC) VISIBLE,  -- 别名在表内唯一
+                                      UNIQUE INDEX `unique_url_hash` (`url_hash` ASC) VISIBLE,  -- 同一原始URL只有一个可复用的短链
+                                      UNIQUE INDEX `unique_idempotency` (`idempotency_key` ASC) VISIBLE,  -- 幂等键在表内唯一
+                                      INDEX `index_expire_at` (`expire_at` ASC) VISIBLE)  -- 归档过期短链时使用
     ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
 DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
 COMMENT = 'url关系表';  -- 表注释，说明该表用于存储URL映射关系
@@ -24,17 +33,223 @@ CREATE TABLE `mediahub`.`url_map_user` (
                                            `id` BIGINT(20) NOT NULL AUTO_INCREMENT,  -- 主键，自增ID
                                            `user_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 用户ID，关联到具体用户
                                            `short_key` VARCHAR(45) NOT NULL DEFAULT '',  -- 短链接的唯一标识
-                                           `original_url` VARCHAR(512) NOT NULL DEFAULT '',  -- 原始URL
+                                           `alias` VARCHAR(45) NULL DEFAULT NULL,  -- 自定义别名，与 `short_key` 相同；生成的短链为NULL
+                                           `original_url` TEXT NOT NULL,  -- 原始URL，最长8192字节，按 `url_hash` 查询
+                                           `url_hash` BINARY(16) NULL DEFAULT NULL,  -- 原始URL的 SHA-256 前16字节，只有可复用的短链有值，用于去重与按原始URL查询
+                                           `idempotency_key` VARCHAR(64) NULL DEFAULT NULL,  -- 调用方提供的幂等键
                                            `times` INT NOT NULL DEFAULT 0,  -- 该短链接被访问的次数
+                                           `status` TINYINT NOT NULL DEFAULT 0,  -- 状态：0正常，1禁用
+                                           `expire_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 过期时间戳，0表示永不过期
+                                           `max_clicks` INT NOT NULL DEFAULT 0,  -- 最大访问次数，0表示不限制
                                            `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
                                            `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录最后一次更新时间的时间戳
                                            PRIMARY KEY (`id`),  -- 设置 `id` 为主键
                                            INDEX `index_short_key` (`short_key` ASC) VISIBLE,  -- 在 `short_key` 字段上创建索引
-                                           INDEX `index_original_url` (`original_url` ASC) VISIBLE)  -- 在 `original_url` 字段上创建索引
+                                           INDEX `index_user_id` (`user_id` ASC, `id` ASC) VISIBLE,  -- 按用户分页查询短链
+                                           UNIQUE INDEX `unique_alias` (`alias` ASC) VISIBLE,  -- 别名在表内唯一
+                                           UNIQUE INDEX `unique_url_hash` (`user_id` ASC, `url_hash` ASC) VISIBLE,  -- 同一用户的同一原始URL只有一个可复用的短链
+                                           UNIQUE INDEX `unique_idempotency` (`user_id` ASC, `idempotency_key` ASC) VISIBLE,  -- 幂等键在用户内唯一
+                                           INDEX `index_expire_at` (`expire_at` ASC) VISIBLE)  -- 归档过期短链时使用
     ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
 DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
 COMMENT = 'url关系表';  -- 表注释，说明该表用于存储用户与URL的映射关系
 
+-- 创建 `url_map_archive` 表，归档已过期的公共短链，结构与 `url_map` 相同
+CREATE TABLE `mediahub`.`url_map_archive` (
+                                              `id` BIGINT(20) NOT NULL,  -- 原记录ID
+                                              `short_key` VARCHAR(45) NOT NULL DEFAULT '',  -- 短链接的唯一标识
+                                              `alias` VARCHAR(45) NULL DEFAULT NULL,  -- 自定义别名
+                                              `original_url` TEXT NOT NULL,  -- 原始URL
+                                              `times` INT NOT NULL DEFAULT 0,  -- 该短链接被访问的次数
+                                              `status` TINYINT NOT NULL DEFAULT 0,  -- 状态：0正常，1禁用
+                                              `expire_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 过期时间戳
+                                              `max_clicks` INT NOT NULL DEFAULT 0,  -- 最大访问次数
+                                              `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
+                                              `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录最后一次更新时间的时间戳
+                                              `archive_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 归档时间戳
+                                              PRIMARY KEY (`id`),  -- 沿用原记录ID
+                                              INDEX `index_short_key` (`short_key` ASC) VISIBLE)  -- 在 `short_key` 字段上创建索引
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = 'url关系归档表';  -- 表注释
+
+-- 创建 `url_map_user_archive` 表，归档已过期的用户短链，结构与 `url_map_user` 相同
+CREATE TABLE `mediahub`.`url_map_user_archive` (
+                                                   `id` BIGINT(20) NOT NULL,  -- 原记录ID
+                                                   `user_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 用户ID
+                                                   `short_key` VARCHAR(45) NOT NULL DEFAULT '',  -- 短链接的唯一标识
+                                                   `alias` VARCHAR(45) NULL DEFAULT NULL,  -- 自定义别名
+                                                   `original_url` TEXT NOT NULL,  -- 原始URL
+                                                   `times` INT NOT NULL DEFAULT 0,  -- 该短链接被访问的次数
+                                                   `status` TINYINT NOT NULL DEFAULT 0,  -- 状态：0正常，1禁用
+                                                   `expire_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 过期时间戳
+                                                   `max_clicks` INT NOT NULL DEFAULT 0,  -- 最大访问次数
+                                                   `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
+                                                   `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录最后一次更新时间的时间戳
+                                                   `archive_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 归档时间戳
+                                                   PRIMARY KEY (`id`),  -- 沿用原记录ID
+                                                   INDEX `index_user_id` (`user_id` ASC) VISIBLE)  -- 按用户查询归档记录
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = 'url关系归档表';  -- 表注释，说明该表用于归档用户短链
+
+-- 创建 `id_sequence` 表，短链记录ID按号段从该表预留，每张短链表一个序列
+CREATE TABLE `mediahub`.`id_sequence` (
+                                          `name` VARCHAR(45) NOT NULL,  -- 序列名称，使用短链表名
+                                          `max_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 已预留的最大ID
+                                          `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 最后一次预留号段的时间戳
+                                          PRIMARY KEY (`name`))  -- 设置 `name` 为主键
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = 'ID号段表';  -- 表注释，说明该表用于分配短链记录ID
+
+-- 创建 `media` 表，用于存储上传的媒体及其访问控制信息
+CREATE TABLE `mediahub`.`media` (
+                                    `id` BIGINT(20) NOT NULL AUTO_INCREMENT,  -- 主键，自增ID
+                                    `user_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 所属用户ID，0表示公共上传
+                                    `md5` VARCHAR(32) NOT NULL DEFAULT '',  -- 文件内容的md5
+                                    `object_path` VARCHAR(255) NOT NULL DEFAULT '',  -- 对象存储中的路径
+                                    `original_path` VARCHAR(255) NOT NULL DEFAULT '',  -- 未加水印的原图路径（私有对象），未加水印时为空
+                                    `url` VARCHAR(512) NOT NULL DEFAULT '',  -- 公开访问地址，私有媒体为空
+                                    `size` BIGINT(20) NOT NULL DEFAULT 0,  -- 文件大小（字节）
+                                    `saved_bytes` BIGINT(20) NOT NULL DEFAULT 0,  -- 上传优化节省的字节数
+                                    `short_url` VARCHAR(255) NOT NULL DEFAULT '',  -- 短链接
+                                    `visibility` TINYINT NOT NULL DEFAULT 0,  -- 可见性：0公开，1私有
+                                    `version` INT NOT NULL DEFAULT 1,  -- 当前版本号
+                                    `sign_version` INT NOT NULL DEFAULT 0,  -- 签名版本，撤销访问时递增
+                                    `revoked` TINYINT(1) NOT NULL DEFAULT 0,  -- 是否已撤销访问
+                                    `deleted_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 移入回收站的时间戳，0表示未删除
+                                    `purge_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 计划彻底清除的时间戳
+                                    `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
+                                    `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录最后一次更新时间的时间戳
+                                    PRIMARY KEY (`id`),  -- 设置 `id` 为主键
+                                    INDEX `index_user_id` (`user_id` ASC) VISIBLE,  -- 在 `user_id` 字段上创建索引
+                                    INDEX `index_purge_at` (`purge_at` ASC) VISIBLE)  -- 在 `purge_at` 字段上创建索引，供定时清理使用
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = '媒体表';  -- 表注释，说明该表用于存储媒体信息
+
+-- 创建 `media_version` 表，用于存储媒体的历史版本
+CREATE TABLE `mediahub`.`media_version` (
+                                            `id` BIGINT(20) NOT NULL AUTO_INCREMENT,  -- 主键，自增ID
+                                            `media_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 所属媒体ID
+                                            `version` INT NOT NULL DEFAULT 0,  -- 版本号，从1开始递增
+                                            `md5` VARCHAR(32) NOT NULL DEFAULT '',  -- 该版本文件内容的md5
+                                            `object_path` VARCHAR(255) NOT NULL DEFAULT '',  -- 该版本在对象存储中的路径
+                                            `original_path` VARCHAR(255) NOT NULL DEFAULT '',  -- 该版本未加水印的原图路径，未加水印时为空
+                                            `url` VARCHAR(512) NOT NULL DEFAULT '',  -- 该版本的公开访问地址，私有媒体为空
+                                            `size` BIGINT(20) NOT NULL DEFAULT 0,  -- 该版本文件大小（字节）
+                                            `saved_bytes` BIGINT(20) NOT NULL DEFAULT 0,  -- 该版本上传优化节省的字节数
+                                            `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
+                                            PRIMARY KEY (`id`),  -- 设置 `id` 为主键
+                                            UNIQUE INDEX `index_media_version` (`media_id` ASC, `version` ASC) VISIBLE,  -- 同一媒体的版本号唯一
+                                            INDEX `index_object_path` (`object_path` ASC) VISIBLE)  -- 在 `object_path` 字段上创建索引，供清除时统计引用
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = '媒体版本表';  -- 表注释，说明该表用于存储媒体历史版本
+
+-- 创建 `watermark` 表，用于存储用户的水印设置
+CREATE TABLE `mediahub`.`watermark` (
+                                        `user_id` BIGINT(20) NOT NULL,  -- 用户ID
+                                        `enable` TINYINT(1) NOT NULL DEFAULT 0,  -- 是否为公开上传添加水印
+                                        `text` VARCHAR(64) NOT NULL DEFAULT '',  -- 文字水印
+                                        `image_path` VARCHAR(255) NOT NULL DEFAULT '',  -- 图片水印在对象存储中的路径，为空时使用文字水印
+                                        `position` VARCHAR(16) NOT NULL DEFAULT 'bottom-right',  -- 水印位置
+                                        `opacity` DOUBLE NOT NULL DEFAULT 0.5,  -- 不透明度
+                                        `scale` DOUBLE NOT NULL DEFAULT 0.2,  -- 水印宽度占原图宽度的比例
+                                        `margin` INT NOT NULL DEFAULT 0,  -- 水印与图片边缘的距离（像素）
+                                        `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录最后一次更新时间的时间戳
+                                        PRIMARY KEY (`user_id`))  -- 每个用户一条设置
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = '水印设置表';  -- 表注释，说明该表用于存储用户水印设置
+
+-- 创建 `api_key` 表，用于存储用户创建的 API Key（只保存哈希）
+CREATE TABLE `mediahub`.`api_key` (
+                                      `id` BIGINT(20) NOT NULL AUTO_INCREMENT,  -- 主键，自增ID
+                                      `user_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 所属用户ID
+                                      `name` VARCHAR(64) NOT NULL DEFAULT '',  -- 名称
+                                      `display` VARCHAR(16) NOT NULL DEFAULT '',  -- 密钥开头的若干字符，用于辨认密钥
+                                      `key_hash` CHAR(64) NOT NULL DEFAULT '',  -- 密钥的 SHA-256 哈希
+                                      `scopes` VARCHAR(255) NOT NULL DEFAULT '',  -- 权限范围，空格分隔，为空表示不受限
+                                      `expire_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 过期时间戳，0表示永不过期
+                                      `last_used_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 最后使用时间戳
+                                      `revoked_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 撤销时间戳，0表示未撤销
+                                      `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
+                                      PRIMARY KEY (`id`),  -- 设置 `id` 为主键
+                                      UNIQUE INDEX `index_key_hash` (`key_hash` ASC) VISIBLE,  -- 通过哈希查找密钥
+                                      INDEX `index_user_id` (`user_id` ASC) VISIBLE)  -- 在 `user_id` 字段上创建索引
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = 'API Key表';  -- 表注释，说明该表用于存储用户的 API Key
+
+-- 创建 `rbac_role` 表，用于存储管理角色
+CREATE TABLE `mediahub`.`rbac_role` (
+                                        `id` BIGINT(20) NOT NULL AUTO_INCREMENT,  -- 主键，自增ID
+                                        `name` VARCHAR(64) NOT NULL DEFAULT '',  -- 角色名称，唯一
+                                        `description` VARCHAR(255) NOT NULL DEFAULT '',  -- 角色说明
+                                        `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录创建时间的时间戳
+                                        `update_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录最后一次更新时间的时间戳
+                                        PRIMARY KEY (`id`),  -- 设置 `id` 为主键
+                                        UNIQUE INDEX `index_name` (`name` ASC) VISIBLE)  -- 角色名称唯一
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = '角色表';  -- 表注释，说明该表用于存储管理角色
+
+-- 创建 `rbac_role_permission` 表，用于存储角色拥有的权限
+CREATE TABLE `mediahub`.`rbac_role_permission` (
+                                                   `role_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 角色ID
+                                                   `permission` VARCHAR(64) NOT NULL DEFAULT '',  -- 权限标识，如 stats:view，* 表示全部权限
+                                                   PRIMARY KEY (`role_id`, `permission`))  -- 同一角色的权限不重复
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = '角色权限表';  -- 表注释，说明该表用于存储角色拥有的权限
+
+-- 创建 `rbac_user_role` 表，用于存储用户被授予的角色
+CREATE TABLE `mediahub`.`rbac_user_role` (
+                                             `user_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 用户ID
+                                             `role_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 角色ID
+                                             `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 授权时间的时间戳
+                                             PRIMARY KEY (`user_id`, `role_id`),  -- 同一用户的角色不重复
+                                             INDEX `index_role_id` (`role_id` ASC) VISIBLE)  -- 按角色查询用户
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = '用户角色表';  -- 表注释，说明该表用于存储用户被授予的角色
+
+-- 内置管理员角色，拥有全部权限，首个管理员需要直接在数据库中授予
+INSERT INTO `mediahub`.`rbac_role` (`name`, `description`) VALUES ('admin', '管理员');
+INSERT INTO `mediahub`.`rbac_role_permission` (`role_id`, `permission`) SELECT `id`, '*' FROM `mediahub`.`rbac_role` WHERE `name` = 'admin';
+
+-- 创建 `audit_log` 表，用于存储 mediahub 与短链服务的审计记录，只追加不修改
+CREATE TABLE `mediahub`.`audit_log` (
+                                       `id` BIGINT(20) NOT NULL AUTO_INCREMENT,  -- 主键，自增ID
+                                       `service` VARCHAR(32) NOT NULL DEFAULT '',  -- 记录来源服务：mediahub、shorturl
+                                       `actor_type` VARCHAR(16) NOT NULL DEFAULT '',  -- 操作者类型：anonymous、user、api_key、client
+                                       `actor_id` BIGINT(20) NOT NULL DEFAULT 0,  -- 操作用户ID，没有用户时为0
+                                       `actor_name` VARCHAR(128) NOT NULL DEFAULT '',  -- 操作者名称
+                                       `client` VARCHAR(64) NOT NULL DEFAULT '',  -- 经由的内部调用方
+                                       `action` VARCHAR(32) NOT NULL DEFAULT '',  -- 操作，如 media.upload、link.create、auth.failure
+                                       `target_type` VARCHAR(32) NOT NULL DEFAULT '',  -- 操作对象类型
+                                       `target_id` VARCHAR(128) NOT NULL DEFAULT '',  -- 操作对象ID
+                                       `ip` VARCHAR(64) NOT NULL DEFAULT '',  -- 客户端IP
+                                       `user_agent` VARCHAR(255) NOT NULL DEFAULT '',  -- 客户端 User-Agent
+                                       `outcome` VARCHAR(16) NOT NULL DEFAULT '',  -- 结果：success、failure、denied
+                                       `detail` VARCHAR(1024) NOT NULL DEFAULT '',  -- 补充说明
+                                       `create_at` BIGINT(64) NOT NULL DEFAULT 0,  -- 记录时间的时间戳
+                                       PRIMARY KEY (`id`),  -- 设置 `id` 为主键
+                                       INDEX `index_actor` (`actor_id` ASC, `id` ASC) VISIBLE,  -- 按操作用户查询
+                                       INDEX `index_target` (`target_type` ASC, `target_id` ASC, `id` ASC) VISIBLE,  -- 按操作对象查询
+                                       INDEX `index_create_at` (`create_at` ASC) VISIBLE)  -- 按时间范围查询与导出
+    ENGINE = InnoDB  -- 使用 InnoDB 存储引擎
+DEFAULT CHARACTER SET = utf8mb4  -- 设置默认字符集为 `utf8mb4`
+COMMENT = '审计日志表';  -- 表注释，说明该表用于存储审计记录
+
+-- 审计记录只追加，禁止修改与删除；应用账号也只应授予该表的 INSERT、SELECT 权限
+CREATE TRIGGER `mediahub`.`audit_log_no_update` BEFORE UPDATE ON `mediahub`.`audit_log`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+CREATE TRIGGER `mediahub`.`audit_log_no_delete` BEFORE DELETE ON `mediahub`.`audit_log`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
 /*
  ### 面试场景：SQL 表结构设计与理解
 
@@ -0,0 +1,19 @@
+-- 媒体表，私有媒体与签名访问地址上线时新增
+-- 结构为后续升级脚本执行前的初始版本，依次执行 001 及之后的脚本后与 create_db.sql 一致
+CREATE TABLE IF NOT EXISTS `mediahub`.`media` (
+    `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+    `user_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '所属用户ID，0表示公共上传',
+    `md5` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '文件内容的md5',
+    `object_path` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '对象存储中的路径',
+    `url` VARCHAR(512) NOT NULL DEFAULT '' COMMENT '公开访问地址，私有媒体为空',
+    `short_url` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '短链接',
+    `visibility` TINYINT NOT NULL DEFAULT 0 COMMENT '可见性：0公开，1私有',
+    `sign_version` INT NOT NULL DEFAULT 0 COMMENT '签名版本，撤销访问时递增',
+    `revoked` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否已撤销访问',
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '记录创建时间的时间戳',
+    `update_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '记录最后一次更新时间的时间戳',
+    PRIMARY KEY (`id`),
+    INDEX `index_user_id` (`user_id` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '媒体表';
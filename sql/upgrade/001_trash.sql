@@ -0,0 +1,12 @@
+-- 短链增加状态字段，媒体彻底清除时禁用对应短链
+ALTER TABLE `mediahub`.`url_map`
+    ADD COLUMN `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态：0正常，1禁用' AFTER `times`;
+
+ALTER TABLE `mediahub`.`url_map_user`
+    ADD COLUMN `status` TINYINT NOT NULL DEFAULT 0 COMMENT '状态：0正常，1禁用' AFTER `times`;
+
+-- 媒体增加回收站字段
+ALTER TABLE `mediahub`.`media`
+    ADD COLUMN `deleted_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '移入回收站的时间戳，0表示未删除' AFTER `revoked`,
+    ADD COLUMN `purge_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '计划彻底清除的时间戳' AFTER `deleted_at`,
+    ADD INDEX `index_purge_at` (`purge_at` ASC) VISIBLE;
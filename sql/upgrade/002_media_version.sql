@@ -0,0 +1,23 @@
+-- 媒体增加当前版本号
+ALTER TABLE `mediahub`.`media`
+    ADD COLUMN `version` INT NOT NULL DEFAULT 1 COMMENT '当前版本号' AFTER `visibility`;
+
+-- 媒体版本表
+CREATE TABLE `mediahub`.`media_version` (
+    `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+    `media_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '所属媒体ID',
+    `version` INT NOT NULL DEFAULT 0 COMMENT '版本号，从1开始递增',
+    `md5` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '该版本文件内容的md5',
+    `object_path` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '该版本在对象存储中的路径',
+    `url` VARCHAR(512) NOT NULL DEFAULT '' COMMENT '该版本的公开访问地址，私有媒体为空',
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `index_media_version` (`media_id` ASC, `version` ASC) VISIBLE,
+    INDEX `index_object_path` (`object_path` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '媒体版本表';
+
+-- 已有媒体补齐第1个版本
+INSERT INTO `mediahub`.`media_version` (`media_id`, `version`, `md5`, `object_path`, `url`, `create_at`)
+SELECT `id`, 1, `md5`, `object_path`, `url`, `create_at` FROM `mediahub`.`media`;
@@ -0,0 +1,22 @@
+-- 媒体及媒体版本增加原图路径，加水印时原图以私有对象保存
+ALTER TABLE `mediahub`.`media`
+    ADD COLUMN `original_path` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '未加水印的原图路径（私有对象），未加水印时为空' AFTER `object_path`;
+
+ALTER TABLE `mediahub`.`media_version`
+    ADD COLUMN `original_path` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '该版本未加水印的原图路径，未加水印时为空' AFTER `object_path`;
+
+-- 用户水印设置表
+CREATE TABLE `mediahub`.`watermark` (
+    `user_id` BIGINT(20) NOT NULL,
+    `enable` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否为公开上传添加水印',
+    `text` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '文字水印',
+    `image_path` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '图片水印在对象存储中的路径，为空时使用文字水印',
+    `position` VARCHAR(16) NOT NULL DEFAULT 'bottom-right' COMMENT '水印位置',
+    `opacity` DOUBLE NOT NULL DEFAULT 0.5 COMMENT '不透明度',
+    `scale` DOUBLE NOT NULL DEFAULT 0.2 COMMENT '水印宽度占原图宽度的比例',
+    `margin` INT NOT NULL DEFAULT 0 COMMENT '水印与图片边缘的距离（像素）',
+    `update_at` BIGINT(64) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`user_id`))
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '水印设置表';
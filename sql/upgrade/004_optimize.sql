@@ -0,0 +1,8 @@
+-- 媒体及媒体版本记录文件大小与上传优化节省的字节数
+ALTER TABLE `mediahub`.`media`
+    ADD COLUMN `size` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '文件大小（字节）' AFTER `url`,
+    ADD COLUMN `saved_bytes` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '上传优化节省的字节数' AFTER `size`;
+
+ALTER TABLE `mediahub`.`media_version`
+    ADD COLUMN `size` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '该版本文件大小（字节）' AFTER `url`,
+    ADD COLUMN `saved_bytes` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '该版本上传优化节省的字节数' AFTER `size`;
@@ -0,0 +1,18 @@
+-- API Key 表，只保存密钥哈希
+CREATE TABLE `mediahub`.`api_key` (
+    `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+    `user_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '所属用户ID',
+    `name` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '名称',
+    `display` VARCHAR(16) NOT NULL DEFAULT '' COMMENT '密钥开头的若干字符，用于辨认密钥',
+    `key_hash` CHAR(64) NOT NULL DEFAULT '' COMMENT '密钥的 SHA-256 哈希',
+    `scopes` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '权限范围，空格分隔，为空表示不受限',
+    `expire_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '过期时间戳，0表示永不过期',
+    `last_used_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '最后使用时间戳',
+    `revoked_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '撤销时间戳，0表示未撤销',
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `index_key_hash` (`key_hash` ASC) VISIBLE,
+    INDEX `index_user_id` (`user_id` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = 'API Key表';
@@ -0,0 +1,37 @@
+-- 角色表
+CREATE TABLE `mediahub`.`rbac_role` (
+    `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+    `name` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '角色名称，唯一',
+    `description` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '角色说明',
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0,
+    `update_at` BIGINT(64) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `index_name` (`name` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '角色表';
+
+-- 角色权限表
+CREATE TABLE `mediahub`.`rbac_role_permission` (
+    `role_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '角色ID',
+    `permission` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '权限标识，如 stats:view，* 表示全部权限',
+    PRIMARY KEY (`role_id`, `permission`))
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '角色权限表';
+
+-- 用户角色表
+CREATE TABLE `mediahub`.`rbac_user_role` (
+    `user_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '用户ID',
+    `role_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '角色ID',
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '授权时间戳',
+    PRIMARY KEY (`user_id`, `role_id`),
+    INDEX `index_role_id` (`role_id` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '用户角色表';
+
+-- 内置管理员角色，拥有全部权限
+-- 首个管理员需要手动授予：INSERT INTO `mediahub`.`rbac_user_role` (`user_id`, `role_id`) SELECT <用户ID>, `id` FROM `mediahub`.`rbac_role` WHERE `name` = 'admin';
+INSERT INTO `mediahub`.`rbac_role` (`name`, `description`) VALUES ('admin', '管理员');
+INSERT INTO `mediahub`.`rbac_role_permission` (`role_id`, `permission`) SELECT `id`, '*' FROM `mediahub`.`rbac_role` WHERE `name` = 'admin';
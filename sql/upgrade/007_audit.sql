@@ -0,0 +1,30 @@
+-- 审计日志表，记录 mediahub 与短链服务的安全相关操作，只追加不修改
+CREATE TABLE `mediahub`.`audit_log` (
+    `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+    `service` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '记录来源服务：mediahub、shorturl',
+    `actor_type` VARCHAR(16) NOT NULL DEFAULT '' COMMENT '操作者类型：anonymous、user、api_key、client',
+    `actor_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '操作用户ID，没有用户时为0',
+    `actor_name` VARCHAR(128) NOT NULL DEFAULT '' COMMENT '操作者名称',
+    `client` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '经由的内部调用方',
+    `action` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '操作，如 media.upload、link.create、auth.failure',
+    `target_type` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '操作对象类型',
+    `target_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT '操作对象ID',
+    `ip` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+    `user_agent` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '客户端 User-Agent',
+    `outcome` VARCHAR(16) NOT NULL DEFAULT '' COMMENT '结果：success、failure、denied',
+    `detail` VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '补充说明',
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `index_actor` (`actor_id` ASC, `id` ASC) VISIBLE,
+    INDEX `index_target` (`target_type` ASC, `target_id` ASC, `id` ASC) VISIBLE,
+    INDEX `index_create_at` (`create_at` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '审计日志表';
+
+-- 审计记录只追加，禁止修改与删除
+-- 应用账号也只应授予该表的 INSERT、SELECT 权限，例如：GRANT INSERT, SELECT ON `mediahub`.`audit_log` TO '<账号>'@'%';
+CREATE TRIGGER `mediahub`.`audit_log_no_update` BEFORE UPDATE ON `mediahub`.`audit_log`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+CREATE TRIGGER `mediahub`.`audit_log_no_delete` BEFORE DELETE ON `mediahub`.`audit_log`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
@@ -0,0 +1,9 @@
+-- 短链支持自定义别名，别名同时写入 short_key，生成的短链该字段为NULL
+-- 唯一索引按表生效：公共短链与用户短链各自保证别名唯一
+ALTER TABLE `mediahub`.`url_map`
+    ADD COLUMN `alias` VARCHAR(45) NULL DEFAULT NULL COMMENT '自定义别名' AFTER `short_key`,
+    ADD UNIQUE INDEX `unique_alias` (`alias` ASC) VISIBLE;
+
+ALTER TABLE `mediahub`.`url_map_user`
+    ADD COLUMN `alias` VARCHAR(45) NULL DEFAULT NULL COMMENT '自定义别名' AFTER `short_key`,
+    ADD UNIQUE INDEX `unique_alias` (`alias` ASC) VISIBLE;
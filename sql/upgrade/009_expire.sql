@@ -0,0 +1,48 @@
+-- 短链支持按时间与访问次数过期，过期一段时间后由 shorturl-crontab 移入归档表
+ALTER TABLE `mediahub`.`url_map`
+    ADD COLUMN `expire_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '过期时间戳，0表示永不过期' AFTER `status`,
+    ADD COLUMN `max_clicks` INT NOT NULL DEFAULT 0 COMMENT '最大访问次数，0表示不限制' AFTER `expire_at`,
+    ADD INDEX `index_expire_at` (`expire_at` ASC) VISIBLE;
+
+ALTER TABLE `mediahub`.`url_map_user`
+    ADD COLUMN `expire_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '过期时间戳，0表示永不过期' AFTER `status`,
+    ADD COLUMN `max_clicks` INT NOT NULL DEFAULT 0 COMMENT '最大访问次数，0表示不限制' AFTER `expire_at`,
+    ADD INDEX `index_expire_at` (`expire_at` ASC) VISIBLE;
+
+-- 归档表，沿用原记录ID，不保留别名唯一索引，别名归档后可以被重新使用
+CREATE TABLE `mediahub`.`url_map_archive` (
+    `id` BIGINT(20) NOT NULL,
+    `short_key` VARCHAR(45) NOT NULL DEFAULT '',
+    `alias` VARCHAR(45) NULL DEFAULT NULL,
+    `original_url` VARCHAR(512) NOT NULL DEFAULT '',
+    `times` INT NOT NULL DEFAULT 0,
+    `status` TINYINT NOT NULL DEFAULT 0,
+    `expire_at` BIGINT(64) NOT NULL DEFAULT 0,
+    `max_clicks` INT NOT NULL DEFAULT 0,
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0,
+    `update_at` BIGINT(64) NOT NULL DEFAULT 0,
+    `archive_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '归档时间戳',
+    PRIMARY KEY (`id`),
+    INDEX `index_short_key` (`short_key` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = 'url关系归档表';
+
+CREATE TABLE `mediahub`.`url_map_user_archive` (
+    `id` BIGINT(20) NOT NULL,
+    `user_id` BIGINT(20) NOT NULL DEFAULT 0,
+    `short_key` VARCHAR(45) NOT NULL DEFAULT '',
+    `alias` VARCHAR(45) NULL DEFAULT NULL,
+    `original_url` VARCHAR(512) NOT NULL DEFAULT '',
+    `times` INT NOT NULL DEFAULT 0,
+    `status` TINYINT NOT NULL DEFAULT 0,
+    `expire_at` BIGINT(64) NOT NULL DEFAULT 0,
+    `max_clicks` INT NOT NULL DEFAULT 0,
+    `create_at` BIGINT(64) NOT NULL DEFAULT 0,
+    `update_at` BIGINT(64) NOT NULL DEFAULT 0,
+    `archive_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '归档时间戳',
+    PRIMARY KEY (`id`),
+    INDEX `index_user_id` (`user_id` ASC) VISIBLE)
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = '用户url关系归档表';
@@ -0,0 +1,3 @@
+-- 按用户分页查询短链列表
+ALTER TABLE `mediahub`.`url_map_user`
+    ADD INDEX `index_user_id` (`user_id` ASC, `id` ASC) VISIBLE;
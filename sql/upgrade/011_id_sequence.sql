@@ -0,0 +1,15 @@
+-- 短链记录ID改为由ID生成器分配，创建时只写入一次，不再依赖自增ID
+CREATE TABLE `mediahub`.`id_sequence` (
+    `name` VARCHAR(45) NOT NULL COMMENT '序列名称，使用短链表名',
+    `max_id` BIGINT(20) NOT NULL DEFAULT 0 COMMENT '已预留的最大ID',
+    `update_at` BIGINT(64) NOT NULL DEFAULT 0 COMMENT '最后一次预留号段的时间戳',
+    PRIMARY KEY (`name`))
+    ENGINE = InnoDB
+DEFAULT CHARACTER SET = utf8mb4
+COMMENT = 'ID号段表';
+
+-- 序列从现有的最大ID开始，已签发的ID不会被再次分配
+INSERT INTO `mediahub`.`id_sequence` (`name`, `max_id`, `update_at`)
+    SELECT 'url_map', IFNULL(MAX(`id`), 0), UNIX_TIMESTAMP() FROM `mediahub`.`url_map`;
+INSERT INTO `mediahub`.`id_sequence` (`name`, `max_id`, `update_at`)
+    SELECT 'url_map_user', IFNULL(MAX(`id`), 0), UNIX_TIMESTAMP() FROM `mediahub`.`url_map_user`;
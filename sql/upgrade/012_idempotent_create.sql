@@ -0,0 +1,13 @@
+-- 创建短链去重与幂等：可复用的短链写入原始URL的哈希，由唯一索引保证同一范围内只有一条
+-- 已有记录的 url_hash 为空，仍按 original_url 查询复用
+ALTER TABLE `mediahub`.`url_map`
+    ADD COLUMN `url_hash` BINARY(16) NULL DEFAULT NULL COMMENT '原始URL的 SHA-256 前16字节，只有可复用的短链有值，用于去重' AFTER `original_url`,
+    ADD COLUMN `idempotency_key` VARCHAR(64) NULL DEFAULT NULL COMMENT '调用方提供的幂等键' AFTER `url_hash`,
+    ADD UNIQUE INDEX `unique_url_hash` (`url_hash` ASC) VISIBLE,
+    ADD UNIQUE INDEX `unique_idempotency` (`idempotency_key` ASC) VISIBLE;
+
+ALTER TABLE `mediahub`.`url_map_user`
+    ADD COLUMN `url_hash` BINARY(16) NULL DEFAULT NULL COMMENT '原始URL的 SHA-256 前16字节，只有可复用的短链有值，用于去重' AFTER `original_url`,
+    ADD COLUMN `idempotency_key` VARCHAR(64) NULL DEFAULT NULL COMMENT '调用方提供的幂等键' AFTER `url_hash`,
+    ADD UNIQUE INDEX `unique_url_hash` (`user_id` ASC, `url_hash` ASC) VISIBLE,
+    ADD UNIQUE INDEX `unique_idempotency` (`user_id` ASC, `idempotency_key` ASC) VISIBLE;
@@ -0,0 +1,23 @@
+-- 按原始URL哈希查询可复用的短链，original_url 改为 TEXT 以保存超过512个字符的URL
+-- 先回填哈希再升级 shorturl-server，未回填的记录不会被复用
+
+-- 回填不能直接用 SQL 对 original_url 计算哈希：shorturl-server 按规范化后的原始URL计算哈希并比较原始URL，
+-- 主机名大小写、默认端口、查询参数顺序不同的旧记录永远匹配不上，会重复创建短链。
+-- 使用与 shorturl-server 相同的配置运行回填工具，先把原始URL改写为规范形式再写入哈希，可以重复执行：
+--   cd shorturl && go run ./shorturl-backfill -config <shorturl-server 的配置文件>
+-- 同一范围内规范化后相同的记录只有ID最小的一条写入哈希，其余不再参与复用
+
+-- 不再按 original_url 查询，删除索引后改为 TEXT
+ALTER TABLE `mediahub`.`url_map`
+    DROP INDEX `index_original_url`,
+    MODIFY COLUMN `original_url` TEXT NOT NULL COMMENT '原始URL';
+
+ALTER TABLE `mediahub`.`url_map_user`
+    DROP INDEX `index_original_url`,
+    MODIFY COLUMN `original_url` TEXT NOT NULL COMMENT '原始URL';
+
+ALTER TABLE `mediahub`.`url_map_archive`
+    MODIFY COLUMN `original_url` TEXT NOT NULL COMMENT '原始URL';
+
+ALTER TABLE `mediahub`.`url_map_user_archive`
+    MODIFY COLUMN `original_url` TEXT NOT NULL COMMENT '原始URL';